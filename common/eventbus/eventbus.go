@@ -0,0 +1,126 @@
+// Package eventbus carries click events from url-api to analytics-consumer
+// over Kafka, an in-process channel or a Postgres table, selected by config.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// DriverKafka publishes to a Kafka topic.
+	DriverKafka = "kafka"
+	// DriverMemory hands events over a channel inside one process. Only the
+	// allinone binary, which runs producer and consumer together, selects it;
+	// config files cannot.
+	DriverMemory = "memory"
+	// DriverPostgres queues events in the event_queue table.
+	DriverPostgres = "postgres"
+)
+
+// ErrNoDataSource is returned when the postgres driver has no data source.
+var ErrNoDataSource = errors.New("eventbus: postgres driver requires a data source")
+
+// Conf selects the event bus driver. Kafka settings, the topic and the number
+// of concurrent handlers come from the service's kq configuration.
+type Conf struct {
+	Driver string `json:",default=kafka,options=kafka|postgres"`
+	// DataSource of the postgres driver; services default it to their own.
+	DataSource   string `json:",optional"`
+	PollInterval int    `json:",default=100"`   // postgres: milliseconds between polls of an empty queue
+	BatchSize    int    `json:",default=100"`   // postgres: events claimed per poll
+	RetryBackoff int    `json:",default=1000"`  // postgres: milliseconds before a failed event is retried, doubling per attempt
+	MaxBackoff   int    `json:",default=60000"` // postgres: milliseconds
+	Lease        int    `json:",default=30000"` // postgres: milliseconds a claimed event stays hidden while its handler runs
+	MaxAttempts  int    `json:",default=10"`    // postgres: deliveries before a failing event is dead-lettered; 0 retries forever
+	BufferSize   int    `json:",default=10000"` // memory: events held per topic
+	// BatchTimeout is how long, in milliseconds, a kafka push waits for
+	// concurrent pushes to share its produce request. Pushes are synchronous,
+	// so it bounds the pushes per second of each caller.
+	BatchTimeout int `json:",default=5"`
+}
+
+// Message is an event and the headers it travels with, such as its content
+// type. The key is set by the bus on push.
+type Message struct {
+	Key     string
+	Value   string
+	Headers map[string]string
+}
+
+// Handler consumes one event. The context carries the trace context the
+// event was pushed with.
+type Handler interface {
+	Consume(ctx context.Context, msg Message) error
+}
+
+// EventBus publishes events to and consumes events from one topic.
+type EventBus interface {
+	// Push publishes msgs along with the trace context of ctx and reports
+	// whether they were accepted.
+	Push(ctx context.Context, msgs ...Message) error
+	// Subscribe returns a service delivering events to handler until stopped.
+	Subscribe(handler Handler) (service.Service, error)
+	// Close releases the resources used to push.
+	Close() error
+}
+
+// New returns the event bus selected by c for the topic in kc.
+func New(c Conf, kc kq.KqConf) (EventBus, error) {
+	switch c.Driver {
+	case DriverKafka, "":
+		bus, err := newKafkaBus(kc, time.Duration(c.BatchTimeout)*time.Millisecond)
+		if err != nil {
+			return nil, err
+		}
+		return bus, nil
+	case DriverMemory:
+		return newMemoryBus(kc.Topic, c.BufferSize, kc.Processors), nil
+	case DriverPostgres:
+		if c.DataSource == "" {
+			return nil, ErrNoDataSource
+		}
+		return NewPostgresBus(sqlx.NewSqlConn("postgres", c.DataSource), c, kc), nil
+	default:
+		return nil, fmt.Errorf("eventbus: unknown driver %q", c.Driver)
+	}
+}
+
+// MustNew is like New but exits on error.
+func MustNew(c Conf, kc kq.KqConf) EventBus {
+	bus, err := New(c, kc)
+	logx.Must(err)
+	return bus
+}
+
+// newKey returns the message key go-queue uses for unkeyed pushes.
+func newKey() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// injectHeaders returns the headers of msg along with the trace context the
+// global propagator derives from ctx, as go-queue does for Kafka messages.
+func injectHeaders(ctx context.Context, msg Message) map[string]string {
+	headers := make(map[string]string, len(msg.Headers)+2)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return headers
+}
+
+// extractHeaders returns parent carrying the trace context the global
+// propagator reads from headers.
+func extractHeaders(parent context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(parent, propagation.MapCarrier(headers))
+}
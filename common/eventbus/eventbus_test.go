@@ -0,0 +1,163 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shortener/common/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/conf"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingHandler records consumed values and their content types.
+type recordingHandler struct {
+	mu           sync.Mutex
+	values       []string
+	contentTypes []string
+}
+
+func (h *recordingHandler) Consume(ctx context.Context, msg Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.values = append(h.values, msg.Value)
+	h.contentTypes = append(h.contentTypes, msg.Headers[events.HeaderContentType])
+	return nil
+}
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.values)
+}
+
+// topicName returns a memory bus topic no other test or test run shares.
+func topicName(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+}
+
+func TestNew_SelectsDriver(t *testing.T) {
+	kc := kq.KqConf{Brokers: []string{"localhost:9092"}, Topic: topicName(t), Processors: 1}
+
+	bus, err := New(Conf{Driver: DriverKafka, BatchTimeout: 5}, kc)
+	require.NoError(t, err)
+	if assert.IsType(t, &kafkaBus{}, bus) {
+		assert.Equal(t, 5*time.Millisecond, bus.(*kafkaBus).writer.BatchTimeout)
+	}
+	require.NoError(t, bus.Close())
+
+	bus, err = New(Conf{Driver: DriverMemory, BufferSize: 1}, kc)
+	require.NoError(t, err)
+	assert.IsType(t, &memoryBus{}, bus)
+
+	_, err = New(Conf{Driver: DriverPostgres}, kc)
+	assert.ErrorIs(t, err, ErrNoDataSource)
+
+	_, err = New(Conf{Driver: "nats"}, kc)
+	assert.Error(t, err)
+}
+
+func TestMemoryBus_DeliversAcrossBusesOfATopic(t *testing.T) {
+	kc := kq.KqConf{Topic: topicName(t), Processors: 2}
+	producer := MustNew(Conf{Driver: DriverMemory, BufferSize: 10}, kc)
+	consumerBus := MustNew(Conf{Driver: DriverMemory, BufferSize: 10}, kc)
+
+	headers := map[string]string{events.HeaderContentType: events.ContentTypeProtobuf}
+	require.NoError(t, producer.Push(context.Background(),
+		Message{Value: "a", Headers: headers}, Message{Value: "b", Headers: headers}))
+
+	handler := &recordingHandler{}
+	consumer, err := consumerBus.Subscribe(handler)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		consumer.Start()
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return handler.count() == 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, producer.Close())
+	consumer.Stop()
+	<-done
+
+	assert.ElementsMatch(t, []string{"a", "b"}, handler.values)
+	assert.Equal(t, []string{events.ContentTypeProtobuf, events.ContentTypeProtobuf}, handler.contentTypes)
+}
+
+func TestMemoryBus_StoppedConsumerDrainsUntilProducersClose(t *testing.T) {
+	kc := kq.KqConf{Topic: topicName(t), Processors: 1}
+	producer := MustNew(Conf{Driver: DriverMemory, BufferSize: 10}, kc)
+	require.NoError(t, producer.Push(context.Background(), Message{Value: "a"}))
+
+	handler := &recordingHandler{}
+	consumer, err := MustNew(Conf{Driver: DriverMemory, BufferSize: 10}, kc).Subscribe(handler)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		consumer.Start()
+		close(done)
+	}()
+	consumer.Stop()
+
+	// The producer is still flushing
+	require.NoError(t, producer.Push(context.Background(), Message{Value: "b"}))
+	select {
+	case <-done:
+		t.Fatal("consumer stopped before the producer closed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, producer.Push(context.Background(), Message{Value: "c"}))
+	require.NoError(t, producer.Close())
+	<-done
+	assert.Equal(t, []string{"a", "b", "c"}, handler.values)
+}
+
+func TestMemoryBus_PushWaitsForBufferSpace(t *testing.T) {
+	bus := MustNew(Conf{Driver: DriverMemory, BufferSize: 1}, kq.KqConf{Topic: topicName(t)})
+	require.NoError(t, bus.Push(context.Background(), Message{Value: "a"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bus.Push(ctx, Message{Value: "b"}), context.DeadlineExceeded)
+}
+
+func TestMemoryBus_CarriesTraceContextWithHeaders(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	kc := kq.KqConf{Topic: topicName(t), Processors: 1}
+	bus := MustNew(Conf{Driver: DriverMemory, BufferSize: 1}, kc)
+	t.Cleanup(func() { _ = bus.Close() })
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	headers := map[string]string{events.HeaderContentType: events.ContentTypeJSON}
+	require.NoError(t, bus.Push(ctx, Message{Value: "a", Headers: headers}))
+
+	msg := <-bus.(*memoryBus).topic.messages
+	assert.Equal(t, events.ContentTypeJSON, msg.Headers[events.HeaderContentType])
+	assert.Contains(t, msg.Headers, "traceparent")
+	assert.Len(t, headers, 1, "the pushed headers are not modified")
+	assert.Equal(t, sc.TraceID(), trace.SpanContextFromContext(extractHeaders(context.Background(), msg.Headers)).TraceID())
+}
+
+func TestConf_RejectsMemoryDriver(t *testing.T) {
+	var c Conf
+	assert.Error(t, conf.LoadFromYamlBytes([]byte("Driver: memory"), &c), "only allinone selects the memory driver")
+	require.NoError(t, conf.LoadFromYamlBytes([]byte("Driver: postgres"), &c))
+	assert.Equal(t, DriverPostgres, c.Driver)
+}
@@ -0,0 +1,368 @@
+package eventbus
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logc"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const (
+	kafkaFirstOffset    = "first"
+	kafkaCommitInterval = time.Second
+	kafkaMaxWait        = time.Second
+	kafkaQueueCapacity  = 1000
+)
+
+// kafkaBus pushes with a synchronous kafka-go writer, so failures are
+// reported to the caller, and consumes through a consumer group reader. It
+// follows go-queue's kq settings and defaults, but carries message headers
+// both ways.
+type kafkaBus struct {
+	conf   kq.KqConf
+	writer *kafka.Writer
+}
+
+func newKafkaBus(kc kq.KqConf, batchTimeout time.Duration) (*kafkaBus, error) {
+	transport, err := newKafkaTransport(kc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafkaBus{
+		conf: kc,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(kc.Brokers...),
+			Topic:        kc.Topic,
+			Balancer:     &kafka.LeastBytes{},
+			Compression:  kafka.Snappy,
+			BatchTimeout: batchTimeout,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+// Push writes msgs in one request and waits until the brokers accepted them.
+func (b *kafkaBus) Push(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	kms := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		km := kafka.Message{Key: []byte(newKey()), Value: []byte(msg.Value)}
+		for k, v := range injectHeaders(ctx, msg) {
+			km.Headers = append(km.Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+		kms = append(kms, km)
+	}
+	return b.writer.WriteMessages(ctx, kms...)
+}
+
+func (b *kafkaBus) Subscribe(handler Handler) (service.Service, error) {
+	dialer, err := newKafkaDialer(b.conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKafkaConsumer(b.conf, dialer, handler), nil
+}
+
+func (b *kafkaBus) Close() error {
+	return b.writer.Close()
+}
+
+// kafkaConsumer fetches with kq's Consumers goroutines and hands messages to
+// Processors goroutines, like a kq queue with a single connection. With
+// CommitInOrder, offsets are committed only up to the first message of each
+// partition still being handled, so concurrent processors never commit past
+// an unhandled message.
+type kafkaConsumer struct {
+	conf     kq.KqConf
+	reader   *kafka.Reader
+	handler  Handler
+	messages chan kafka.Message
+	offsets  *offsetTracker
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+func newKafkaConsumer(kc kq.KqConf, dialer *kafka.Dialer, handler Handler) *kafkaConsumer {
+	offset := kafka.LastOffset
+	if kc.Offset == kafkaFirstOffset {
+		offset = kafka.FirstOffset
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &kafkaConsumer{
+		conf: kc,
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:        kc.Brokers,
+			GroupID:        kc.Group,
+			Topic:          kc.Topic,
+			Dialer:         dialer,
+			StartOffset:    offset,
+			MinBytes:       kc.MinBytes,
+			MaxBytes:       kc.MaxBytes,
+			MaxWait:        kafkaMaxWait,
+			CommitInterval: kafkaCommitInterval,
+			QueueCapacity:  kafkaQueueCapacity,
+		}),
+		handler:  handler,
+		messages: make(chan kafka.Message),
+		offsets:  newOffsetTracker(kc.ForceCommit),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start blocks until Stop is called and every fetched message is handled.
+func (c *kafkaConsumer) Start() {
+	c.running.Add(1)
+	defer c.running.Done()
+
+	processors := threading.NewRoutineGroup()
+	for i := 0; i < max(c.conf.Processors, 1); i++ {
+		processors.Run(func() {
+			for msg := range c.messages {
+				c.handle(msg)
+			}
+		})
+	}
+
+	fetchers := threading.NewRoutineGroup()
+	for i := 0; i < max(c.conf.Consumers, 1); i++ {
+		fetchers.Run(c.fetch)
+	}
+
+	fetchers.Wait()
+	close(c.messages)
+	processors.Wait()
+	logx.Infof("Consumer %s is closed", c.conf.Name)
+}
+
+// Stop stops fetching, waits for the fetched messages to be handled and
+// flushes their commits.
+func (c *kafkaConsumer) Stop() {
+	c.cancel()
+	c.running.Wait()
+	if err := c.reader.Close(); err != nil {
+		logx.Errorf("close consumer %s, error: %v", c.conf.Name, err)
+	}
+}
+
+func (c *kafkaConsumer) fetch() {
+	for {
+		msg, err := c.reader.FetchMessage(c.ctx)
+		if c.ctx.Err() != nil || err == io.EOF || errors.Is(err, io.ErrClosedPipe) {
+			return
+		}
+		if err != nil {
+			logx.Errorf("Error on reading message, %q", err.Error())
+			continue
+		}
+
+		if c.conf.CommitInOrder {
+			c.offsets.fetched(msg)
+		}
+		select {
+		case c.messages <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// handle consumes msg and commits its offset unless the handler failed and
+// ForceCommit is off, in which case the message is fetched again after a
+// restart or rebalance. The handler's context is cancelled by Stop, so it
+// can give up waiting and leave the message uncommitted.
+func (c *kafkaConsumer) handle(km kafka.Message) {
+	headers := make(map[string]string, len(km.Headers))
+	for _, h := range km.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	ctx := extractHeaders(c.ctx, headers)
+	msg := Message{Key: string(km.Key), Value: string(km.Value), Headers: headers}
+	err := c.handler.Consume(ctx, msg)
+	if err != nil {
+		logc.Errorf(ctx, "consume: %s, error: %v", msg.Value, err)
+	}
+
+	if c.conf.CommitInOrder {
+		var ok bool
+		if km, ok = c.offsets.handled(km, err == nil); !ok {
+			return
+		}
+	} else if err != nil && !c.conf.ForceCommit {
+		return
+	}
+
+	if err := c.reader.CommitMessages(context.WithoutCancel(ctx), km); err != nil {
+		logc.Errorf(ctx, "commit failed, error: %v", err)
+	}
+}
+
+// offsetTracker finds, per partition, the last message below which every
+// fetched message has been handled, so committing it commits nothing that
+// is still in flight. Unless failures are force committed, a failed message
+// stops the commits of its partition until it is fetched again after a
+// restart or rebalance.
+type offsetTracker struct {
+	forceCommit bool
+
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	committed int64           // offset of the last message returned for commit
+	inFlight  []kafka.Message // fetched and not committable yet, by offset
+	handled   map[int64]bool  // handled offsets of inFlight and whether they succeeded
+	failed    bool            // inFlight[0] failed; later messages are not tracked
+}
+
+func newOffsetTracker(forceCommit bool) *offsetTracker {
+	return &offsetTracker{
+		forceCommit: forceCommit,
+		partitions:  make(map[int]*partitionOffsets),
+	}
+}
+
+// fetched registers msg before it is handed to a processor.
+func (t *offsetTracker) fetched(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok || msg.Offset <= p.committed || p.tracks(msg.Offset) {
+		// New, or reassigned and read again from its committed offset: the
+		// messages of the previous assignment must not be committed.
+		p = &partitionOffsets{committed: -1, handled: make(map[int64]bool)}
+		t.partitions[msg.Partition] = p
+	}
+	if p.failed {
+		return
+	}
+
+	i, _ := p.search(msg.Offset)
+	p.inFlight = slices.Insert(p.inFlight, i, msg)
+}
+
+// handled records the outcome of msg and returns the message to commit, if
+// the handled prefix of its partition grew.
+func (t *offsetTracker) handled(msg kafka.Message, succeeded bool) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[msg.Partition]
+	if !ok || p.failed || !p.tracks(msg.Offset) {
+		return kafka.Message{}, false
+	}
+	p.handled[msg.Offset] = succeeded || t.forceCommit
+
+	n := 0
+	for ; n < len(p.inFlight); n++ {
+		succeeded, ok := p.handled[p.inFlight[n].Offset]
+		if !ok {
+			break
+		}
+		if !succeeded {
+			logx.Errorf("partition %d stops committing at offset %d until it is read again",
+				msg.Partition, p.inFlight[n].Offset)
+			p.failed = true
+			break
+		}
+		delete(p.handled, p.inFlight[n].Offset)
+	}
+
+	var last kafka.Message
+	if n > 0 {
+		last = p.inFlight[n-1]
+		p.committed = last.Offset
+		p.inFlight = slices.Delete(p.inFlight, 0, n)
+	}
+	if p.failed {
+		// Nothing after the failed message is committed, so stop tracking.
+		p.inFlight = p.inFlight[:1]
+		p.handled = make(map[int64]bool)
+	}
+	return last, n > 0
+}
+
+func (p *partitionOffsets) search(offset int64) (int, bool) {
+	return slices.BinarySearchFunc(p.inFlight, offset, func(m kafka.Message, offset int64) int {
+		return cmp.Compare(m.Offset, offset)
+	})
+}
+
+func (p *partitionOffsets) tracks(offset int64) bool {
+	_, ok := p.search(offset)
+	return ok
+}
+
+// newKafkaDialer returns the dialer for kq's SASL and CA settings, or nil
+// when neither is set.
+func newKafkaDialer(kc kq.KqConf) (*kafka.Dialer, error) {
+	mechanism, tlsConf, err := kafkaAuth(kc)
+	if err != nil || (mechanism == nil && tlsConf == nil) {
+		return nil, err
+	}
+
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		SASLMechanism: mechanism,
+		TLS:           tlsConf,
+	}, nil
+}
+
+// newKafkaTransport is like newKafkaDialer for writers, which use the
+// default transport when it is nil.
+func newKafkaTransport(kc kq.KqConf) (kafka.RoundTripper, error) {
+	mechanism, tlsConf, err := kafkaAuth(kc)
+	if err != nil || (mechanism == nil && tlsConf == nil) {
+		return nil, err
+	}
+
+	return &kafka.Transport{SASL: mechanism, TLS: tlsConf}, nil
+}
+
+func kafkaAuth(kc kq.KqConf) (mechanism sasl.Mechanism, tlsConf *tls.Config, err error) {
+	if len(kc.Username) > 0 && len(kc.Password) > 0 {
+		mechanism = plain.Mechanism{Username: kc.Username, Password: kc.Password}
+	}
+	if len(kc.CaFile) == 0 {
+		return mechanism, nil, nil
+	}
+
+	caCert, err := os.ReadFile(kc.CaFile)
+	if err != nil {
+		return mechanism, nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return mechanism, nil, fmt.Errorf("eventbus: no certificates in %s", kc.CaFile)
+	}
+
+	return mechanism, &tls.Config{RootCAs: pool}, nil
+}
@@ -0,0 +1,86 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func fetchedMessages(tracker *offsetTracker, partition int, offsets ...int64) []kafka.Message {
+	msgs := make([]kafka.Message, 0, len(offsets))
+	for _, offset := range offsets {
+		msg := kafka.Message{Partition: partition, Offset: offset}
+		tracker.fetched(msg)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func assertCommits(t *testing.T, tracker *offsetTracker, msg kafka.Message, succeeded bool, want int64) {
+	t.Helper()
+	commit, ok := tracker.handled(msg, succeeded)
+	if want < 0 {
+		assert.False(t, ok, "offset %d commits nothing", msg.Offset)
+		return
+	}
+	if assert.True(t, ok, "offset %d commits", msg.Offset) {
+		assert.Equal(t, want, commit.Offset)
+	}
+}
+
+func TestOffsetTracker_CommitsHandledPrefix(t *testing.T) {
+	tracker := newOffsetTracker(false)
+	msgs := fetchedMessages(tracker, 0, 0, 1, 2, 3)
+	other := fetchedMessages(tracker, 1, 7)
+
+	assertCommits(t, tracker, msgs[2], true, -1)
+	assertCommits(t, tracker, msgs[1], true, -1)
+	assertCommits(t, tracker, other[0], true, 7)
+	assertCommits(t, tracker, msgs[0], true, 2)
+	assertCommits(t, tracker, msgs[3], true, 3)
+}
+
+func TestOffsetTracker_FailureStopsCommits(t *testing.T) {
+	tracker := newOffsetTracker(false)
+	msgs := fetchedMessages(tracker, 0, 0, 1, 2)
+
+	assertCommits(t, tracker, msgs[0], true, 0)
+	assertCommits(t, tracker, msgs[2], true, -1)
+	assertCommits(t, tracker, msgs[1], false, -1)
+	later := fetchedMessages(tracker, 0, 3)
+	assertCommits(t, tracker, later[0], true, -1)
+
+	// A rebalance reads the partition again from the failed message
+	again := fetchedMessages(tracker, 0, 1, 2)
+	assertCommits(t, tracker, again[0], true, 1)
+	assertCommits(t, tracker, again[1], true, 2)
+}
+
+func TestOffsetTracker_ForceCommitSkipsFailures(t *testing.T) {
+	tracker := newOffsetTracker(true)
+	msgs := fetchedMessages(tracker, 0, 0, 1)
+
+	assertCommits(t, tracker, msgs[1], true, -1)
+	assertCommits(t, tracker, msgs[0], false, 1)
+}
+
+func TestOffsetTracker_FetchersRegisterOutOfOrder(t *testing.T) {
+	tracker := newOffsetTracker(false)
+	msgs := fetchedMessages(tracker, 0, 1, 0)
+
+	assertCommits(t, tracker, msgs[0], true, -1)
+	assertCommits(t, tracker, msgs[1], true, 1)
+}
+
+func TestOffsetTracker_ForgetsPreviousAssignment(t *testing.T) {
+	tracker := newOffsetTracker(false)
+	msgs := fetchedMessages(tracker, 0, 5, 6)
+	assertCommits(t, tracker, msgs[0], true, 5)
+
+	// Commits are asynchronous, so the partition may be read again from
+	// before the last offset handed out for commit.
+	again := fetchedMessages(tracker, 0, 4)
+	assertCommits(t, tracker, msgs[1], true, -1)
+	assertCommits(t, tracker, again[0], true, 4)
+}
@@ -0,0 +1,180 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logc"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+var (
+	memoryLock   sync.Mutex
+	memoryTopics = make(map[string]*memoryTopic)
+)
+
+// memoryTopic is the buffered channel shared by every bus of a topic in the
+// process. It counts the buses that pushed and are not closed yet, so
+// stopping consumers keep draining while producers are still flushing.
+type memoryTopic struct {
+	messages chan Message
+
+	mu        sync.Mutex
+	producers int
+	// idle is closed while no producer is open.
+	idle chan struct{}
+}
+
+func (t *memoryTopic) addProducer() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.producers == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.producers++
+}
+
+func (t *memoryTopic) removeProducer() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.producers--
+	if t.producers == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *memoryTopic) idleChan() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.idle
+}
+
+// memoryBus hands events over a channel, so it only delivers when the
+// producer and the consumer run in one binary. Events still buffered at exit
+// are lost.
+type memoryBus struct {
+	topic      *memoryTopic
+	processors int
+
+	pushOnce  sync.Once
+	closeOnce sync.Once
+	pushed    bool
+}
+
+func newMemoryBus(topic string, bufferSize, processors int) *memoryBus {
+	memoryLock.Lock()
+	defer memoryLock.Unlock()
+
+	t, ok := memoryTopics[topic]
+	if !ok {
+		idle := make(chan struct{})
+		close(idle)
+		t = &memoryTopic{messages: make(chan Message, bufferSize), idle: idle}
+		memoryTopics[topic] = t
+	}
+
+	return &memoryBus{topic: t, processors: max(processors, 1)}
+}
+
+// Push waits for buffer space until ctx is done. A bus that pushed keeps
+// stopping consumers of its topic running until it is closed.
+func (b *memoryBus) Push(ctx context.Context, msgs ...Message) error {
+	b.pushOnce.Do(func() {
+		b.pushed = true
+		b.topic.addProducer()
+	})
+
+	for _, msg := range msgs {
+		msg := Message{Key: newKey(), Value: msg.Value, Headers: injectHeaders(ctx, msg)}
+		select {
+		case b.topic.messages <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(handler Handler) (service.Service, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &memoryConsumer{
+		topic:      b.topic,
+		handler:    handler,
+		processors: b.processors,
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+func (b *memoryBus) Close() error {
+	b.pushOnce.Do(func() {})
+	b.closeOnce.Do(func() {
+		if b.pushed {
+			b.topic.removeProducer()
+		}
+	})
+	return nil
+}
+
+type memoryConsumer struct {
+	topic      *memoryTopic
+	handler    Handler
+	processors int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Start consumes with Processors handlers until Stop is called and every
+// producer of the topic is closed, then drains the buffered events.
+func (c *memoryConsumer) Start() {
+	group := threading.NewRoutineGroup()
+	for i := 0; i < c.processors; i++ {
+		group.RunSafe(c.consume)
+	}
+	group.Wait()
+}
+
+func (c *memoryConsumer) Stop() {
+	c.once.Do(c.cancel)
+}
+
+func (c *memoryConsumer) consume() {
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.drain()
+			return
+		case msg := <-c.topic.messages:
+			c.handle(msg)
+		}
+	}
+}
+
+func (c *memoryConsumer) drain() {
+	idle := c.topic.idleChan()
+	for {
+		select {
+		case msg := <-c.topic.messages:
+			c.handle(msg)
+		case <-idle:
+			for {
+				select {
+				case msg := <-c.topic.messages:
+					c.handle(msg)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *memoryConsumer) handle(msg Message) {
+	ctx := extractHeaders(context.Background(), msg.Headers)
+	if err := c.handler.Consume(ctx, msg); err != nil {
+		logc.Errorf(ctx, "consume: %s, error: %v", msg.Value, err)
+	}
+}
@@ -0,0 +1,225 @@
+package eventbus
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/logc"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// postgresBus queues events in the event_queue table. Consumers claim batches
+// with SELECT ... FOR UPDATE SKIP LOCKED and hide them for a lease, so several
+// consumers share a topic without handling an event twice. Handled events are
+// deleted; failed ones stay queued and are retried with backoff until they
+// move to event_dead_letters.
+type postgresBus struct {
+	conn       sqlx.SqlConn
+	conf       Conf
+	topic      string
+	processors int
+}
+
+type queuedEvent struct {
+	Id      int64  `db:"id"`
+	Key     string `db:"key"`
+	Value   []byte `db:"value"`
+	Headers string `db:"headers"`
+}
+
+// NewPostgresBus returns a postgres event bus for the topic in kc on conn,
+// ignoring c.DataSource.
+func NewPostgresBus(conn sqlx.SqlConn, c Conf, kc kq.KqConf) EventBus {
+	return &postgresBus{
+		conn:       conn,
+		conf:       c,
+		topic:      kc.Topic,
+		processors: max(kc.Processors, 1),
+	}
+}
+
+// Push inserts msgs in one statement, so either all or none are queued.
+func (b *postgresBus) Push(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(msgs))
+	args := make([]any, 0, len(msgs)*4)
+	for _, msg := range msgs {
+		headers, err := json.Marshal(injectHeaders(ctx, msg))
+		if err != nil {
+			return err
+		}
+		args = append(args, b.topic, newKey(), []byte(msg.Value), string(headers))
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d)", n-3, n-2, n-1, n))
+	}
+
+	_, err := b.conn.ExecCtx(ctx, `INSERT INTO event_queue (topic, key, value, headers) VALUES `+
+		strings.Join(values, ", "), args...)
+	return err
+}
+
+func (b *postgresBus) Subscribe(handler Handler) (service.Service, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &postgresConsumer{
+		bus:     b,
+		handler: handler,
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+// Close leaves the connection pool open; go-zero shares it with the service.
+func (b *postgresBus) Close() error {
+	return nil
+}
+
+type postgresConsumer struct {
+	bus     *postgresBus
+	handler Handler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Start claims batches until the queue is empty, then polls every
+// PollInterval until Stop is called.
+func (c *postgresConsumer) Start() {
+	ticker := time.NewTicker(time.Duration(c.bus.conf.PollInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		for c.ctx.Err() == nil {
+			if c.RunOnce(c.ctx) < c.bus.conf.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *postgresConsumer) Stop() {
+	c.once.Do(c.cancel)
+}
+
+// RunOnce claims up to BatchSize visible events, hands them to the handler
+// with up to Processors at a time, then settles them. It returns how many
+// events were claimed. The claim is committed before any handler runs, so no
+// transaction stays open while they do: claimed events are hidden for Lease
+// instead, and an event whose consumer dies is claimed again once the lease
+// expires. A claimed batch is finished even when ctx is cancelled.
+func (c *postgresConsumer) RunOnce(ctx context.Context) int {
+	var rows []*queuedEvent
+	err := c.bus.conn.QueryRowsCtx(ctx, &rows, `UPDATE event_queue SET attempts = attempts + 1,
+		visible_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE id IN (SELECT id FROM event_queue WHERE topic = $1 AND visible_at <= NOW()
+			ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED)
+		RETURNING id, key, value, headers`, c.bus.topic, c.bus.conf.BatchSize, c.bus.conf.Lease)
+	if err != nil {
+		logx.Errorf("failed to claim events of queue %s: %v", c.bus.topic, err)
+		return 0
+	}
+	if len(rows) == 0 {
+		return 0
+	}
+	// RETURNING yields rows in no particular order
+	slices.SortFunc(rows, func(a, b *queuedEvent) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	var mu sync.Mutex
+	handled := make([]int64, 0, len(rows))
+	failed := make([]int64, 0)
+	runner := threading.NewTaskRunner(c.bus.processors)
+	for _, row := range rows {
+		runner.Schedule(func() {
+			ok := c.consume(row)
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				handled = append(handled, row.Id)
+			} else {
+				failed = append(failed, row.Id)
+			}
+		})
+	}
+	runner.Wait()
+
+	if err := c.settle(context.WithoutCancel(ctx), handled, failed); err != nil {
+		// Unsettled events are claimed again once their lease expires
+		logx.Errorf("failed to settle events of queue %s: %v", c.bus.topic, err)
+	}
+	return len(rows)
+}
+
+// settle deletes the handled events and hides the failed ones until their
+// retry is due. Failed events delivered MaxAttempts times move to
+// event_dead_letters instead.
+func (c *postgresConsumer) settle(ctx context.Context, handled, failed []int64) error {
+	return c.bus.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if len(handled) > 0 {
+			if _, err := session.ExecCtx(ctx, `DELETE FROM event_queue WHERE id = ANY($1)`, pq.Array(handled)); err != nil {
+				return err
+			}
+		}
+		if len(failed) == 0 {
+			return nil
+		}
+
+		if c.bus.conf.MaxAttempts > 0 {
+			var dead []int64
+			if err := session.QueryRowsCtx(ctx, &dead, `WITH dead AS (
+				DELETE FROM event_queue WHERE id = ANY($1) AND attempts >= $2
+				RETURNING id, topic, key, value, headers, attempts, created_at)
+				INSERT INTO event_dead_letters (id, topic, key, value, headers, attempts, created_at)
+				SELECT id, topic, key, value, headers, attempts, created_at FROM dead RETURNING id`,
+				pq.Array(failed), c.bus.conf.MaxAttempts); err != nil {
+				return err
+			}
+			for _, id := range dead {
+				logx.Errorf("moved event %d of queue %s to event_dead_letters after %d attempts",
+					id, c.bus.topic, c.bus.conf.MaxAttempts)
+			}
+		}
+
+		_, err := session.ExecCtx(ctx, `UPDATE event_queue
+			SET visible_at = NOW() + LEAST($2 * POWER(2, attempts - 1), $3) * INTERVAL '1 millisecond'
+			WHERE id = ANY($1)`, pq.Array(failed), c.bus.conf.RetryBackoff, c.bus.conf.MaxBackoff)
+		return err
+	})
+}
+
+// consume hands row to the handler and reports whether it succeeded.
+func (c *postgresConsumer) consume(row *queuedEvent) bool {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(row.Headers), &headers); err != nil {
+		logx.Errorf("invalid headers of queued event %d: %v", row.Id, err)
+	}
+
+	ctx := extractHeaders(context.Background(), headers)
+	msg := Message{Key: row.Key, Value: string(row.Value), Headers: headers}
+	if err := c.handler.Consume(ctx, msg); err != nil {
+		logc.Errorf(ctx, "consume: %s, error: %v", string(row.Value), err)
+		return false
+	}
+	return true
+}
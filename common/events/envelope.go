@@ -0,0 +1,237 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"go-shortener/common/events/eventspb"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypeClick is the envelope type of click events.
+const TypeClick = "click"
+
+// Click event versions. Version 1 is the bare ClickEvent JSON published
+// before envelopes existed; it carries no type or version field.
+const (
+	ClickVersion1 = 1
+	ClickVersion2 = 2
+
+	// ClickVersion is the version new click events are published with.
+	ClickVersion = ClickVersion2
+)
+
+// Content types of encoded events, carried in the content-type header.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// HeaderContentType is the message header naming the event encoding.
+const HeaderContentType = "content-type"
+
+// Column widths of the clicks table, in characters. Longer values can never
+// be stored.
+const (
+	maxShortCodeLength = 8
+	maxVariantLength   = 32
+)
+
+var (
+	// ErrUnsupportedVersion is returned for versions this build cannot decode.
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+	// ErrInvalidEvent is returned for events failing schema checks.
+	ErrInvalidEvent = errors.New("invalid event")
+)
+
+type (
+	// Envelope is the JSON encoding of a versioned event. Payload is decoded
+	// according to Type and Version.
+	Envelope struct {
+		Type       string          `json:"type"`
+		Version    int             `json:"version"`
+		ID         string          `json:"id"`
+		OccurredAt time.Time       `json:"occurred_at"`
+		Payload    json.RawMessage `json:"payload"`
+	}
+
+	// clickV2 is the JSON payload of a version 2 click envelope. The event id
+	// and time live on the envelope.
+	clickV2 struct {
+		ShortCode string `json:"short_code"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+		Referer   string `json:"referer"`
+		Variant   string `json:"variant,omitempty"`
+	}
+)
+
+// EncodeClick encodes e as a current-version envelope in contentType.
+func EncodeClick(e *ClickEvent, contentType string) ([]byte, error) {
+	occurredAt := time.Unix(e.Timestamp, 0).UTC()
+
+	switch contentType {
+	case ContentTypeJSON:
+		payload, err := json.Marshal(clickV2{
+			ShortCode: e.ShortCode,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			Referer:   e.Referer,
+			Variant:   e.Variant,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(Envelope{
+			Type:       TypeClick,
+			Version:    ClickVersion,
+			ID:         e.EventID,
+			OccurredAt: occurredAt,
+			Payload:    payload,
+		})
+	case ContentTypeProtobuf:
+		payload, err := proto.Marshal(&eventspb.ClickV2{
+			ShortCode: e.ShortCode,
+			Ip:        e.IP,
+			UserAgent: e.UserAgent,
+			Referer:   e.Referer,
+			Variant:   e.Variant,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(&eventspb.Envelope{
+			Type:       TypeClick,
+			Version:    ClickVersion,
+			Id:         e.EventID,
+			OccurredAt: occurredAt.UnixNano(),
+			Payload:    payload,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+}
+
+// DecodeClick decodes a click event of any supported version. An empty
+// content type is treated as JSON, which covers version 1 events published
+// without headers.
+func DecodeClick(contentType string, data []byte) (*ClickEvent, error) {
+	var (
+		e   *ClickEvent
+		err error
+	)
+	switch contentType {
+	case "", ContentTypeJSON:
+		e, err = decodeJSONClick(data)
+	case ContentTypeProtobuf:
+		e, err = decodeProtobufClick(data)
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateClick(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func decodeJSONClick(data []byte) (*ClickEvent, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	// Version 1 has no envelope
+	if envelope.Type == "" && envelope.Version == 0 {
+		var e ClickEvent
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	}
+
+	if err := checkEnvelope(envelope.Type, envelope.Version); err != nil {
+		return nil, err
+	}
+	var payload clickV2
+	if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &ClickEvent{
+		EventID:   envelope.ID,
+		ShortCode: payload.ShortCode,
+		Timestamp: occurredAt(envelope.OccurredAt),
+		IP:        payload.IP,
+		UserAgent: payload.UserAgent,
+		Referer:   payload.Referer,
+		Variant:   payload.Variant,
+	}, nil
+}
+
+func decodeProtobufClick(data []byte) (*ClickEvent, error) {
+	var envelope eventspb.Envelope
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if err := checkEnvelope(envelope.Type, int(envelope.Version)); err != nil {
+		return nil, err
+	}
+
+	var payload eventspb.ClickV2
+	if err := proto.Unmarshal(envelope.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &ClickEvent{
+		EventID:   envelope.Id,
+		ShortCode: payload.ShortCode,
+		Timestamp: occurredAt(time.Unix(0, envelope.OccurredAt)),
+		IP:        payload.Ip,
+		UserAgent: payload.UserAgent,
+		Referer:   payload.Referer,
+		Variant:   payload.Variant,
+	}, nil
+}
+
+// checkEnvelope accepts click envelopes of versions with a payload decoder.
+// Version 1 predates envelopes, so it is never valid inside one.
+func checkEnvelope(typ string, version int) error {
+	if typ != TypeClick {
+		return fmt.Errorf("%w: type %q", ErrInvalidEvent, typ)
+	}
+	if version != ClickVersion2 {
+		return fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, typ, version)
+	}
+	return nil
+}
+
+func occurredAt(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func validateClick(e *ClickEvent) error {
+	if e.ShortCode == "" {
+		return fmt.Errorf("%w: missing short_code", ErrInvalidEvent)
+	}
+	if utf8.RuneCountInString(e.ShortCode) > maxShortCodeLength {
+		return fmt.Errorf("%w: short_code longer than %d characters", ErrInvalidEvent, maxShortCodeLength)
+	}
+	if utf8.RuneCountInString(e.Variant) > maxVariantLength {
+		return fmt.Errorf("%w: variant longer than %d characters", ErrInvalidEvent, maxVariantLength)
+	}
+	if e.EventID != "" {
+		if err := uuid.Validate(e.EventID); err != nil {
+			return fmt.Errorf("%w: event id: %v", ErrInvalidEvent, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeClick_Compatibility(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		payload     string
+		want        ClickEvent
+	}{
+		{
+			name:        "v1 before variants and event ids",
+			contentType: "",
+			payload:     `{"short_code":"abc12345","timestamp":1748779200,"ip":"1.2.3.4","user_agent":"Mozilla/5.0","referer":"https://google.com"}`,
+			want: ClickEvent{
+				ShortCode: "abc12345", Timestamp: 1748779200, IP: "1.2.3.4",
+				UserAgent: "Mozilla/5.0", Referer: "https://google.com",
+			},
+		},
+		{
+			name:        "v1 with variant and event id",
+			contentType: "",
+			payload:     `{"event_id":"01972a3c-5f6e-7d8a-9b0c-1d2e3f405162","short_code":"abc12345","timestamp":1748779200,"ip":"1.2.3.4","user_agent":"Mozilla/5.0","referer":"","variant":"b"}`,
+			want: ClickEvent{
+				EventID: "01972a3c-5f6e-7d8a-9b0c-1d2e3f405162", ShortCode: "abc12345",
+				Timestamp: 1748779200, IP: "1.2.3.4", UserAgent: "Mozilla/5.0", Variant: "b",
+			},
+		},
+		{
+			name:        "v2 json",
+			contentType: ContentTypeJSON,
+			payload:     `{"type":"click","version":2,"id":"01972a3c-5f6e-7d8a-9b0c-1d2e3f405162","occurred_at":"2025-06-01T12:00:00Z","payload":{"short_code":"abc12345","ip":"1.2.3.4","user_agent":"Mozilla/5.0","referer":"https://t.co/x","variant":"a"}}`,
+			want: ClickEvent{
+				EventID: "01972a3c-5f6e-7d8a-9b0c-1d2e3f405162", ShortCode: "abc12345", Timestamp: 1748779200,
+				IP: "1.2.3.4", UserAgent: "Mozilla/5.0", Referer: "https://t.co/x", Variant: "a",
+			},
+		},
+		{
+			name:        "v2 json with unknown fields",
+			contentType: ContentTypeJSON,
+			payload:     `{"type":"click","version":2,"id":"","occurred_at":"2025-06-01T12:00:00Z","source":"edge","payload":{"short_code":"abc12345","country_hint":"DE"}}`,
+			want:        ClickEvent{ShortCode: "abc12345", Timestamp: 1748779200},
+		},
+		{
+			name:        "v2 protobuf",
+			contentType: ContentTypeProtobuf,
+			// EncodeClick output for the v2 json case above
+			payload: mustHex(t, "0a05636c69636b10021a2430313937326133632d356636652d376438612d396230632d316432653366343035313632208080def4abadbaa2182a330a0861626331323334351207312e322e332e341a0b4d6f7a696c6c612f352e30220e68747470733a2f2f742e636f2f782a0161"),
+			want: ClickEvent{
+				EventID: "01972a3c-5f6e-7d8a-9b0c-1d2e3f405162", ShortCode: "abc12345", Timestamp: 1748779200,
+				IP: "1.2.3.4", UserAgent: "Mozilla/5.0", Referer: "https://t.co/x", Variant: "a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeClick(tt.contentType, []byte(tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestEncodeClick_RoundTrip(t *testing.T) {
+	event := ClickEvent{
+		EventID:   "01972a3c-5f6e-7d8a-9b0c-1d2e3f405162",
+		ShortCode: "abc12345",
+		Timestamp: 1748779200,
+		IP:        "1.2.3.4",
+		UserAgent: "Mozilla/5.0",
+		Referer:   "https://t.co/x",
+		Variant:   "a",
+	}
+
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf} {
+		t.Run(contentType, func(t *testing.T) {
+			data, err := EncodeClick(&event, contentType)
+			require.NoError(t, err)
+
+			got, err := DecodeClick(contentType, data)
+			require.NoError(t, err)
+			assert.Equal(t, event, *got)
+		})
+	}
+}
+
+func TestEncodeClick_ProtobufIsStable(t *testing.T) {
+	data, err := EncodeClick(&ClickEvent{
+		EventID:   "01972a3c-5f6e-7d8a-9b0c-1d2e3f405162",
+		ShortCode: "abc12345",
+		Timestamp: 1748779200,
+		IP:        "1.2.3.4",
+		UserAgent: "Mozilla/5.0",
+		Referer:   "https://t.co/x",
+		Variant:   "a",
+	}, ContentTypeProtobuf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0a05636c69636b10021a2430313937326133632d356636652d376438612d396230632d316432653366343035313632208080def4abadbaa2182a330a0861626331323334351207312e322e332e341a0b4d6f7a696c6c612f352e30220e68747470733a2f2f742e636f2f782a0161",
+		hex.EncodeToString(data))
+}
+
+func TestDecodeClick_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		payload     string
+		wantErr     error
+	}{
+		{name: "malformed json", payload: "{invalid json"},
+		{name: "unsupported version", contentType: ContentTypeJSON, payload: `{"type":"click","version":3,"payload":{"short_code":"abc12345"}}`, wantErr: ErrUnsupportedVersion},
+		{name: "v1 inside an envelope", contentType: ContentTypeJSON, payload: `{"type":"click","version":1,"payload":{"short_code":"abc12345"}}`, wantErr: ErrUnsupportedVersion},
+		{name: "wrong type", contentType: ContentTypeJSON, payload: `{"type":"signup","version":2,"payload":{}}`, wantErr: ErrInvalidEvent},
+		{name: "missing short code", payload: `{"timestamp":1748779200}`, wantErr: ErrInvalidEvent},
+		{name: "invalid event id", payload: `{"event_id":"not-a-uuid","short_code":"abc12345"}`, wantErr: ErrInvalidEvent},
+		{name: "short code too long", payload: `{"short_code":"abc123456"}`, wantErr: ErrInvalidEvent},
+		{name: "variant too long", payload: `{"short_code":"abc12345","variant":"` + strings.Repeat("v", 33) + `"}`, wantErr: ErrInvalidEvent},
+		{name: "unknown content type", contentType: "application/avro", payload: "{}"},
+		{name: "malformed protobuf", contentType: ContentTypeProtobuf, payload: "\xff\xff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeClick(tt.contentType, []byte(tt.payload))
+			require.Error(t, err)
+			assert.Nil(t, got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func mustHex(t *testing.T, s string) string {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -3,9 +3,16 @@ package events
 // ClickEvent represents a URL redirect event published to the message queue.
 // Phase 7: Type definition only. Phase 9 will wire Kafka producer/consumer.
 type ClickEvent struct {
+	// EventID is a UUIDv7 assigned when the click happens. The consumer stores
+	// it as the click id, so a redelivered event is not counted twice. Empty
+	// for events published before event IDs were introduced.
+	EventID   string `json:"event_id,omitempty"`
 	ShortCode string `json:"short_code"`
 	Timestamp int64  `json:"timestamp"`
 	IP        string `json:"ip"`
 	UserAgent string `json:"user_agent"`
 	Referer   string `json:"referer"`
+	// Variant is the label of the weighted destination chosen for this click.
+	// Empty for links with a single destination.
+	Variant string `json:"variant,omitempty"`
 }
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// errNoBrokers is returned by KafkaBrokers without brokers to check.
+var errNoBrokers = errors.New("no kafka brokers configured")
+
+// Ping checks that conn reaches its database.
+func Ping(conn sqlx.SqlConn) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		db, err := conn.RawDB()
+		if err != nil {
+			return err
+		}
+		return db.PingContext(ctx)
+	})
+}
+
+// KafkaBrokers checks that one of brokers returns the cluster metadata.
+func KafkaBrokers(brokers []string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		if len(brokers) == 0 {
+			return errNoBrokers
+		}
+
+		var errs []error
+		for _, broker := range brokers {
+			err := fetchBrokers(ctx, broker)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+func fetchBrokers(ctx context.Context, broker string) error {
+	conn, err := kafka.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	_, err = conn.Brokers()
+	return err
+}
+
+// Grpc checks that the server behind conn reports serving through the gRPC
+// health service, which go-zero registers on every zrpc server.
+func Grpc(conn *grpc.ClientConn) Checker {
+	client := grpc_health_v1.NewHealthClient(conn)
+	return CheckerFunc(func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			return fmt.Errorf("server is %s", resp.Status)
+		}
+		return nil
+	})
+}
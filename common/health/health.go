@@ -0,0 +1,206 @@
+// Package health reports whether a service is alive and whether the
+// dependencies it needs to serve traffic are reachable.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/syncx"
+	"github.com/zeromicro/go-zero/core/threading"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+const (
+	// StatusUp reports a usable service or dependency.
+	StatusUp = "up"
+	// StatusDown reports an unusable service or dependency.
+	StatusDown = "down"
+	// StatusDegraded reports a ready service with an optional dependency down.
+	StatusDegraded = "degraded"
+
+	reportKey = "report"
+)
+
+// Conf controls how readiness checks run. Reports are reused for
+// CacheDuration, so frequent probes don't load the dependencies.
+type Conf struct {
+	CacheDuration int `json:",default=2000"` // milliseconds
+	Timeout       int `json:",default=1000"` // milliseconds each check may take
+}
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc lets a function be used as a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result is the outcome of one dependency check.
+type Result struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Optional  bool   `json:"optional,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the readiness of a service: down when a required check is down,
+// degraded when only optional checks are.
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// Health runs the registered checkers and serves /livez and /readyz.
+type Health struct {
+	conf   Conf
+	flight syncx.SingleFlight
+
+	mu       sync.Mutex
+	checkers map[string]check
+	report   Report
+	expires  time.Time
+}
+
+// New returns a Health without checkers, which is always ready.
+func New(c Conf) *Health {
+	return &Health{
+		conf:     c,
+		flight:   syncx.NewSingleFlight(),
+		checkers: make(map[string]check),
+	}
+}
+
+type check struct {
+	checker  Checker
+	optional bool
+}
+
+// Register adds a dependency the service cannot serve without, replacing any
+// checker of that name.
+func (h *Health) Register(name string, checker Checker) {
+	h.register(name, check{checker: checker})
+}
+
+// RegisterOptional adds a dependency the service degrades without. It is
+// reported but keeps the service ready.
+func (h *Health) RegisterOptional(name string, checker Checker) {
+	h.register(name, check{checker: checker, optional: true})
+}
+
+func (h *Health) register(name string, c check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers[name] = c
+	h.expires = time.Time{}
+}
+
+// Report returns the readiness report, running the checks concurrently unless
+// a report younger than CacheDuration exists. Concurrent callers share one
+// run of the checks.
+func (h *Health) Report() Report {
+	h.mu.Lock()
+	if time.Now().Before(h.expires) {
+		report := h.report
+		h.mu.Unlock()
+		return report
+	}
+	h.mu.Unlock()
+
+	v, _ := h.flight.Do(reportKey, func() (any, error) {
+		report := h.check()
+		h.mu.Lock()
+		h.report = report
+		h.expires = time.Now().Add(time.Duration(h.conf.CacheDuration) * time.Millisecond)
+		h.mu.Unlock()
+		return report, nil
+	})
+	return v.(Report)
+}
+
+func (h *Health) check() Report {
+	h.mu.Lock()
+	checkers := make(map[string]check, len(h.checkers))
+	for name, c := range h.checkers {
+		checkers[name] = c
+	}
+	h.mu.Unlock()
+
+	report := Report{
+		Status:    StatusUp,
+		Checks:    make(map[string]Result, len(checkers)),
+		CheckedAt: time.Now(),
+	}
+	var lock sync.Mutex
+	group := threading.NewRoutineGroup()
+	for name, c := range checkers {
+		group.RunSafe(func() {
+			result := h.run(c.checker)
+			result.Optional = c.optional
+			lock.Lock()
+			defer lock.Unlock()
+			report.Checks[name] = result
+			switch {
+			case result.Status == StatusUp:
+			case !c.optional:
+				report.Status = StatusDown
+			case report.Status == StatusUp:
+				report.Status = StatusDegraded
+			}
+		})
+	}
+	group.Wait()
+
+	return report
+}
+
+// run checks one dependency, reporting a panicking checker down.
+func (h *Health) run(checker Checker) (result Result) {
+	// Checks outlive the probe that triggered them, as the report is shared.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(h.conf.Timeout)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		result.LatencyMs = time.Since(start).Milliseconds()
+		if p := recover(); p != nil {
+			result.Status = StatusDown
+			result.Error = "check panicked"
+		}
+	}()
+
+	if err := checker.Check(ctx); err != nil {
+		return Result{Status: StatusDown, Error: err.Error()}
+	}
+	return Result{Status: StatusUp}
+}
+
+// LivenessHandler reports that the process serves HTTP. It checks no
+// dependency, so an outage doesn't get the service restarted.
+func (h *Health) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httpx.OkJsonCtx(r.Context(), w, map[string]string{"status": StatusUp})
+	}
+}
+
+// ReadinessHandler writes the readiness report, with 503 Service Unavailable
+// when a required dependency is down.
+func (h *Health) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := h.Report()
+		code := http.StatusOK
+		if report.Status == StatusDown {
+			code = http.StatusServiceUnavailable
+		}
+		httpx.WriteJsonCtx(r.Context(), w, code, report)
+	}
+}
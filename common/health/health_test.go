@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countingChecker(calls *atomic.Int32, err error) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		calls.Add(1)
+		return err
+	})
+}
+
+func TestHealth_ReportUpWithoutCheckers(t *testing.T) {
+	report := New(Conf{CacheDuration: 1000, Timeout: 100}).Report()
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestHealth_ReportDownWhenOneCheckFails(t *testing.T) {
+	var calls atomic.Int32
+	h := New(Conf{CacheDuration: 1000, Timeout: 100})
+	h.Register("postgres", countingChecker(&calls, nil))
+	h.Register("kafka", countingChecker(&calls, errors.New("connection refused")))
+
+	report := h.Report()
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusUp, report.Checks["postgres"].Status)
+	assert.Equal(t, StatusDown, report.Checks["kafka"].Status)
+	assert.Equal(t, "connection refused", report.Checks["kafka"].Error)
+}
+
+func TestHealth_OptionalCheckDegrades(t *testing.T) {
+	var calls atomic.Int32
+	h := New(Conf{CacheDuration: 1000, Timeout: 100})
+	h.Register("postgres", countingChecker(&calls, nil))
+	h.RegisterOptional("kafka", countingChecker(&calls, errors.New("connection refused")))
+
+	report := h.Report()
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.True(t, report.Checks["kafka"].Optional)
+
+	ready := httptest.NewRecorder()
+	h.ReadinessHandler()(ready, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	assert.Equal(t, http.StatusOK, ready.Code, "a degraded service stays ready")
+}
+
+func TestHealth_CachesReport(t *testing.T) {
+	var calls atomic.Int32
+	h := New(Conf{CacheDuration: 60000, Timeout: 100})
+	h.Register("postgres", countingChecker(&calls, nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.Report()
+		}()
+	}
+	wg.Wait()
+	h.Report()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHealth_RechecksAfterCacheDuration(t *testing.T) {
+	var calls atomic.Int32
+	h := New(Conf{CacheDuration: 1, Timeout: 100})
+	h.Register("postgres", countingChecker(&calls, nil))
+
+	h.Report()
+	time.Sleep(5 * time.Millisecond)
+	h.Report()
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestHealth_TimesOutSlowChecks(t *testing.T) {
+	h := New(Conf{CacheDuration: 1000, Timeout: 10})
+	h.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report := h.Report()
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+	assert.GreaterOrEqual(t, report.Checks["slow"].LatencyMs, int64(10))
+}
+
+func TestHealth_PanickingCheckIsDown(t *testing.T) {
+	h := New(Conf{CacheDuration: 1000, Timeout: 100})
+	h.Register("geoip", CheckerFunc(func(ctx context.Context) error {
+		panic("nil reader")
+	}))
+
+	report := h.Report()
+
+	assert.Equal(t, StatusDown, report.Checks["geoip"].Status)
+}
+
+func TestHealth_Handlers(t *testing.T) {
+	var calls atomic.Int32
+	h := New(Conf{CacheDuration: 1000, Timeout: 100})
+	h.Register("postgres", countingChecker(&calls, errors.New("down")))
+
+	live := httptest.NewRecorder()
+	h.LivenessHandler()(live, httptest.NewRequest(http.MethodGet, LivePath, nil))
+	assert.Equal(t, http.StatusOK, live.Code, "liveness ignores dependencies")
+
+	ready := httptest.NewRecorder()
+	h.ReadinessHandler()(ready, httptest.NewRequest(http.MethodGet, ReadyPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, ready.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(ready.Body.Bytes(), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "down", report.Checks["postgres"].Error)
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	// LivePath serves the liveness probe.
+	LivePath = "/livez"
+	// ReadyPath serves the readiness report.
+	ReadyPath = "/readyz"
+	// LegacyPath serves the liveness probe under its former path.
+	LegacyPath = "/healthz"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server serves the probes of h on a port of their own, for services without
+// an HTTP server. Server implements service.Service.
+type Server struct {
+	server *http.Server
+}
+
+// NewServer returns a Server for h listening on port.
+func NewServer(port int, h *Health) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(LegacyPath, h.LivenessHandler())
+	mux.Handle(LivePath, h.LivenessHandler())
+	mux.Handle(ReadyPath, h.ReadinessHandler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:    fmt.Sprintf(":%d", port),
+			Handler: mux,
+		},
+	}
+}
+
+// Start serves until Stop is called.
+func (s *Server) Start() {
+	logx.Infof("Health check server listening on %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logx.Errorf("Health server error: %v", err)
+	}
+}
+
+// Stop shuts the server down, waiting for in-flight probes.
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		logx.Errorf("Health server shutdown error: %v", err)
+	}
+}
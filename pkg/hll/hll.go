@@ -0,0 +1,110 @@
+// Package hll estimates distinct visitor counts with HyperLogLog.
+//
+// Registers are built in Postgres from the hex visitor_hash column so only
+// 2^precision small rows leave the database instead of every distinct hash.
+// The first 16 bits of the hash select the register and the following 52 bits
+// give the rank; AddHash applies the same derivation in Go.
+package hll
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the register index to the 16 bits
+	// taken from the hash.
+	MinPrecision = 4
+	MaxPrecision = 16
+
+	// rankBits is the width of the hash slice ranks are computed from.
+	rankBits = 52
+)
+
+// Sketch is a HyperLogLog sketch with 2^precision registers.
+type Sketch struct {
+	precision uint8
+	registers []uint8
+}
+
+// New returns an empty sketch. The standard error is about 1.04/sqrt(2^precision),
+// e.g. 0.8% for precision 14.
+func New(precision uint8) (*Sketch, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("hll: precision %d out of range [%d, %d]", precision, MinPrecision, MaxPrecision)
+	}
+	return &Sketch{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Set raises register index to rank. Lower ranks are ignored, so registers
+// can be merged in any order.
+func (s *Sketch) Set(index int, rank uint8) {
+	if index < 0 || index >= len(s.registers) {
+		return
+	}
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// Registers returns the register values by index. The slice must not be modified.
+func (s *Sketch) Registers() []uint8 {
+	return s.registers
+}
+
+// AddHash adds a hex visitor hash of at least 17 characters.
+func (s *Sketch) AddHash(hash string) error {
+	if len(hash) < 17 {
+		return fmt.Errorf("hll: hash %q too short", hash)
+	}
+	head, err := strconv.ParseUint(hash[:4], 16, 16)
+	if err != nil {
+		return fmt.Errorf("hll: invalid hash %q: %w", hash, err)
+	}
+	w, err := strconv.ParseUint(hash[4:17], 16, rankBits)
+	if err != nil {
+		return fmt.Errorf("hll: invalid hash %q: %w", hash, err)
+	}
+	s.Set(int(head>>(16-s.precision)), uint8(rankBits+1-bits.Len64(w)))
+	return nil
+}
+
+// Estimate returns the estimated number of distinct hashes added.
+func (s *Sketch) Estimate() int64 {
+	m := float64(len(s.registers))
+
+	var sum float64
+	zeros := 0
+	for _, r := range s.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(s.registers)) * m * m / sum
+	// Linear counting is more accurate while many registers are still empty.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int64(math.Round(estimate))
+}
+
+// alpha is the bias correction constant for m registers.
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
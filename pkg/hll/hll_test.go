@@ -0,0 +1,86 @@
+package hll
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func visitorHash(i int) string {
+	sum := sha256.Sum256([]byte(strconv.Itoa(i)))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+func TestSketch_Estimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision uint8
+		distinct  int
+		tolerance float64
+	}{
+		{name: "empty", precision: 14, distinct: 0, tolerance: 0},
+		{name: "small range", precision: 14, distinct: 1000, tolerance: 0.02},
+		{name: "large range", precision: 14, distinct: 200000, tolerance: 0.03},
+		{name: "low precision", precision: 10, distinct: 50000, tolerance: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.precision)
+			require.NoError(t, err)
+			for i := 0; i < tt.distinct; i++ {
+				// Repeat visits must not inflate the estimate
+				require.NoError(t, s.AddHash(visitorHash(i)))
+				require.NoError(t, s.AddHash(visitorHash(i)))
+			}
+			assert.InDelta(t, float64(tt.distinct), float64(s.Estimate()), float64(tt.distinct)*tt.tolerance)
+		})
+	}
+}
+
+func TestSketch_SetKeepsMaximum(t *testing.T) {
+	a, err := New(4)
+	require.NoError(t, err)
+	b, err := New(4)
+	require.NoError(t, err)
+
+	a.Set(3, 5)
+	a.Set(3, 2)
+	b.Set(3, 2)
+	b.Set(3, 5)
+
+	assert.Equal(t, a.registers, b.registers)
+	assert.Equal(t, uint8(5), a.registers[3])
+}
+
+func TestSketch_AddHashMatchesSQL(t *testing.T) {
+	s, err := New(14)
+	require.NoError(t, err)
+
+	// ('x'||'ffff')::bit(16)::int >> 2 = 16383; 13 zero hex digits rank 53
+	require.NoError(t, s.AddHash("ffff0000000000000"))
+	assert.Equal(t, uint8(53), s.registers[16383])
+
+	// 0x8000000000000 has no leading zeros in 52 bits
+	require.NoError(t, s.AddHash("00048000000000000"))
+	assert.Equal(t, uint8(1), s.registers[1])
+}
+
+func TestNew_InvalidPrecision(t *testing.T) {
+	_, err := New(3)
+	assert.Error(t, err)
+	_, err = New(17)
+	assert.Error(t, err)
+}
+
+func TestSketch_AddHashInvalid(t *testing.T) {
+	s, err := New(10)
+	require.NoError(t, err)
+
+	assert.Error(t, s.AddHash("abc"))
+	assert.Error(t, s.AddHash("zzzz0000000000000"))
+}
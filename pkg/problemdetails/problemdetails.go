@@ -3,11 +3,12 @@ package problemdetails
 import "fmt"
 
 const (
-	TypeInvalidURL        = "invalid-url"
-	TypeNotFound          = "not-found"
-	TypeRateLimitExceeded = "rate-limit-exceeded"
-	TypeInternalError     = "internal-error"
-	TypeValidationError   = "validation-error"
+	TypeInvalidURL         = "invalid-url"
+	TypeNotFound           = "not-found"
+	TypeRateLimitExceeded  = "rate-limit-exceeded"
+	TypeInternalError      = "internal-error"
+	TypeValidationError    = "validation-error"
+	TypeServiceUnavailable = "service-unavailable"
 )
 
 type FieldError struct {
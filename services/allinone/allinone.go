@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	_ "time/tzdata" // embed the IANA database for analytics timezones
+
+	"go-shortener/common/eventbus"
+	"go-shortener/services/allinone/internal/config"
+	"go-shortener/services/analytics-consumer/analyticsconsumer"
+	"go-shortener/services/analytics-rpc/analyticsrpc"
+	"go-shortener/services/url-api/urlapi"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+var configFile = flag.String("f", "etc/allinone.yaml", "the config file")
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	// Set up process-wide logging, telemetry and DevServer before the
+	// services, whose own setup is then ignored
+	c.MustSetUp()
+
+	// Deliver click events in process
+	c.Url.EventBus.Driver = eventbus.DriverMemory
+	c.Consumer.EventBus.Driver = eventbus.DriverMemory
+	c.Consumer.KqConsumerConf.Topic = c.Url.KqPusherConf.Topic
+
+	analytics := analyticsrpc.MustNewServer(c.Analytics)
+
+	group := service.NewServiceGroup()
+	defer group.Stop()
+
+	group.Add(analyticsconsumer.MustNew(c.Consumer))
+	group.Add(analytics)
+	group.Add(urlapi.MustNew(c.Url, urlapi.WithAnalytics(analytics.Client())))
+
+	fmt.Printf("Starting all-in-one server at %s:%d, analytics rpc at %s...\n",
+		c.Url.Host, c.Url.Port, c.Analytics.ListenOn)
+	group.Start()
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"go-shortener/services/analytics-consumer/analyticsconsumer"
+	"go-shortener/services/analytics-rpc/analyticsrpc"
+	"go-shortener/services/url-api/urlapi"
+
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+// Config runs url-api, analytics-rpc and analytics-consumer in one process.
+// The top-level ServiceConf sets up logging, telemetry and the DevServer for
+// the whole process; the same settings of each service are ignored.
+type Config struct {
+	service.ServiceConf
+	// DataSource and Pool are inherited by every service, which then share
+	// one connection pool.
+	DataSource string
+	Pool       PoolConfig
+	Url        urlapi.Config
+	Analytics  analyticsrpc.Config
+	// Consumer always reads click events from url-api in process; of
+	// KqConsumerConf only Processors applies.
+	Consumer analyticsconsumer.Config
+}
+
+type PoolConfig struct {
+	MaxOpenConns    int `json:",default=20"`
+	MaxIdleConns    int `json:",default=10"`
+	ConnMaxLifetime int `json:",default=3600"` // seconds
+}
@@ -0,0 +1,37 @@
+// Package analyticsconsumer wires analytics-consumer so it runs on its own or
+// inside the allinone binary.
+package analyticsconsumer
+
+import (
+	"context"
+
+	"go-shortener/common/health"
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-consumer/internal/mqs"
+	"go-shortener/services/analytics-consumer/internal/partition"
+	"go-shortener/services/analytics-consumer/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+// Config is the analytics-consumer configuration.
+type Config = config.Config
+
+// MustNew returns the click event consumer, the partition manager and the
+// health check server as one group, exiting on error.
+func MustNew(c Config) *service.ServiceGroup {
+	svcCtx := svc.NewServiceContext(c)
+
+	consumer, err := svcCtx.EventBus.Subscribe(mqs.NewClickEventConsumer(context.Background(), svcCtx))
+	logx.Must(err)
+
+	group := service.NewServiceGroup()
+	group.Add(consumer)
+	group.Add(health.NewServer(c.HealthCheckPort, svcCtx.Health))
+	if c.Partitions.Enabled {
+		group.Add(partition.NewManager(c.Partitions, svcCtx.Partitions))
+	}
+
+	return group
+}
@@ -0,0 +1,59 @@
+// Command replay re-injects dead-lettered click events into the click events
+// topic, where the consumer processes them again.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-consumer/internal/deadletter"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var (
+	configFile = flag.String("f", "etc/consumer.yaml", "the consumer config file")
+	limit      = flag.Int("limit", 0, "maximum number of messages to replay, 0 for all")
+	idle       = flag.Duration("idle", 5*time.Second, "stop once no message arrived for this long")
+)
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	brokers := c.DeadLetter.Brokers
+	if len(brokers) == 0 {
+		brokers = c.KqConsumerConf.Brokers
+	}
+
+	// A dedicated group tracks replay progress on the dead-letter topic.
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupID:     c.KqConsumerConf.Group + "-dlq-replay",
+		Topic:       c.DeadLetter.Topic,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	// Messages are written one at a time, so they must not wait for kafka-go's
+	// default one second batch timeout.
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(c.KqConsumerConf.Brokers...),
+		Topic:        c.KqConsumerConf.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: time.Millisecond,
+	}
+	defer writer.Close()
+
+	replayed, err := deadletter.NewReplayer(reader, writer, *idle).Run(context.Background(), *limit)
+	fmt.Printf("Replayed %d messages from %s to %s\n", replayed, c.DeadLetter.Topic, c.KqConsumerConf.Topic)
+	logx.Must(err)
+}
@@ -0,0 +1,199 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// flushSpanName names the span of a batch insert.
+	flushSpanName = "click-batch flush"
+	// insertSpanName names the span of a click's part in a batch insert, a
+	// child of the span the click was written in.
+	insertSpanName = "click insert"
+)
+
+// Writer groups clicks from concurrent callers into multi-row inserts. Write
+// blocks until the batch holding the click is stored, so the caller only
+// acknowledges its Kafka message once the click is durable.
+type Writer struct {
+	conf  config.BatchConf
+	model model.ClicksModel
+
+	mu      sync.Mutex
+	pending []*pendingClick
+	// generation identifies the open batch so a late linger timer does not
+	// flush the batch opened after it.
+	generation uint64
+	timer      *time.Timer
+}
+
+type pendingClick struct {
+	click *model.Clicks
+	// span is the span context of the writer, linked from the flush span.
+	span oteltrace.SpanContext
+	done chan result
+}
+
+type result struct {
+	inserted bool
+	err      error
+}
+
+// NewWriter returns a Writer storing batches through m.
+func NewWriter(c config.BatchConf, m model.ClicksModel) *Writer {
+	return &Writer{
+		conf:  c,
+		model: m,
+	}
+}
+
+// Write adds click to the open batch and waits for it to be flushed. It
+// reports whether the click was inserted; false means it was already stored.
+func (w *Writer) Write(ctx context.Context, click *model.Clicks) (bool, error) {
+	p := &pendingClick{
+		click: click,
+		span:  oteltrace.SpanContextFromContext(ctx),
+		done:  make(chan result, 1),
+	}
+
+	w.mu.Lock()
+	w.pending = append(w.pending, p)
+	if len(w.pending) >= w.conf.Size {
+		batch := w.take()
+		w.mu.Unlock()
+		w.flush(batch)
+	} else {
+		if len(w.pending) == 1 {
+			generation := w.generation
+			w.timer = time.AfterFunc(time.Duration(w.conf.Linger)*time.Millisecond, func() {
+				w.linger(generation)
+			})
+		}
+		w.mu.Unlock()
+	}
+
+	r := <-p.done
+	return r.inserted, r.err
+}
+
+// linger flushes the batch of the given generation if it is still open.
+func (w *Writer) linger(generation uint64) {
+	w.mu.Lock()
+	if generation != w.generation || len(w.pending) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	batch := w.take()
+	w.mu.Unlock()
+
+	w.flush(batch)
+}
+
+// take closes the open batch and returns its clicks. Callers hold w.mu.
+func (w *Writer) take() []*pendingClick {
+	batch := w.pending
+	w.pending = nil
+	w.generation++
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	return batch
+}
+
+// flush stores batch within Timeout in a span of its own. The batch holds
+// clicks of many callers and traces, so it runs detached from all of them:
+// the span starts a new trace linked to the span of each click, and each
+// click's trace gets a span for its wait on the insert. When the multi-row
+// insert fails, each click is inserted on its own, so one bad row does not
+// fail the clicks batched with it.
+func (w *Writer) flush(batch []*pendingClick) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(w.conf.Timeout)*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	clicks := make([]*model.Clicks, len(batch))
+	links := make([]oteltrace.Link, 0, len(batch))
+	for i, p := range batch {
+		clicks[i] = p.click
+		if p.span.IsValid() {
+			links = append(links, oteltrace.Link{SpanContext: p.span})
+		}
+	}
+
+	ctx, span := otel.Tracer(trace.TraceName).Start(ctx, flushSpanName,
+		oteltrace.WithNewRoot(),
+		oteltrace.WithLinks(links...),
+		oteltrace.WithAttributes(attribute.Int("click.batch_size", len(batch))),
+	)
+	defer span.End()
+
+	inserted, err := w.model.InsertBatchWithRollups(ctx, clicks)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		logx.WithContext(ctx).Errorw("failed to flush click batch",
+			logx.Field("size", len(batch)),
+			logx.Field("error", err.Error()),
+		)
+		if len(batch) == 1 {
+			batch[0].finish(start, span, result{err: err})
+			return
+		}
+		for _, p := range batch {
+			w.insertOne(ctx, start, span, p)
+		}
+		return
+	}
+
+	ids := make(map[string]struct{}, len(inserted))
+	for _, id := range inserted {
+		ids[id] = struct{}{}
+	}
+	logx.WithContext(ctx).Debugw("flushed click batch",
+		logx.Field("size", len(batch)),
+		logx.Field("inserted", len(inserted)),
+	)
+	for _, p := range batch {
+		// Only the first of several writes of the same click counts as
+		// inserted.
+		_, ok := ids[p.click.Id]
+		delete(ids, p.click.Id)
+		p.finish(start, span, result{inserted: ok})
+	}
+}
+
+// insertOne stores the click of p alone.
+func (w *Writer) insertOne(ctx context.Context, start time.Time, flush oteltrace.Span, p *pendingClick) {
+	inserted, err := w.model.InsertBatchWithRollups(ctx, []*model.Clicks{p.click})
+	p.finish(start, flush, result{inserted: len(inserted) == 1, err: err})
+}
+
+// finish records the click's insert span from start to now in the trace it
+// was written in, linked to the flush span, and hands r to the writer.
+func (p *pendingClick) finish(start time.Time, flush oteltrace.Span, r result) {
+	if p.span.IsValid() {
+		ctx := oteltrace.ContextWithSpanContext(context.Background(), p.span)
+		_, span := otel.Tracer(trace.TraceName).Start(ctx, insertSpanName,
+			oteltrace.WithTimestamp(start),
+			oteltrace.WithLinks(oteltrace.Link{SpanContext: flush.SpanContext()}),
+			oteltrace.WithAttributes(attribute.Bool("click.inserted", r.inserted)),
+		)
+		if r.err != nil {
+			span.SetStatus(codes.Error, r.err.Error())
+		}
+		span.End()
+	}
+	p.done <- r
+}
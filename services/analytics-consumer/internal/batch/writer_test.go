@@ -0,0 +1,286 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func click(id string) *model.Clicks {
+	return &model.Clicks{Id: id, ShortCode: "abc12345"}
+}
+
+func TestWriter_FlushesOnSize(t *testing.T) {
+	var flushes [][]*model.Clicks
+	var mu sync.Mutex
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			flushes = append(flushes, data)
+			ids := make([]string, len(data))
+			for i, c := range data {
+				ids[i] = c.Id
+			}
+			return ids, nil
+		},
+	}
+	// Linger is long enough that only the size threshold can flush.
+	writer := NewWriter(config.BatchConf{Size: 3, Linger: 60000, Timeout: 1000}, mock)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inserted, err := writer.Write(context.Background(), click(strconv.Itoa(i)))
+			assert.NoError(t, err)
+			assert.True(t, inserted)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, flushes, 2)
+	assert.Len(t, flushes[0], 3)
+	assert.Len(t, flushes[1], 3)
+}
+
+func TestWriter_SizeFlushOutlivesCaller(t *testing.T) {
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			require.NoError(t, ctx.Err(), "the flush must not be cancelled with the caller filling the batch")
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+			return []string{data[0].Id, data[1].Id}, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 2, Linger: 60000, Timeout: 1000}, mock)
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := writer.Write(context.Background(), click("1"))
+		first <- err
+	}()
+	require.Eventually(t, func() bool {
+		writer.mu.Lock()
+		defer writer.mu.Unlock()
+		return len(writer.pending) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inserted, err := writer.Write(ctx, click("2"))
+
+	require.NoError(t, err)
+	assert.True(t, inserted)
+	require.NoError(t, <-first)
+}
+
+func TestWriter_FlushesOnLinger(t *testing.T) {
+	var calls atomic.Int32
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			calls.Add(1)
+			assert.Len(t, data, 1)
+			return []string{data[0].Id}, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 100, Linger: 10, Timeout: 1000}, mock)
+
+	start := time.Now()
+	inserted, err := writer.Write(context.Background(), click("1"))
+
+	require.NoError(t, err)
+	assert.True(t, inserted)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWriter_ReportsDuplicates(t *testing.T) {
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			return []string{"new"}, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 2, Linger: 60000, Timeout: 1000}, mock)
+
+	results := make(map[string]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range []string{"new", "dup"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inserted, err := writer.Write(context.Background(), click(id))
+			assert.NoError(t, err)
+			mu.Lock()
+			results[id] = inserted
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]bool{"new": true, "dup": false}, results)
+}
+
+func TestWriter_SameClickTwiceInBatch(t *testing.T) {
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			return []string{"1"}, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 2, Linger: 60000, Timeout: 1000}, mock)
+
+	var inserted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := writer.Write(context.Background(), click("1"))
+			assert.NoError(t, err)
+			if ok {
+				inserted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inserted.Load())
+}
+
+func TestWriter_FlushErrorFailsWholeBatch(t *testing.T) {
+	var calls atomic.Int32
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			calls.Add(1)
+			return nil, errors.New("database connection error")
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 2, Linger: 60000, Timeout: 1000}, mock)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inserted, err := writer.Write(context.Background(), click(strconv.Itoa(i)))
+			assert.False(t, inserted)
+			assert.ErrorContains(t, err, "database connection error")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(3), calls.Load(), "the batch, then each click")
+}
+
+func TestWriter_FlushErrorFallsBackToSingleInserts(t *testing.T) {
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			ids := make([]string, 0, len(data))
+			for _, c := range data {
+				if c.Id == "bad" {
+					return nil, errors.New("value too long for type character varying(32)")
+				}
+				ids = append(ids, c.Id)
+			}
+			return ids, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 3, Linger: 60000, Timeout: 1000}, mock)
+
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range []string{"1", "bad", "2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			inserted, err := writer.Write(context.Background(), click(id))
+			assert.Equal(t, err == nil, inserted)
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, errs["1"])
+	assert.NoError(t, errs["2"])
+	assert.ErrorContains(t, errs["bad"], "value too long")
+}
+
+func TestWriter_FlushSpanLinksClicks(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	var flushSpan oteltrace.SpanContext
+	mock := &model.MockClicksModel{
+		InsertBatchWithRollupsFunc: func(ctx context.Context, data []*model.Clicks) ([]string, error) {
+			flushSpan = oteltrace.SpanContextFromContext(ctx)
+			return []string{"0", "1"}, nil
+		},
+	}
+	writer := NewWriter(config.BatchConf{Size: 2, Linger: 60000, Timeout: 1000}, mock)
+
+	clickSpans := make([]oteltrace.SpanContext, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		ctx, span := provider.Tracer("test").Start(context.Background(), "click-event consume")
+		clickSpans[i] = span.SpanContext()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer span.End()
+			_, err := writer.Write(ctx, click(strconv.Itoa(i)))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	var flush sdktrace.ReadOnlySpan
+	var inserts []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case flushSpanName:
+			flush = span
+		case insertSpanName:
+			inserts = append(inserts, span)
+		}
+	}
+	require.NotNil(t, flush)
+	assert.Equal(t, flush.SpanContext(), flushSpan, "the insert runs in the flush span")
+	assert.False(t, flush.Parent().IsValid(), "a batch starts its own trace")
+	var linked []oteltrace.SpanContext
+	for _, link := range flush.Links() {
+		linked = append(linked, link.SpanContext)
+	}
+	assert.ElementsMatch(t, clickSpans, linked)
+
+	// Each click's trace shows its part of the insert
+	var parents []oteltrace.SpanContext
+	for _, insert := range inserts {
+		parents = append(parents, insert.Parent())
+		require.Len(t, insert.Links(), 1)
+		assert.Equal(t, flush.SpanContext(), insert.Links()[0].SpanContext)
+		assert.False(t, insert.StartTime().After(flush.StartTime()), "starts with the flush")
+	}
+	assert.ElementsMatch(t, clickSpans, parents)
+}
@@ -1,17 +1,26 @@
 package config
 
 import (
+	"go-shortener/common/eventbus"
+	"go-shortener/common/health"
+
 	"github.com/zeromicro/go-queue/kq"
 	"github.com/zeromicro/go-zero/core/service"
 )
 
 type Config struct {
 	service.ServiceConf
-	DataSource      string
-	Pool            PoolConfig
+	DataSource      string     `json:",inherit"`
+	Pool            PoolConfig `json:",inherit"`
 	KqConsumerConf  kq.KqConf
+	EventBus        eventbus.Conf
 	GeoIPPath       string `json:",optional"`
 	HealthCheckPort int    `json:",default=8082"`
+	Readiness       health.Conf
+	Batch           BatchConf
+	Retry           RetryConf
+	DeadLetter      DeadLetterConf
+	Partitions      PartitionConf
 }
 
 type PoolConfig struct {
@@ -19,3 +28,48 @@ type PoolConfig struct {
 	MaxIdleConns    int `json:",default=5"`
 	ConnMaxLifetime int `json:",default=3600"` // seconds
 }
+
+// BatchConf controls how clicks are grouped into multi-row inserts. A batch is
+// flushed once it holds Size clicks or its first click has waited Linger.
+// Each message's offset is committed only after its batch is stored, which
+// takes ForceCommit: false and CommitInOrder: true in KqConsumerConf, so a
+// batch never holds more than KqConsumerConf.Processors clicks; set
+// Processors to at least Size.
+type BatchConf struct {
+	Size   int `json:",default=100,range=[1:1000]"`
+	Linger int `json:",default=50"` // milliseconds
+	// Timeout bounds a flush in milliseconds. A batch holds the clicks of
+	// many callers, so it is not cancelled with the one that filled it.
+	Timeout int `json:",default=10000"`
+}
+
+// RetryConf bounds how often a click that fails to be stored is retried
+// before it is dead-lettered. The delay starts at Backoff and doubles after
+// each attempt, up to MaxBackoff.
+type RetryConf struct {
+	MaxAttempts int `json:",default=5,range=[1:100]"`
+	Backoff     int `json:",default=100"`  // milliseconds
+	MaxBackoff  int `json:",default=5000"` // milliseconds
+}
+
+// DeadLetterConf controls the topic receiving poison messages and clicks that
+// exhausted their retries. Publishing is retried until the topic accepts the
+// message. When disabled, poison messages are dropped and exhausted clicks
+// are left uncommitted on the event bus, to be delivered again later. The topic is on Kafka, so it is
+// only used with the kafka event bus driver.
+type DeadLetterConf struct {
+	Enabled bool     `json:",default=true"`
+	Brokers []string `json:",optional"` // defaults to KqConsumerConf.Brokers
+	Topic   string   `json:",default=click-events-dlq"`
+}
+
+// PartitionConf controls the manager that keeps monthly clicks partitions in
+// place ahead of time and enforces raw click retention. Hourly and daily
+// rollups are not partitioned and outlive the raw clicks.
+type PartitionConf struct {
+	Enabled       bool `json:",default=true"`
+	Premake       int  `json:",default=3"`    // months created ahead of the current one
+	Retention     int  `json:",default=13"`   // months of raw clicks kept; 0 keeps them forever
+	Detach        bool `json:",optional"`     // detach expired partitions and archive expired default-partition clicks
+	CheckInterval int  `json:",default=3600"` // seconds
+}
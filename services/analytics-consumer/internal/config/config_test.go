@@ -0,0 +1,28 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// TestConfig_CommitsStoredClicksOnly guards against go-queue's defaults,
+// which commit failed and out of order messages and so lose clicks.
+func TestConfig_CommitsStoredClicksOnly(t *testing.T) {
+	files, err := filepath.Glob("../../etc/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			var c Config
+			require.NoError(t, conf.Load(file, &c))
+			assert.False(t, c.KqConsumerConf.ForceCommit, "ForceCommit")
+			assert.True(t, c.KqConsumerConf.CommitInOrder, "CommitInOrder")
+			assert.GreaterOrEqual(t, c.KqConsumerConf.Processors, c.Batch.Size, "Processors")
+		})
+	}
+}
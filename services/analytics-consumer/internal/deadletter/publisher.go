@@ -0,0 +1,99 @@
+package deadletter
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go-shortener/common/events"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers attached to every dead-lettered message. The original key, value
+// and headers are kept unchanged so the message can be replayed as is.
+const (
+	HeaderError       = "x-dlq-error"
+	HeaderStage       = "x-dlq-stage"
+	HeaderAttempts    = "x-dlq-attempts"
+	HeaderSourceTopic = "x-dlq-source-topic"
+	HeaderFailedAt    = "x-dlq-failed-at"
+)
+
+// Stages at which a message can fail.
+const (
+	// StageDecode marks poison messages that can never be processed.
+	StageDecode = "decode"
+	// StageStore marks messages that exhausted their retry budget.
+	StageStore = "store"
+)
+
+// Message is a failed message and why it failed.
+type Message struct {
+	Key   string
+	Value string
+	// ContentType is kept as the content-type header so replays decode the
+	// value as originally published.
+	ContentType string
+	Stage       string
+	Err         error
+	Attempts    int
+}
+
+// Publisher writes failed messages to the dead-letter topic.
+type Publisher struct {
+	writer      *kafka.Writer
+	sourceTopic string
+	now         func() time.Time
+}
+
+// NewPublisher returns a Publisher writing to topic on brokers. Writes are
+// synchronous and acknowledged by all replicas, since the source message is
+// committed as soon as Publish returns. They are not held back to fill a
+// batch.
+func NewPublisher(brokers []string, topic, sourceTopic string) *Publisher {
+	return &Publisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			BatchTimeout:           time.Millisecond,
+			AllowAutoTopicCreation: true,
+		},
+		sourceTopic: sourceTopic,
+		now:         time.Now,
+	}
+}
+
+// Publish writes msg to the dead-letter topic with error metadata headers.
+func (p *Publisher) Publish(ctx context.Context, msg Message) error {
+	return p.writer.WriteMessages(ctx, p.message(msg))
+}
+
+func (p *Publisher) message(msg Message) kafka.Message {
+	reason := ""
+	if msg.Err != nil {
+		reason = msg.Err.Error()
+	}
+	headers := []kafka.Header{
+		{Key: HeaderError, Value: []byte(reason)},
+		{Key: HeaderStage, Value: []byte(msg.Stage)},
+		{Key: HeaderAttempts, Value: []byte(strconv.Itoa(msg.Attempts))},
+		{Key: HeaderSourceTopic, Value: []byte(p.sourceTopic)},
+		{Key: HeaderFailedAt, Value: []byte(p.now().UTC().Format(time.RFC3339))},
+	}
+	if msg.ContentType != "" {
+		headers = append(headers, kafka.Header{Key: events.HeaderContentType, Value: []byte(msg.ContentType)})
+	}
+	return kafka.Message{
+		Key:     []byte(msg.Key),
+		Value:   []byte(msg.Value),
+		Headers: headers,
+	}
+}
+
+// Close flushes and closes the underlying writer.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
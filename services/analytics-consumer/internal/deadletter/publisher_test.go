@@ -0,0 +1,36 @@
+package deadletter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/common/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublisher_MessageHeaders(t *testing.T) {
+	publisher := NewPublisher([]string{"localhost:9092"}, "click-events-dlq", "click-events")
+	publisher.now = func() time.Time {
+		return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	msg := publisher.message(Message{
+		Key:         "abc12345",
+		Value:       `{"short_code":"abc12345"}`,
+		ContentType: events.ContentTypeJSON,
+		Stage:       StageStore,
+		Err:         errors.New("database connection error"),
+		Attempts:    5,
+	})
+
+	assert.Equal(t, "abc12345", string(msg.Key))
+	assert.Equal(t, `{"short_code":"abc12345"}`, string(msg.Value))
+	assert.Equal(t, "database connection error", header(msg, HeaderError))
+	assert.Equal(t, StageStore, header(msg, HeaderStage))
+	assert.Equal(t, "5", header(msg, HeaderAttempts))
+	assert.Equal(t, "click-events", header(msg, HeaderSourceTopic))
+	assert.Equal(t, "2025-06-01T12:00:00Z", header(msg, HeaderFailedAt))
+	assert.Equal(t, events.ContentTypeJSON, header(msg, events.HeaderContentType))
+}
@@ -0,0 +1,96 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Reader and Writer are the parts of kafka.Reader and kafka.Writer a
+// Replayer uses, for testability.
+type (
+	Reader interface {
+		FetchMessage(ctx context.Context) (kafka.Message, error)
+		CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	}
+
+	Writer interface {
+		WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	}
+)
+
+// Replayer moves dead-lettered messages back to the source topic.
+type Replayer struct {
+	reader Reader
+	writer Writer
+	// idle ends the replay once no message arrived for this long.
+	idle time.Duration
+}
+
+// NewReplayer returns a Replayer reading with reader and re-injecting with
+// writer. Each message is committed on the dead-letter topic only after it is
+// written to the source topic.
+func NewReplayer(reader Reader, writer Writer, idle time.Duration) *Replayer {
+	return &Replayer{
+		reader: reader,
+		writer: writer,
+		idle:   idle,
+	}
+}
+
+// Run replays up to limit messages, or all of them when limit is 0, and
+// returns how many were replayed. It stops early when ctx is done or the
+// dead-letter topic stays idle.
+func (r *Replayer) Run(ctx context.Context, limit int) (int, error) {
+	replayed := 0
+	for limit == 0 || replayed < limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, r.idle)
+		msg, err := r.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				return replayed, nil
+			}
+			return replayed, err
+		}
+
+		logx.Infow("replaying dead-lettered message",
+			logx.Field("offset", msg.Offset),
+			logx.Field("stage", header(msg, HeaderStage)),
+			logx.Field("error", header(msg, HeaderError)),
+		)
+		if err := r.writer.WriteMessages(ctx, replay(msg)); err != nil {
+			return replayed, err
+		}
+		if err := r.reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// replay strips the dead-letter headers, keeping the original key, value and
+// any other headers such as trace context.
+func replay(msg kafka.Message) kafka.Message {
+	out := kafka.Message{Key: msg.Key, Value: msg.Value}
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, "x-dlq-") {
+			out.Headers = append(out.Headers, h)
+		}
+	}
+	return out
+}
+
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
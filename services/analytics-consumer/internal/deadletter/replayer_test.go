@@ -0,0 +1,96 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves queued messages, then blocks until the fetch times out.
+type fakeReader struct {
+	messages  []kafka.Message
+	committed []kafka.Message
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+type fakeWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func deadLettered(value string) kafka.Message {
+	return kafka.Message{
+		Key:   []byte("abc12345"),
+		Value: []byte(value),
+		Headers: []kafka.Header{
+			{Key: "traceparent", Value: []byte("00-0123456789abcdef0123456789abcdef-0123456789abcdef-01")},
+			{Key: HeaderError, Value: []byte("database connection error")},
+			{Key: HeaderStage, Value: []byte(StageStore)},
+		},
+	}
+}
+
+func TestReplayer_ReplaysUntilIdle(t *testing.T) {
+	reader := &fakeReader{messages: []kafka.Message{deadLettered("1"), deadLettered("2")}}
+	writer := &fakeWriter{}
+
+	replayed, err := NewReplayer(reader, writer, 10*time.Millisecond).Run(context.Background(), 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed)
+	require.Len(t, writer.written, 2)
+	assert.Equal(t, "abc12345", string(writer.written[0].Key))
+	assert.Equal(t, "1", string(writer.written[0].Value))
+	assert.Equal(t, []kafka.Header{{Key: "traceparent", Value: []byte("00-0123456789abcdef0123456789abcdef-0123456789abcdef-01")}},
+		writer.written[0].Headers, "dead-letter headers are stripped, others kept")
+	assert.Len(t, reader.committed, 2)
+}
+
+func TestReplayer_Limit(t *testing.T) {
+	reader := &fakeReader{messages: []kafka.Message{deadLettered("1"), deadLettered("2")}}
+	writer := &fakeWriter{}
+
+	replayed, err := NewReplayer(reader, writer, time.Second).Run(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Len(t, reader.messages, 1)
+}
+
+func TestReplayer_WriteErrorLeavesMessageUncommitted(t *testing.T) {
+	reader := &fakeReader{messages: []kafka.Message{deadLettered("1")}}
+	writer := &fakeWriter{err: errors.New("broker unavailable")}
+
+	replayed, err := NewReplayer(reader, writer, time.Second).Run(context.Background(), 0)
+
+	require.Error(t, err)
+	assert.Equal(t, 0, replayed)
+	assert.Empty(t, reader.committed)
+}
@@ -2,39 +2,237 @@ package mqs
 
 import (
 	"context"
-	"encoding/json"
 	"net"
 	"strings"
 	"time"
 
+	"go-shortener/common/eventbus"
 	"go-shortener/common/events"
+	"go-shortener/services/analytics-consumer/internal/deadletter"
 	"go-shortener/services/analytics-consumer/internal/svc"
 	"go-shortener/services/analytics-rpc/model"
 
 	"github.com/google/uuid"
 	"github.com/mssola/useragent"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/metric"
+	"github.com/zeromicro/go-zero/core/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// consumeSpanName names the span covering the handling of one click event.
+const consumeSpanName = "click-event consume"
+
+const (
+	enrichmentGeoIPMiss = "geoip_miss"
+	enrichmentBot       = "bot"
+)
+
+var (
+	clickConsumed = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "consumed_total",
+		Help:      "Click events received from the event bus.",
+	})
+	clickProcessed = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "processed_total",
+		Help:      "Click events enriched and stored.",
+	})
+	clickFailed = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "failed_total",
+		Help:      "Click events given up on, by stage: decode or store.",
+		Labels:    []string{"stage"},
+	})
+	// clickDuplicates counts redelivered click events skipped as already stored.
+	clickDuplicates = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "duplicates_total",
+		Help:      "Redelivered click events skipped because they were already stored.",
+	})
+	clickEnrichment = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "enrichment_total",
+		Help:      "Notable enrichment outcomes: geoip_miss for IPs without a country, bot for crawler clicks.",
+		Labels:    []string{"outcome"},
+	})
+	// clickLag is the age of the last click handled, a time-based consumer lag
+	// that works for every event bus driver.
+	clickLag = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: "analytics_consumer",
+		Subsystem: "clicks",
+		Name:      "lag_seconds",
+		Help:      "Seconds between the last consumed click and its handling.",
+	})
 )
 
 type ClickEventConsumer struct {
 	svcCtx *svc.ServiceContext
+	// sleep waits d or until ctx is done.
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
 func NewClickEventConsumer(ctx context.Context, svcCtx *svc.ServiceContext) *ClickEventConsumer {
 	return &ClickEventConsumer{
 		svcCtx: svcCtx,
+		sleep:  sleep,
 	}
 }
 
-func (c *ClickEventConsumer) Consume(ctx context.Context, key, val string) error {
+func (c *ClickEventConsumer) Consume(ctx context.Context, msg eventbus.Message) error {
+	// The event bus extracts the producer's traceparent into ctx as a remote
+	// span context, so the span continues the redirect's trace.
+	ctx, span := otel.Tracer(trace.TraceName).Start(ctx, consumeSpanName,
+		oteltrace.WithSpanKind(oteltrace.SpanKindConsumer),
+		oteltrace.WithAttributes(attribute.String("messaging.message.id", msg.Key)),
+	)
+	defer span.End()
+
+	err := c.consume(ctx, msg.Key, msg.Value, msg.Headers[events.HeaderContentType])
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// consume dispatches by the content-type header; events published before it
+// existed are JSON.
+func (c *ClickEventConsumer) consume(ctx context.Context, key, val, contentType string) error {
 	logx.WithContext(ctx).Infof("ClickEventConsumer received: key=%s", key)
+	clickConsumed.Inc()
+
+	event, err := events.DecodeClick(contentType, []byte(val))
+	if err != nil {
+		logx.WithContext(ctx).Errorf("failed to decode click event: %v", err)
+		// Don't retry malformed messages
+		return c.deadLetter(ctx, deadletter.Message{
+			Key:         key,
+			Value:       val,
+			ContentType: contentType,
+			Stage:       deadletter.StageDecode,
+			Err:         err,
+			Attempts:    1,
+		})
+	}
+
+	oteltrace.SpanFromContext(ctx).SetAttributes(attribute.String("click.short_code", event.ShortCode))
+	clickLag.Set(time.Since(time.Unix(event.Timestamp, 0)).Seconds())
+	attempts, err := c.storeWithRetry(ctx, event)
+	if err != nil && ctx.Err() != nil {
+		// Stopping: leave the click to be delivered again
+		return err
+	}
+	if err != nil {
+		return c.deadLetter(ctx, deadletter.Message{
+			Key:         key,
+			Value:       val,
+			ContentType: contentType,
+			Stage:       deadletter.StageStore,
+			Err:         err,
+			Attempts:    attempts,
+		})
+	}
+
+	return nil
+}
+
+// storeWithRetry stores the click, retrying with exponential backoff up to
+// the configured number of attempts or until ctx is done. It returns the
+// attempts made.
+func (c *ClickEventConsumer) storeWithRetry(ctx context.Context, event *events.ClickEvent) (int, error) {
+	retry := c.svcCtx.Config.Retry
+	maxAttempts := max(retry.MaxAttempts, 1)
+	backoff := time.Duration(retry.Backoff) * time.Millisecond
+	maxBackoff := time.Duration(retry.MaxBackoff) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		err := c.store(ctx, event)
+		if err == nil || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		logx.WithContext(ctx).Infow("retrying click event",
+			logx.Field("short_code", event.ShortCode),
+			logx.Field("attempt", attempt),
+			logx.Field("backoff", backoff.String()),
+			logx.Field("error", err.Error()),
+		)
+		if err := c.sleep(ctx, backoff); err != nil {
+			return attempt, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// deadLetter publishes a message the consumer gives up on, retrying until
+// the dead-letter topic accepts it or ctx is done. Without a dead-letter
+// topic, poison messages are dropped and store failures are returned to the
+// event bus. A returned error leaves the message uncommitted only because
+// KqConsumerConf sets ForceCommit to false; it is then delivered again after
+// a restart or rebalance.
+func (c *ClickEventConsumer) deadLetter(ctx context.Context, msg deadletter.Message) error {
+	clickFailed.Inc(msg.Stage)
+	oteltrace.SpanFromContext(ctx).RecordError(msg.Err, oteltrace.WithAttributes(
+		attribute.String("click.stage", msg.Stage),
+		attribute.Int("click.attempts", msg.Attempts),
+	))
 
-	var event events.ClickEvent
-	if err := json.Unmarshal([]byte(val), &event); err != nil {
-		logx.WithContext(ctx).Errorf("failed to unmarshal click event: %v", err)
-		return nil // Don't retry malformed messages
+	if c.svcCtx.DeadLetter == nil {
+		if msg.Stage == deadletter.StageDecode {
+			return nil
+		}
+		return msg.Err
+	}
+
+	retry := c.svcCtx.Config.Retry
+	backoff := time.Duration(retry.Backoff) * time.Millisecond
+	maxBackoff := time.Duration(retry.MaxBackoff) * time.Millisecond
+	for {
+		err := c.svcCtx.DeadLetter.Publish(ctx, msg)
+		if err == nil {
+			break
+		}
+		logx.WithContext(ctx).Errorw("failed to publish to dead-letter topic",
+			logx.Field("backoff", backoff.String()),
+			logx.Field("error", err.Error()),
+		)
+		if err := c.sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	logx.WithContext(ctx).Errorw("click event dead-lettered",
+		logx.Field("stage", msg.Stage),
+		logx.Field("attempts", msg.Attempts),
+		logx.Field("error", msg.Err.Error()),
+	)
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
+// store enriches and stores a decoded click event.
+func (c *ClickEventConsumer) store(ctx context.Context, event *events.ClickEvent) error {
 	// Enrich with GeoIP
 	countryCode := resolveCountry(c.svcCtx, event.IP)
 
@@ -44,8 +242,7 @@ func (c *ClickEventConsumer) Consume(ctx context.Context, key, val string) error
 	// Enrich with traffic source from Referer
 	trafficSource := resolveTrafficSource(event.Referer)
 
-	// Generate UUIDv7 for the click record
-	id, err := uuid.NewV7()
+	id, err := clickID(event)
 	if err != nil {
 		logx.WithContext(ctx).Errorf("failed to generate UUIDv7: %v", err)
 		return err
@@ -53,22 +250,44 @@ func (c *ClickEventConsumer) Consume(ctx context.Context, key, val string) error
 
 	clickedAt := time.Unix(event.Timestamp, 0)
 
-	_, insertErr := c.svcCtx.ClickModel.Insert(ctx, &model.Clicks{
-		Id:            id.String(),
+	visitorHash, err := resolveVisitor(ctx, c.svcCtx, clickedAt, event.IP, event.UserAgent)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("failed to derive visitor hash: %v", err)
+		return err
+	}
+
+	// Blocks until the click's batch is flushed, so the offset is committed
+	// only once the click is stored.
+	inserted, err := c.svcCtx.ClickWriter.Write(ctx, &model.Clicks{
+		Id:            id,
 		ShortCode:     event.ShortCode,
 		ClickedAt:     clickedAt,
 		CountryCode:   countryCode,
 		DeviceType:    deviceType,
 		TrafficSource: trafficSource,
+		Variant:       event.Variant,
+		VisitorHash:   visitorHash,
 	})
-	if insertErr != nil {
-		// Check for duplicate key (idempotent handling)
-		if strings.Contains(insertErr.Error(), "duplicate key") {
-			logx.WithContext(ctx).Infof("duplicate click event, skipping: short_code=%s", event.ShortCode)
-			return nil
-		}
-		logx.WithContext(ctx).Errorf("failed to insert click: %v", insertErr)
-		return insertErr
+	if err != nil {
+		logx.WithContext(ctx).Errorf("failed to insert click: %v", err)
+		return err
+	}
+	oteltrace.SpanFromContext(ctx).SetAttributes(attribute.Bool("click.inserted", inserted))
+	if !inserted {
+		// Redelivered event (idempotent handling)
+		clickDuplicates.Inc()
+		logx.WithContext(ctx).Infof("duplicate click event, skipping: short_code=%s event_id=%s",
+			event.ShortCode, event.EventID)
+		return nil
+	}
+
+	// Counted once stored, as store runs again on retries.
+	clickProcessed.Inc()
+	if c.svcCtx.GeoDB != nil && countryCode == "XX" {
+		clickEnrichment.Inc(enrichmentGeoIPMiss)
+	}
+	if deviceType == "Bot" {
+		clickEnrichment.Inc(enrichmentBot)
 	}
 
 	logx.WithContext(ctx).Infow("click event processed",
@@ -76,11 +295,26 @@ func (c *ClickEventConsumer) Consume(ctx context.Context, key, val string) error
 		logx.Field("country", countryCode),
 		logx.Field("device", deviceType),
 		logx.Field("source", trafficSource),
+		logx.Field("variant", event.Variant),
 	)
 
 	return nil
 }
 
+// clickID returns the id to store the click under. Redeliveries of an event
+// share its EventID and timestamp, so they collide on the clicks primary key.
+// Events without an EventID get a fresh UUIDv7 and cannot be deduplicated.
+func clickID(event *events.ClickEvent) (string, error) {
+	if event.EventID != "" {
+		return event.EventID, nil
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
 // resolveCountry looks up the country code from IP using GeoIP database.
 // Falls back to "XX" if GeoIP is unavailable or lookup fails.
 func resolveCountry(svcCtx *svc.ServiceContext, ip string) string {
@@ -106,6 +340,15 @@ func resolveCountry(svcCtx *svc.ServiceContext, ip string) string {
 	return code
 }
 
+// resolveVisitor returns the salted visitor fingerprint for unique counts.
+// Returns an empty hash when fingerprinting is disabled.
+func resolveVisitor(ctx context.Context, svcCtx *svc.ServiceContext, clickedAt time.Time, ip, userAgent string) (string, error) {
+	if svcCtx.Visitors == nil {
+		return "", nil
+	}
+	return svcCtx.Visitors.Hash(ctx, clickedAt, ip, userAgent)
+}
+
 // resolveDeviceType parses the User-Agent string to determine device type.
 func resolveDeviceType(userAgent string) string {
 	if userAgent == "" {
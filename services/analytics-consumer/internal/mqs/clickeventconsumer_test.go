@@ -2,21 +2,28 @@ package mqs
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"net"
 	"testing"
 	"time"
 
+	"go-shortener/common/eventbus"
 	"go-shortener/common/events"
 	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-consumer/internal/deadletter"
 	"go-shortener/services/analytics-consumer/internal/svc"
 	"go-shortener/services/analytics-rpc/model"
 
 	"github.com/oschwald/geoip2-golang"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // mockGeoIPReader implements svc.GeoIPReader for testing.
@@ -28,20 +35,54 @@ func (m *mockGeoIPReader) Country(ip net.IP) (*geoip2.Country, error) {
 	return m.countryFunc(ip)
 }
 
+// mockClickWriter implements svc.ClickWriter for testing.
+type mockClickWriter struct {
+	writeFunc func(ctx context.Context, click *model.Clicks) (bool, error)
+}
+
+func (m *mockClickWriter) Write(ctx context.Context, click *model.Clicks) (bool, error) {
+	return m.writeFunc(ctx, click)
+}
+
+// mockDeadLetter implements svc.DeadLetterPublisher for testing.
+type mockDeadLetter struct {
+	published []deadletter.Message
+	errs      []error // returned by successive calls, then nil
+}
+
+func (m *mockDeadLetter) Publish(ctx context.Context, msg deadletter.Message) error {
+	m.published = append(m.published, msg)
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+// mockVisitorHasher implements svc.VisitorHasher for testing.
+type mockVisitorHasher struct {
+	hashFunc func(ctx context.Context, t time.Time, ip, userAgent string) (string, error)
+}
+
+func (m *mockVisitorHasher) Hash(ctx context.Context, t time.Time, ip, userAgent string) (string, error) {
+	return m.hashFunc(ctx, t, ip, userAgent)
+}
+
 func TestClickEventConsumer_Success(t *testing.T) {
 	var insertedClick *model.Clicks
 
-	mockModel := &model.MockClicksModel{
-		InsertFunc: func(ctx context.Context, data *model.Clicks) (sql.Result, error) {
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
 			insertedClick = data
-			return nil, nil
+			return true, nil
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:     config.Config{},
-		ClickModel: mockModel,
-		GeoDB:      nil, // No GeoIP database
+		Config:      config.Config{},
+		ClickWriter: writer,
+		GeoDB:       nil, // No GeoIP database
 	}
 
 	consumer := NewClickEventConsumer(context.Background(), svcCtx)
@@ -55,7 +96,7 @@ func TestClickEventConsumer_Success(t *testing.T) {
 	}
 
 	payload, _ := json.Marshal(event)
-	err := consumer.Consume(context.Background(), "", string(payload))
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
 
 	require.NoError(t, err)
 	require.NotNil(t, insertedClick)
@@ -64,42 +105,180 @@ func TestClickEventConsumer_Success(t *testing.T) {
 	assert.Equal(t, "Bot", insertedClick.DeviceType)
 	assert.Equal(t, "Search", insertedClick.TrafficSource)
 	assert.NotEmpty(t, insertedClick.Id)
+	assert.Empty(t, insertedClick.Variant, "single-destination clicks have no variant")
+}
+
+func TestClickEventConsumer_ContinuesProducerTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	// Round-trip the producer span through message headers as go-queue does.
+	_, producer := provider.Tracer("test").Start(context.Background(), "click-event publish")
+	producer.End()
+	headers := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(oteltrace.ContextWithSpan(context.Background(), producer), headers)
+	ctx := propagation.TraceContext{}.Extract(context.Background(), headers)
+
+	var writeSpan oteltrace.SpanContext
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			writeSpan = oteltrace.SpanContextFromContext(ctx)
+			return true, nil
+		},
+	}
+	consumer := NewClickEventConsumer(context.Background(), &svc.ServiceContext{ClickWriter: writer})
+
+	payload, _ := json.Marshal(events.ClickEvent{ShortCode: "abc12345", Timestamp: time.Now().Unix()})
+	require.NoError(t, consumer.Consume(ctx, eventbus.Message{Key: "key", Value: string(payload)}))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	consume := spans[1]
+	assert.Equal(t, consumeSpanName, consume.Name())
+	assert.Equal(t, oteltrace.SpanKindConsumer, consume.SpanKind())
+	assert.Equal(t, producer.SpanContext().TraceID(), consume.SpanContext().TraceID(),
+		"the click is handled in the redirect's trace")
+	assert.Equal(t, producer.SpanContext().SpanID(), consume.Parent().SpanID())
+	assert.Equal(t, consume.SpanContext(), writeSpan, "the insert runs in the consume span")
+}
+
+func TestClickEventConsumer_Variant(t *testing.T) {
+	var insertedClick *model.Clicks
+
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			insertedClick = data
+			return true, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{},
+		ClickWriter: writer,
+		GeoDB:       nil,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	event := events.ClickEvent{
+		ShortCode: "abc12345",
+		Timestamp: time.Now().Unix(),
+		IP:        "1.2.3.4",
+		UserAgent: "Mozilla/5.0",
+		Variant:   "b",
+	}
+
+	payload, _ := json.Marshal(event)
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
+
+	require.NoError(t, err)
+	require.NotNil(t, insertedClick)
+	assert.Equal(t, "b", insertedClick.Variant)
+}
+
+func TestClickEventConsumer_VisitorHash(t *testing.T) {
+	var insertedClick *model.Clicks
+
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			insertedClick = data
+			return true, nil
+		},
+	}
+
+	clickedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{},
+		ClickWriter: writer,
+		Visitors: &mockVisitorHasher{
+			hashFunc: func(ctx context.Context, at time.Time, ip, userAgent string) (string, error) {
+				assert.True(t, clickedAt.Equal(at))
+				assert.Equal(t, "1.2.3.4", ip)
+				assert.Equal(t, "Mozilla/5.0", userAgent)
+				return "0123456789abcdef0123456789abcdef", nil
+			},
+		},
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	event := events.ClickEvent{
+		ShortCode: "abc12345",
+		Timestamp: clickedAt.Unix(),
+		IP:        "1.2.3.4",
+		UserAgent: "Mozilla/5.0",
+	}
+
+	payload, _ := json.Marshal(event)
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
+
+	require.NoError(t, err)
+	require.NotNil(t, insertedClick)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", insertedClick.VisitorHash)
+}
+
+func TestClickEventConsumer_VisitorHashError(t *testing.T) {
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			t.Fatal("Write should not be called without a visitor hash")
+			return false, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{},
+		ClickWriter: writer,
+		Visitors: &mockVisitorHasher{
+			hashFunc: func(ctx context.Context, at time.Time, ip, userAgent string) (string, error) {
+				return "", errors.New("database connection timeout")
+			},
+		},
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	payload, _ := json.Marshal(events.ClickEvent{ShortCode: "abc12345", Timestamp: time.Now().Unix(), IP: "1.2.3.4"})
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
+
+	assert.Error(t, err, "salt lookup failures should be retried")
 }
 
 func TestClickEventConsumer_InvalidJSON(t *testing.T) {
-	mockModel := &model.MockClicksModel{
-		InsertFunc: func(ctx context.Context, data *model.Clicks) (sql.Result, error) {
-			t.Fatal("Insert should not be called for invalid JSON")
-			return nil, nil
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			t.Fatal("Write should not be called for invalid JSON")
+			return false, nil
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:     config.Config{},
-		ClickModel: mockModel,
-		GeoDB:      nil,
+		Config:      config.Config{},
+		ClickWriter: writer,
+		GeoDB:       nil,
 	}
 
 	consumer := NewClickEventConsumer(context.Background(), svcCtx)
 
 	// Malformed JSON
-	err := consumer.Consume(context.Background(), "", "{invalid json")
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: "{invalid json"})
 
 	// Should return nil (skip, don't retry)
 	assert.NoError(t, err, "malformed JSON should return nil to skip message")
 }
 
 func TestClickEventConsumer_DuplicateKey(t *testing.T) {
-	mockModel := &model.MockClicksModel{
-		InsertFunc: func(ctx context.Context, data *model.Clicks) (sql.Result, error) {
-			return nil, errors.New("duplicate key value violates unique constraint")
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			return false, nil
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:     config.Config{},
-		ClickModel: mockModel,
-		GeoDB:      nil,
+		Config:      config.Config{},
+		ClickWriter: writer,
+		GeoDB:       nil,
 	}
 
 	consumer := NewClickEventConsumer(context.Background(), svcCtx)
@@ -113,23 +292,23 @@ func TestClickEventConsumer_DuplicateKey(t *testing.T) {
 	}
 
 	payload, _ := json.Marshal(event)
-	err := consumer.Consume(context.Background(), "", string(payload))
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
 
 	// Should return nil (idempotent handling)
-	assert.NoError(t, err, "duplicate key should return nil for idempotency")
+	assert.NoError(t, err, "duplicates should return nil for idempotency")
 }
 
 func TestClickEventConsumer_DBError(t *testing.T) {
-	mockModel := &model.MockClicksModel{
-		InsertFunc: func(ctx context.Context, data *model.Clicks) (sql.Result, error) {
-			return nil, errors.New("database connection error")
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			return false, errors.New("database connection error")
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:     config.Config{},
-		ClickModel: mockModel,
-		GeoDB:      nil,
+		Config:      config.Config{},
+		ClickWriter: writer,
+		GeoDB:       nil,
 	}
 
 	consumer := NewClickEventConsumer(context.Background(), svcCtx)
@@ -143,13 +322,262 @@ func TestClickEventConsumer_DBError(t *testing.T) {
 	}
 
 	payload, _ := json.Marshal(event)
-	err := consumer.Consume(context.Background(), "", string(payload))
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
 
 	// Should return error (retry)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "database connection error")
 }
 
+func TestClickEventConsumer_RetriesWithBackoff(t *testing.T) {
+	calls := 0
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			calls++
+			if calls < 3 {
+				return false, errors.New("database connection error")
+			}
+			return true, nil
+		},
+	}
+	deadLetter := &mockDeadLetter{}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{Retry: config.RetryConf{MaxAttempts: 5, Backoff: 100, MaxBackoff: 150}},
+		ClickWriter: writer,
+		DeadLetter:  deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	var sleeps []time.Duration
+	consumer.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+
+	payload, _ := json.Marshal(events.ClickEvent{ShortCode: "abc12345", Timestamp: time.Now().Unix()})
+	err := consumer.Consume(context.Background(), eventbus.Message{Key: "abc12345", Value: string(payload)})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 150 * time.Millisecond}, sleeps)
+	assert.Empty(t, deadLetter.published)
+}
+
+func TestClickEventConsumer_DeadLettersExhaustedRetries(t *testing.T) {
+	calls := 0
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			calls++
+			return false, errors.New("database connection error")
+		},
+	}
+	deadLetter := &mockDeadLetter{}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{Retry: config.RetryConf{MaxAttempts: 3, Backoff: 100, MaxBackoff: 5000}},
+		ClickWriter: writer,
+		DeadLetter:  deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	consumer.sleep = func(context.Context, time.Duration) error { return nil }
+
+	payload, _ := json.Marshal(events.ClickEvent{ShortCode: "abc12345", Timestamp: time.Now().Unix()})
+	err := consumer.Consume(context.Background(), eventbus.Message{Key: "abc12345", Value: string(payload)})
+
+	require.NoError(t, err, "dead-lettered messages are committed")
+	assert.Equal(t, 3, calls)
+	require.Len(t, deadLetter.published, 1)
+	msg := deadLetter.published[0]
+	assert.Equal(t, "abc12345", msg.Key)
+	assert.Equal(t, string(payload), msg.Value)
+	assert.Equal(t, deadletter.StageStore, msg.Stage)
+	assert.Equal(t, 3, msg.Attempts)
+	assert.EqualError(t, msg.Err, "database connection error")
+}
+
+func TestClickEventConsumer_DeadLettersInvalidJSON(t *testing.T) {
+	deadLetter := &mockDeadLetter{}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		DeadLetter: deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: "{invalid json"})
+
+	require.NoError(t, err)
+	require.Len(t, deadLetter.published, 1)
+	assert.Equal(t, deadletter.StageDecode, deadLetter.published[0].Stage)
+	assert.Equal(t, "{invalid json", deadLetter.published[0].Value)
+	assert.Equal(t, 1, deadLetter.published[0].Attempts)
+}
+
+func TestClickEventConsumer_RetriesDeadLetterPublish(t *testing.T) {
+	unavailable := errors.New("broker unavailable")
+	deadLetter := &mockDeadLetter{errs: []error{unavailable, unavailable}}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{Retry: config.RetryConf{Backoff: 100, MaxBackoff: 150}},
+		DeadLetter: deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	var sleeps []time.Duration
+	consumer.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: "{invalid json"})
+
+	require.NoError(t, err)
+	assert.Len(t, deadLetter.published, 3)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 150 * time.Millisecond}, sleeps)
+}
+
+func TestClickEventConsumer_DeadLetterPublishStopsWithContext(t *testing.T) {
+	deadLetter := &mockDeadLetter{errs: []error{errors.New("broker unavailable")}}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{Retry: config.RetryConf{Backoff: 100, MaxBackoff: 150}},
+		DeadLetter: deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := consumer.Consume(ctx, eventbus.Message{Value: "{invalid json"})
+
+	assert.ErrorIs(t, err, context.Canceled, "the message is left uncommitted")
+	assert.Len(t, deadLetter.published, 1)
+}
+
+func TestClickEventConsumer_StoppingSkipsDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			cancel()
+			return false, ctx.Err()
+		},
+	}
+	deadLetter := &mockDeadLetter{}
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{Retry: config.RetryConf{MaxAttempts: 3, Backoff: 100, MaxBackoff: 150}},
+		ClickWriter: writer,
+		DeadLetter:  deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+	payload, _ := json.Marshal(events.ClickEvent{ShortCode: "abc12345", Timestamp: time.Now().Unix()})
+	err := consumer.Consume(ctx, eventbus.Message{Value: string(payload)})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, deadLetter.published)
+}
+
+func TestClickEventConsumer_EventIDIsClickID(t *testing.T) {
+	var ids []string
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			ids = append(ids, data.Id)
+			// The second delivery collides with the stored click
+			return len(ids) == 1, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{},
+		ClickWriter: writer,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	payload, _ := json.Marshal(events.ClickEvent{
+		EventID:   "01978a3c-5f6e-7d8a-9b0c-1d2e3f405162",
+		ShortCode: "abc12345",
+		Timestamp: time.Now().Unix(),
+	})
+	require.NoError(t, consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)}))
+	require.NoError(t, consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)}), "redeliveries are no-ops")
+
+	assert.Equal(t, []string{"01978a3c-5f6e-7d8a-9b0c-1d2e3f405162", "01978a3c-5f6e-7d8a-9b0c-1d2e3f405162"}, ids)
+}
+
+func TestClickEventConsumer_InvalidEventID(t *testing.T) {
+	deadLetter := &mockDeadLetter{}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		DeadLetter: deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	payload, _ := json.Marshal(events.ClickEvent{EventID: "not-a-uuid", ShortCode: "abc12345"})
+	err := consumer.Consume(context.Background(), eventbus.Message{Value: string(payload)})
+
+	require.NoError(t, err)
+	require.Len(t, deadLetter.published, 1)
+	assert.Equal(t, deadletter.StageDecode, deadLetter.published[0].Stage)
+}
+
+func TestClickEventConsumer_DispatchesByContentType(t *testing.T) {
+	var insertedClick *model.Clicks
+	writer := &mockClickWriter{
+		writeFunc: func(ctx context.Context, data *model.Clicks) (bool, error) {
+			insertedClick = data
+			return true, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{},
+		ClickWriter: writer,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	payload, err := events.EncodeClick(&events.ClickEvent{
+		EventID:   "01978a3c-5f6e-7d8a-9b0c-1d2e3f405162",
+		ShortCode: "abc12345",
+		Timestamp: 1748779200,
+		Referer:   "https://reddit.com/r/golang",
+		Variant:   "b",
+	}, events.ContentTypeProtobuf)
+	require.NoError(t, err)
+
+	msg := eventbus.Message{
+		Value:   string(payload),
+		Headers: map[string]string{events.HeaderContentType: events.ContentTypeProtobuf},
+	}
+	require.NoError(t, consumer.Consume(context.Background(), msg))
+
+	require.NotNil(t, insertedClick)
+	assert.Equal(t, "01978a3c-5f6e-7d8a-9b0c-1d2e3f405162", insertedClick.Id)
+	assert.Equal(t, time.Unix(1748779200, 0), insertedClick.ClickedAt)
+	assert.Equal(t, "Social", insertedClick.TrafficSource)
+	assert.Equal(t, "b", insertedClick.Variant)
+}
+
+func TestClickEventConsumer_UnsupportedVersion(t *testing.T) {
+	deadLetter := &mockDeadLetter{}
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		DeadLetter: deadLetter,
+	}
+
+	consumer := NewClickEventConsumer(context.Background(), svcCtx)
+
+	err := consumer.Consume(context.Background(), eventbus.Message{
+		Value:   `{"type":"click","version":99,"payload":{}}`,
+		Headers: map[string]string{events.HeaderContentType: events.ContentTypeJSON},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, deadLetter.published, 1)
+	assert.Equal(t, deadletter.StageDecode, deadLetter.published[0].Stage)
+	assert.Equal(t, events.ContentTypeJSON, deadLetter.published[0].ContentType)
+	assert.ErrorIs(t, deadLetter.published[0].Err, events.ErrUnsupportedVersion)
+}
+
 func TestResolveDeviceType(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,143 @@
+package partition
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Manager keeps the monthly partitions of clicks in shape: it creates the
+// current month and Premake months ahead, and drops or detaches partitions
+// that fall entirely outside the retention window. Expired clicks stranded in
+// the default partition are deleted, or archived when Detach is set. It implements
+// service.Service so it can run in the same service group as the consumer.
+type Manager struct {
+	conf  config.PartitionConf
+	model model.ClickPartitionsModel
+	now   func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewManager returns a Manager for the clicks partitions.
+func NewManager(c config.PartitionConf, m model.ClickPartitionsModel) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		conf:   c,
+		model:  m,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start maintains the partitions every CheckInterval until Stop is called.
+func (m *Manager) Start() {
+	logx.Infof("Clicks partition manager started: premake=%d, retention=%d months",
+		m.conf.Premake, m.conf.Retention)
+
+	ticker := time.NewTicker(time.Duration(m.conf.CheckInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		m.RunOnce(m.ctx)
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends the Start loop.
+func (m *Manager) Stop() {
+	m.once.Do(m.cancel)
+}
+
+// RunOnce creates missing partitions, then expires old ones. Creation runs
+// first so a failing expiry never leaves upcoming clicks without a partition.
+func (m *Manager) RunOnce(ctx context.Context) {
+	now := m.now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= m.conf.Premake; i++ {
+		month := current.AddDate(0, i, 0)
+		if err := m.model.Create(ctx, month); err != nil {
+			logx.WithContext(ctx).Errorw("failed to create clicks partition",
+				logx.Field("partition", model.ClickPartitionName(month)),
+				logx.Field("error", err.Error()),
+			)
+		}
+	}
+
+	if m.conf.Retention <= 0 {
+		return
+	}
+	m.expire(ctx, current.AddDate(0, -m.conf.Retention, 0))
+}
+
+// expire removes the partitions whose month ends at or before cutoff, and the
+// clicks of the default partition before cutoff.
+func (m *Manager) expire(ctx context.Context, cutoff time.Time) {
+	m.expireDefault(ctx, cutoff)
+
+	names, err := m.model.List(ctx)
+	if err != nil {
+		logx.WithContext(ctx).Errorw("failed to list clicks partitions", logx.Field("error", err.Error()))
+		return
+	}
+
+	for _, name := range names {
+		month, err := model.ParseClickPartitionName(name)
+		if err != nil || month.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+
+		action, remove := "dropped", m.model.Drop
+		if m.conf.Detach {
+			action, remove = "detached", m.model.Detach
+		}
+		if err := remove(ctx, name); err != nil {
+			logx.WithContext(ctx).Errorw("failed to expire clicks partition",
+				logx.Field("partition", name),
+				logx.Field("error", err.Error()),
+			)
+			continue
+		}
+		logx.WithContext(ctx).Infow("expired clicks partition",
+			logx.Field("partition", name),
+			logx.Field("action", action),
+		)
+	}
+}
+
+// expireDefault removes the clicks of the default partition before cutoff.
+// They land there when their month had no partition, so no partition drop
+// ever removes them.
+func (m *Manager) expireDefault(ctx context.Context, cutoff time.Time) {
+	removed, err := m.model.ExpireDefault(ctx, cutoff, m.conf.Detach)
+	if err != nil {
+		logx.WithContext(ctx).Errorw("failed to expire default clicks partition", logx.Field("error", err.Error()))
+		return
+	}
+	if removed == 0 {
+		return
+	}
+
+	action := "deleted"
+	if m.conf.Detach {
+		action = "archived"
+	}
+	logx.WithContext(ctx).Infow("expired default clicks partition",
+		logx.Field("clicks", removed),
+		logx.Field("action", action),
+	)
+}
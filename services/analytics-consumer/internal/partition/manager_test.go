@@ -0,0 +1,142 @@
+package partition
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager(c config.PartitionConf, m model.ClickPartitionsModel) *Manager {
+	manager := NewManager(c, m)
+	manager.now = func() time.Time {
+		return time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	}
+	return manager
+}
+
+func TestRunOnce_CreatesAndDrops(t *testing.T) {
+	var created, dropped []string
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error {
+			created = append(created, model.ClickPartitionName(month))
+			return nil
+		},
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"clicks_2024_04", "clicks_2024_05", "clicks_2024_06", "clicks_2025_06", "clicks_default"}, nil
+		},
+		ExpireDefaultFunc: func(ctx context.Context, before time.Time, archive bool) (int64, error) {
+			return 0, nil
+		},
+		DropFunc: func(ctx context.Context, name string) error {
+			dropped = append(dropped, name)
+			return nil
+		},
+	}
+
+	newTestManager(config.PartitionConf{Premake: 2, Retention: 12}, mock).RunOnce(context.Background())
+
+	assert.Equal(t, []string{"clicks_2025_06", "clicks_2025_07", "clicks_2025_08"}, created)
+	assert.Equal(t, []string{"clicks_2024_04", "clicks_2024_05"}, dropped)
+}
+
+func TestRunOnce_Detach(t *testing.T) {
+	var detached []string
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error { return nil },
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"clicks_2025_01", "clicks_2025_06"}, nil
+		},
+		DetachFunc: func(ctx context.Context, name string) error {
+			detached = append(detached, name)
+			return nil
+		},
+		ExpireDefaultFunc: func(ctx context.Context, before time.Time, archive bool) (int64, error) {
+			assert.True(t, archive, "default-partition clicks are archived")
+			return 0, nil
+		},
+	}
+
+	newTestManager(config.PartitionConf{Retention: 3, Detach: true}, mock).RunOnce(context.Background())
+
+	assert.Equal(t, []string{"clicks_2025_01"}, detached)
+}
+
+func TestRunOnce_ExpiresDefaultPartition(t *testing.T) {
+	var cutoffs []time.Time
+	var archived []bool
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error { return nil },
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"clicks_default"}, nil
+		},
+		ExpireDefaultFunc: func(ctx context.Context, before time.Time, archive bool) (int64, error) {
+			cutoffs = append(cutoffs, before)
+			archived = append(archived, archive)
+			return 2, nil
+		},
+	}
+
+	newTestManager(config.PartitionConf{Retention: 12}, mock).RunOnce(context.Background())
+
+	assert.Equal(t, []time.Time{time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, cutoffs)
+	assert.Equal(t, []bool{false}, archived)
+}
+
+func TestRunOnce_KeepForever(t *testing.T) {
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error { return nil },
+	}
+
+	// ListFunc is unset: listing would panic if retention were enforced.
+	newTestManager(config.PartitionConf{Premake: 1, Retention: 0}, mock).RunOnce(context.Background())
+}
+
+func TestRunOnce_CreateErrorStillExpires(t *testing.T) {
+	var dropped []string
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error {
+			return errors.New("default partition contains matching rows")
+		},
+		ListFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"clicks_2023_01"}, nil
+		},
+		ExpireDefaultFunc: func(ctx context.Context, before time.Time, archive bool) (int64, error) {
+			return 0, nil
+		},
+		DropFunc: func(ctx context.Context, name string) error {
+			dropped = append(dropped, name)
+			return nil
+		},
+	}
+
+	newTestManager(config.PartitionConf{Retention: 12}, mock).RunOnce(context.Background())
+
+	assert.Equal(t, []string{"clicks_2023_01"}, dropped)
+}
+
+func TestStop_EndsStart(t *testing.T) {
+	mock := &model.MockClickPartitionsModel{
+		CreateFunc: func(ctx context.Context, month time.Time) error { return nil },
+	}
+	manager := newTestManager(config.PartitionConf{CheckInterval: 3600}, mock)
+
+	done := make(chan struct{})
+	go func() {
+		manager.Start()
+		close(done)
+	}()
+	manager.Stop()
+	manager.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after Stop")
+	}
+}
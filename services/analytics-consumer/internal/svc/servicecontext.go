@@ -1,28 +1,64 @@
 package svc
 
 import (
+	"context"
+	"errors"
 	"net"
 	"time"
 
+	"go-shortener/common/eventbus"
+	"go-shortener/common/health"
+	"go-shortener/services/analytics-consumer/internal/batch"
 	"go-shortener/services/analytics-consumer/internal/config"
+	"go-shortener/services/analytics-consumer/internal/deadletter"
+	"go-shortener/services/analytics-consumer/internal/visitor"
 	"go-shortener/services/analytics-rpc/model"
 
 	_ "github.com/lib/pq"
 	"github.com/oschwald/geoip2-golang"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/proc"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
+var errGeoIPNotLoaded = errors.New("GeoIP database not loaded")
+
 // GeoIPReader abstracts country lookup for testability.
 // *geoip2.Reader naturally satisfies this interface.
 type GeoIPReader interface {
 	Country(ipAddress net.IP) (*geoip2.Country, error)
 }
 
+// ClickWriter stores enriched clicks, reporting whether each was newly
+// inserted. *batch.Writer naturally satisfies this interface.
+type ClickWriter interface {
+	Write(ctx context.Context, click *model.Clicks) (bool, error)
+}
+
+// DeadLetterPublisher publishes messages the consumer gives up on.
+// *deadletter.Publisher naturally satisfies this interface.
+type DeadLetterPublisher interface {
+	Publish(ctx context.Context, msg deadletter.Message) error
+}
+
+// VisitorHasher derives the daily visitor fingerprint stored with each click.
+// *visitor.Hasher naturally satisfies this interface.
+type VisitorHasher interface {
+	Hash(ctx context.Context, t time.Time, ip, userAgent string) (string, error)
+}
+
 type ServiceContext struct {
-	Config     config.Config
-	ClickModel model.ClicksModel
-	GeoDB      GeoIPReader
+	Config      config.Config
+	EventBus    eventbus.EventBus
+	ClickModel  model.ClicksModel
+	ClickWriter ClickWriter
+	Partitions  model.ClickPartitionsModel
+	GeoDB       GeoIPReader
+	// Visitors is nil when visitor fingerprinting is disabled.
+	Visitors VisitorHasher
+	// DeadLetter is nil when the dead-letter topic is disabled.
+	DeadLetter DeadLetterPublisher
+	Health     *health.Health
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
@@ -47,9 +83,56 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		}
 	}
 
+	busConf := c.EventBus
+	if busConf.DataSource == "" {
+		busConf.DataSource = c.DataSource
+	}
+	bus := eventbus.MustNew(busConf, c.KqConsumerConf)
+
+	var deadLetter DeadLetterPublisher
+	switch {
+	case c.DeadLetter.Enabled && busConf.Driver != eventbus.DriverKafka:
+		logx.Infof("Dead-letter topic needs the kafka event bus, not %s; poison messages are dropped", busConf.Driver)
+	case c.DeadLetter.Enabled:
+		brokers := c.DeadLetter.Brokers
+		if len(brokers) == 0 {
+			brokers = c.KqConsumerConf.Brokers
+		}
+		publisher := deadletter.NewPublisher(brokers, c.DeadLetter.Topic, c.KqConsumerConf.Topic)
+		proc.AddShutdownListener(func() {
+			if closeErr := publisher.Close(); closeErr != nil {
+				logx.Errorf("failed to close dead-letter publisher: %v", closeErr)
+			}
+		})
+		deadLetter = publisher
+	}
+
+	checks := health.New(c.Readiness)
+	checks.Register("postgres", health.Ping(conn))
+	if busConf.Driver == eventbus.DriverKafka {
+		checks.Register("kafka", health.KafkaBrokers(c.KqConsumerConf.Brokers))
+	}
+	if c.GeoIPPath != "" {
+		// Clicks are stored with an unknown country without GeoIP
+		checks.RegisterOptional("geoip", health.CheckerFunc(func(ctx context.Context) error {
+			if geoDB == nil {
+				return errGeoIPNotLoaded
+			}
+			return nil
+		}))
+	}
+
+	clickModel := model.NewClicksModel(conn)
+
 	return &ServiceContext{
-		Config:     c,
-		ClickModel: model.NewClicksModel(conn),
-		GeoDB:      geoDB,
+		Config:      c,
+		EventBus:    bus,
+		ClickModel:  clickModel,
+		ClickWriter: batch.NewWriter(c.Batch, clickModel),
+		Partitions:  model.NewClickPartitionsModel(conn),
+		GeoDB:       geoDB,
+		Visitors:    visitor.NewHasher(model.NewVisitorSaltsModel(conn)),
+		DeadLetter:  deadLetter,
+		Health:      checks,
 	}
 }
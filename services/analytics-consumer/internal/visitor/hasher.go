@@ -0,0 +1,102 @@
+// Package visitor derives privacy-preserving visitor fingerprints for clicks.
+//
+// A fingerprint is an HMAC of the client IP and User-Agent keyed with a random
+// salt that changes every UTC day. Salts are shared by all consumers through
+// the visitor_salts table and deleted once the following day has started, so
+// stored fingerprints can neither be reversed by enumerating IP addresses nor
+// joined across days.
+package visitor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// hashLen is the number of hex characters kept from the HMAC (128 bits).
+const hashLen = 32
+
+// Hasher computes visitor fingerprints, caching the salt of recent days.
+type Hasher struct {
+	model model.VisitorSaltsModel
+
+	mu    sync.Mutex
+	salts map[time.Time][]byte
+}
+
+// NewHasher returns a Hasher backed by the visitor_salts table.
+func NewHasher(m model.VisitorSaltsModel) *Hasher {
+	return &Hasher{
+		model: m,
+		salts: make(map[time.Time][]byte),
+	}
+}
+
+// Hash returns the fingerprint of a visitor seen at t. Visitors without an IP
+// get an empty fingerprint and are left out of unique counts.
+func (h *Hasher) Hash(ctx context.Context, t time.Time, ip, userAgent string) (string, error) {
+	if ip == "" {
+		return "", nil
+	}
+
+	salt, err := h.salt(ctx, day(t))
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return hex.EncodeToString(mac.Sum(nil))[:hashLen], nil
+}
+
+func (h *Hasher) salt(ctx context.Context, d time.Time) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if salt, ok := h.salts[d]; ok {
+		return salt, nil
+	}
+
+	candidate := make([]byte, sha256.Size)
+	if _, err := rand.Read(candidate); err != nil {
+		return nil, err
+	}
+	salt, err := h.model.FindOrCreate(ctx, d, candidate)
+	if err != nil {
+		return nil, err
+	}
+	h.salts[d] = salt
+
+	// A new day started: forget salts older than yesterday, which still
+	// serves late events from just before midnight.
+	cutoff := d.AddDate(0, 0, -1)
+	for cached := range h.salts {
+		if cached.Before(cutoff) {
+			delete(h.salts, cached)
+		}
+	}
+	if err := h.model.DeleteBefore(ctx, cutoff); err != nil {
+		logx.WithContext(ctx).Errorw("failed to delete expired visitor salts",
+			logx.Field("before", cutoff.Format(time.DateOnly)),
+			logx.Field("error", err.Error()),
+		)
+	}
+
+	return salt, nil
+}
+
+// day returns the UTC calendar day containing t.
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,97 @@
+package visitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saltStore is an in-memory stand-in for the visitor_salts table.
+func saltStore(deletedBefore *[]time.Time) *model.MockVisitorSaltsModel {
+	stored := map[time.Time][]byte{}
+	return &model.MockVisitorSaltsModel{
+		FindOrCreateFunc: func(ctx context.Context, day time.Time, salt []byte) ([]byte, error) {
+			if s, ok := stored[day]; ok {
+				return s, nil
+			}
+			stored[day] = salt
+			return salt, nil
+		},
+		DeleteBeforeFunc: func(ctx context.Context, day time.Time) error {
+			*deletedBefore = append(*deletedBefore, day)
+			return nil
+		},
+	}
+}
+
+func TestHasher_SameVisitorSameDay(t *testing.T) {
+	var deleted []time.Time
+	h := NewHasher(saltStore(&deleted))
+	morning := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	a, err := h.Hash(context.Background(), morning, "1.2.3.4", "Mozilla/5.0")
+	require.NoError(t, err)
+	b, err := h.Hash(context.Background(), morning.Add(10*time.Hour), "1.2.3.4", "Mozilla/5.0")
+	require.NoError(t, err)
+
+	assert.Len(t, a, hashLen)
+	assert.Equal(t, a, b)
+	assert.Equal(t, []time.Time{time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)}, deleted, "salt is looked up once per day")
+}
+
+func TestHasher_DistinguishesVisitors(t *testing.T) {
+	var deleted []time.Time
+	h := NewHasher(saltStore(&deleted))
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	a, err := h.Hash(context.Background(), now, "1.2.3.4", "Mozilla/5.0")
+	require.NoError(t, err)
+	b, err := h.Hash(context.Background(), now, "1.2.3.4", "curl/8.0")
+	require.NoError(t, err)
+	c, err := h.Hash(context.Background(), now, "5.6.7.8", "Mozilla/5.0")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestHasher_RotatesDaily(t *testing.T) {
+	var deleted []time.Time
+	h := NewHasher(saltStore(&deleted))
+	day1 := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+
+	a, err := h.Hash(context.Background(), day1, "1.2.3.4", "Mozilla/5.0")
+	require.NoError(t, err)
+	b, err := h.Hash(context.Background(), day1.Add(2*time.Minute), "1.2.3.4", "Mozilla/5.0")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), deleted[1], "yesterday's salt is kept for late events")
+}
+
+func TestHasher_NoIP(t *testing.T) {
+	h := NewHasher(&model.MockVisitorSaltsModel{})
+
+	hash, err := h.Hash(context.Background(), time.Now(), "", "Mozilla/5.0")
+
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestHasher_StoreError(t *testing.T) {
+	h := NewHasher(&model.MockVisitorSaltsModel{
+		FindOrCreateFunc: func(ctx context.Context, day time.Time, salt []byte) ([]byte, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	})
+
+	_, err := h.Hash(context.Background(), time.Now(), "1.2.3.4", "Mozilla/5.0")
+
+	require.Error(t, err)
+}
@@ -3,17 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	_ "time/tzdata" // embed the IANA database for time series timezones
 
-	"go-shortener/services/analytics-rpc/analytics"
-	"go-shortener/services/analytics-rpc/internal/config"
-	"go-shortener/services/analytics-rpc/internal/server"
-	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/analyticsrpc"
 
 	"github.com/zeromicro/go-zero/core/conf"
-	"github.com/zeromicro/go-zero/core/service"
-	"github.com/zeromicro/go-zero/zrpc"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 )
 
 var configFile = flag.String("f", "etc/analytics.yaml", "the config file")
@@ -21,17 +15,10 @@ var configFile = flag.String("f", "etc/analytics.yaml", "the config file")
 func main() {
 	flag.Parse()
 
-	var c config.Config
+	var c analyticsrpc.Config
 	conf.MustLoad(*configFile, &c)
-	ctx := svc.NewServiceContext(c)
 
-	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
-		analytics.RegisterAnalyticsServer(grpcServer, server.NewAnalyticsServer(ctx))
-
-		if c.Mode == service.DevMode || c.Mode == service.TestMode {
-			reflection.Register(grpcServer)
-		}
-	})
+	s := analyticsrpc.MustNewServer(c)
 	defer s.Stop()
 
 	fmt.Printf("Starting rpc server at %s...\n", c.ListenOn)
@@ -117,6 +117,1065 @@ func (x *GetClickCountResponse) GetTotalClicks() int64 {
 	return 0
 }
 
+// GetClickCountsRequest asks for the total clicks of up to 1000 short codes.
+type GetClickCountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCodes    []string               `protobuf:"bytes,1,rep,name=short_codes,json=shortCodes,proto3" json:"short_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClickCountsRequest) Reset() {
+	*x = GetClickCountsRequest{}
+	mi := &file_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickCountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickCountsRequest) ProtoMessage() {}
+
+func (x *GetClickCountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickCountsRequest.ProtoReflect.Descriptor instead.
+func (*GetClickCountsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetClickCountsRequest) GetShortCodes() []string {
+	if x != nil {
+		return x.ShortCodes
+	}
+	return nil
+}
+
+// GetClickCountsResponse has one entry per distinct requested short code, in
+// request order, including codes without clicks.
+type GetClickCountsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*LinkClicks          `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClickCountsResponse) Reset() {
+	*x = GetClickCountsResponse{}
+	mi := &file_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickCountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickCountsResponse) ProtoMessage() {}
+
+func (x *GetClickCountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickCountsResponse.ProtoReflect.Descriptor instead.
+func (*GetClickCountsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetClickCountsResponse) GetLinks() []*LinkClicks {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type GetVariantClicksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVariantClicksRequest) Reset() {
+	*x = GetVariantClicksRequest{}
+	mi := &file_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVariantClicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVariantClicksRequest) ProtoMessage() {}
+
+func (x *GetVariantClicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVariantClicksRequest.ProtoReflect.Descriptor instead.
+func (*GetVariantClicksRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVariantClicksRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+type VariantClicks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variant       string                 `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariantClicks) Reset() {
+	*x = VariantClicks{}
+	mi := &file_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantClicks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantClicks) ProtoMessage() {}
+
+func (x *VariantClicks) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantClicks.ProtoReflect.Descriptor instead.
+func (*VariantClicks) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VariantClicks) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *VariantClicks) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type GetVariantClicksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Variants      []*VariantClicks       `protobuf:"bytes,2,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVariantClicksResponse) Reset() {
+	*x = GetVariantClicksResponse{}
+	mi := &file_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVariantClicksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVariantClicksResponse) ProtoMessage() {}
+
+func (x *GetVariantClicksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVariantClicksResponse.ProtoReflect.Descriptor instead.
+func (*GetVariantClicksResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetVariantClicksResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetVariantClicksResponse) GetVariants() []*VariantClicks {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// GetAnalyticsSummaryRequest selects the clicks of a short code within an
+// optional [from, to) range of unix seconds. Zero means unbounded. Unique
+// visitors are estimated with HyperLogLog when approximate is set or the link
+// has too many clicks to count them exactly. Unique visitors are counted from
+// raw clicks, which expire after the retention period while click counts are
+// kept in rollups; unique_visitors_partial reports that the range holds
+// clicks whose visitors are no longer known.
+type GetAnalyticsSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	Approximate   bool                   `protobuf:"varint,4,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAnalyticsSummaryRequest) Reset() {
+	*x = GetAnalyticsSummaryRequest{}
+	mi := &file_analytics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAnalyticsSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnalyticsSummaryRequest) ProtoMessage() {}
+
+func (x *GetAnalyticsSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnalyticsSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetAnalyticsSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAnalyticsSummaryRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetAnalyticsSummaryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetAnalyticsSummaryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetAnalyticsSummaryRequest) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+type DimensionCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DimensionCount) Reset() {
+	*x = DimensionCount{}
+	mi := &file_analytics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DimensionCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DimensionCount) ProtoMessage() {}
+
+func (x *DimensionCount) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DimensionCount.ProtoReflect.Descriptor instead.
+func (*DimensionCount) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DimensionCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *DimensionCount) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type GetAnalyticsSummaryResponse struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode                 string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	TotalClicks               int64                  `protobuf:"varint,2,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	Countries                 []*DimensionCount      `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+	Devices                   []*DimensionCount      `protobuf:"bytes,4,rep,name=devices,proto3" json:"devices,omitempty"`
+	TrafficSources            []*DimensionCount      `protobuf:"bytes,5,rep,name=traffic_sources,json=trafficSources,proto3" json:"traffic_sources,omitempty"`
+	UniqueVisitors            int64                  `protobuf:"varint,6,opt,name=unique_visitors,json=uniqueVisitors,proto3" json:"unique_visitors,omitempty"`
+	UniqueVisitorsApproximate bool                   `protobuf:"varint,7,opt,name=unique_visitors_approximate,json=uniqueVisitorsApproximate,proto3" json:"unique_visitors_approximate,omitempty"`
+	UniqueVisitorsPartial     bool                   `protobuf:"varint,8,opt,name=unique_visitors_partial,json=uniqueVisitorsPartial,proto3" json:"unique_visitors_partial,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *GetAnalyticsSummaryResponse) Reset() {
+	*x = GetAnalyticsSummaryResponse{}
+	mi := &file_analytics_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAnalyticsSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnalyticsSummaryResponse) ProtoMessage() {}
+
+func (x *GetAnalyticsSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnalyticsSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetAnalyticsSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAnalyticsSummaryResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetAnalyticsSummaryResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetAnalyticsSummaryResponse) GetCountries() []*DimensionCount {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *GetAnalyticsSummaryResponse) GetDevices() []*DimensionCount {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *GetAnalyticsSummaryResponse) GetTrafficSources() []*DimensionCount {
+	if x != nil {
+		return x.TrafficSources
+	}
+	return nil
+}
+
+func (x *GetAnalyticsSummaryResponse) GetUniqueVisitors() int64 {
+	if x != nil {
+		return x.UniqueVisitors
+	}
+	return 0
+}
+
+func (x *GetAnalyticsSummaryResponse) GetUniqueVisitorsApproximate() bool {
+	if x != nil {
+		return x.UniqueVisitorsApproximate
+	}
+	return false
+}
+
+func (x *GetAnalyticsSummaryResponse) GetUniqueVisitorsPartial() bool {
+	if x != nil {
+		return x.UniqueVisitorsPartial
+	}
+	return false
+}
+
+// GetClickTimeSeriesRequest buckets the clicks of a short code by interval
+// ("minute", "hour", "day" or "week") within [from, to) in unix seconds.
+// Buckets align to the IANA timezone (default UTC); a zero range defaults to a
+// window suited to the interval, ending now. approximate works as in
+// GetAnalyticsSummaryRequest.
+type GetClickTimeSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Timezone      string                 `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Approximate   bool                   `protobuf:"varint,6,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClickTimeSeriesRequest) Reset() {
+	*x = GetClickTimeSeriesRequest{}
+	mi := &file_analytics_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickTimeSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickTimeSeriesRequest) ProtoMessage() {}
+
+func (x *GetClickTimeSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickTimeSeriesRequest.ProtoReflect.Descriptor instead.
+func (*GetClickTimeSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetClickTimeSeriesRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetClickTimeSeriesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetClickTimeSeriesRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesRequest) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+type TimeBucket struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Start          int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	Clicks         int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	UniqueVisitors int64                  `protobuf:"varint,3,opt,name=unique_visitors,json=uniqueVisitors,proto3" json:"unique_visitors,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TimeBucket) Reset() {
+	*x = TimeBucket{}
+	mi := &file_analytics_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeBucket) ProtoMessage() {}
+
+func (x *TimeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeBucket.ProtoReflect.Descriptor instead.
+func (*TimeBucket) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TimeBucket) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TimeBucket) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *TimeBucket) GetUniqueVisitors() int64 {
+	if x != nil {
+		return x.UniqueVisitors
+	}
+	return 0
+}
+
+// GetClickTimeSeriesResponse holds one bucket per interval in the range,
+// including zero-click buckets, ordered by start. unique_visitors_partial works
+// as in GetAnalyticsSummaryRequest: buckets past retention report clicks but
+// no unique visitors.
+type GetClickTimeSeriesResponse struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode                 string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Interval                  string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timezone                  string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	From                      int64                  `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To                        int64                  `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
+	Buckets                   []*TimeBucket          `protobuf:"bytes,6,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	UniqueVisitorsApproximate bool                   `protobuf:"varint,7,opt,name=unique_visitors_approximate,json=uniqueVisitorsApproximate,proto3" json:"unique_visitors_approximate,omitempty"`
+	UniqueVisitorsPartial     bool                   `protobuf:"varint,8,opt,name=unique_visitors_partial,json=uniqueVisitorsPartial,proto3" json:"unique_visitors_partial,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *GetClickTimeSeriesResponse) Reset() {
+	*x = GetClickTimeSeriesResponse{}
+	mi := &file_analytics_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickTimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickTimeSeriesResponse) ProtoMessage() {}
+
+func (x *GetClickTimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickTimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*GetClickTimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetClickTimeSeriesResponse) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetClickTimeSeriesResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetClickTimeSeriesResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetClickTimeSeriesResponse) GetBuckets() []*TimeBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetClickTimeSeriesResponse) GetUniqueVisitorsApproximate() bool {
+	if x != nil {
+		return x.UniqueVisitorsApproximate
+	}
+	return false
+}
+
+func (x *GetClickTimeSeriesResponse) GetUniqueVisitorsPartial() bool {
+	if x != nil {
+		return x.UniqueVisitorsPartial
+	}
+	return false
+}
+
+// DimensionFilter restricts counted clicks to one value of a dimension
+// ("country_code", "device_type" or "traffic_source"), e.g. country_code = "DE".
+type DimensionFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dimension     string                 `protobuf:"bytes,1,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DimensionFilter) Reset() {
+	*x = DimensionFilter{}
+	mi := &file_analytics_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DimensionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DimensionFilter) ProtoMessage() {}
+
+func (x *DimensionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DimensionFilter.ProtoReflect.Descriptor instead.
+func (*DimensionFilter) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DimensionFilter) GetDimension() string {
+	if x != nil {
+		return x.Dimension
+	}
+	return ""
+}
+
+func (x *DimensionFilter) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// GetTopLinksRequest ranks short codes by clicks within [from, to) in unix
+// seconds, counting only clicks matching every filter. Zero from or to leaves
+// that side open; limit defaults to 10 and is capped at 100. Offset skips that
+// many of the ranked short codes, so callers can page past entries they drop.
+type GetTopLinksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Filters       []*DimensionFilter     `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty"`
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopLinksRequest) Reset() {
+	*x = GetTopLinksRequest{}
+	mi := &file_analytics_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopLinksRequest) ProtoMessage() {}
+
+func (x *GetTopLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopLinksRequest.ProtoReflect.Descriptor instead.
+func (*GetTopLinksRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTopLinksRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetTopLinksRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetTopLinksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTopLinksRequest) GetFilters() []*DimensionFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *GetTopLinksRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type LinkClicks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,json=shortCode,proto3" json:"short_code,omitempty"`
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkClicks) Reset() {
+	*x = LinkClicks{}
+	mi := &file_analytics_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkClicks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkClicks) ProtoMessage() {}
+
+func (x *LinkClicks) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkClicks.ProtoReflect.Descriptor instead.
+func (*LinkClicks) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LinkClicks) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *LinkClicks) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+// GetTopLinksResponse lists the most clicked short codes first.
+type GetTopLinksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*LinkClicks          `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopLinksResponse) Reset() {
+	*x = GetTopLinksResponse{}
+	mi := &file_analytics_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopLinksResponse) ProtoMessage() {}
+
+func (x *GetTopLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopLinksResponse.ProtoReflect.Descriptor instead.
+func (*GetTopLinksResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTopLinksResponse) GetLinks() []*LinkClicks {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+// GetClickRankingRequest pages through all short codes with clicks ranked by
+// their total clicks, most clicked first unless ascending, with ties ordered by
+// short code in the same direction. A page continues after the last entry of
+// the previous one, or starts from the top when after is unset. Limit
+// defaults to 100 and is capped at 1000.
+type GetClickRankingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Ascending     bool                   `protobuf:"varint,2,opt,name=ascending,proto3" json:"ascending,omitempty"`
+	After         *LinkClicks            `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClickRankingRequest) Reset() {
+	*x = GetClickRankingRequest{}
+	mi := &file_analytics_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickRankingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickRankingRequest) ProtoMessage() {}
+
+func (x *GetClickRankingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickRankingRequest.ProtoReflect.Descriptor instead.
+func (*GetClickRankingRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetClickRankingRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetClickRankingRequest) GetAscending() bool {
+	if x != nil {
+		return x.Ascending
+	}
+	return false
+}
+
+func (x *GetClickRankingRequest) GetAfter() *LinkClicks {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type GetClickRankingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*LinkClicks          `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClickRankingResponse) Reset() {
+	*x = GetClickRankingResponse{}
+	mi := &file_analytics_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClickRankingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClickRankingResponse) ProtoMessage() {}
+
+func (x *GetClickRankingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClickRankingResponse.ProtoReflect.Descriptor instead.
+func (*GetClickRankingResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetClickRankingResponse) GetLinks() []*LinkClicks {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
 var File_analytics_proto protoreflect.FileDescriptor
 
 const file_analytics_proto_rawDesc = "" +
@@ -128,9 +1187,94 @@ const file_analytics_proto_rawDesc = "" +
 	"\x15GetClickCountResponse\x12\x1d\n" +
 	"\n" +
 	"short_code\x18\x01 \x01(\tR\tshortCode\x12!\n" +
-	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks2_\n" +
+	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks\"8\n" +
+	"\x15GetClickCountsRequest\x12\x1f\n" +
+	"\vshort_codes\x18\x01 \x03(\tR\n" +
+	"shortCodes\"E\n" +
+	"\x16GetClickCountsResponse\x12+\n" +
+	"\x05links\x18\x01 \x03(\v2\x15.analytics.LinkClicksR\x05links\"8\n" +
+	"\x17GetVariantClicksRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\"A\n" +
+	"\rVariantClicks\x12\x18\n" +
+	"\avariant\x18\x01 \x01(\tR\avariant\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\"o\n" +
+	"\x18GetVariantClicksResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x124\n" +
+	"\bvariants\x18\x02 \x03(\v2\x18.analytics.VariantClicksR\bvariants\"\x81\x01\n" +
+	"\x1aGetAnalyticsSummaryRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12 \n" +
+	"\vapproximate\x18\x04 \x01(\bR\vapproximate\">\n" +
+	"\x0eDimensionCount\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\"\xb2\x03\n" +
+	"\x1bGetAnalyticsSummaryResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12!\n" +
+	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks\x127\n" +
+	"\tcountries\x18\x03 \x03(\v2\x19.analytics.DimensionCountR\tcountries\x123\n" +
+	"\adevices\x18\x04 \x03(\v2\x19.analytics.DimensionCountR\adevices\x12B\n" +
+	"\x0ftraffic_sources\x18\x05 \x03(\v2\x19.analytics.DimensionCountR\x0etrafficSources\x12'\n" +
+	"\x0funique_visitors\x18\x06 \x01(\x03R\x0euniqueVisitors\x12>\n" +
+	"\x1bunique_visitors_approximate\x18\a \x01(\bR\x19uniqueVisitorsApproximate\x126\n" +
+	"\x17unique_visitors_partial\x18\b \x01(\bR\x15uniqueVisitorsPartial\"\xb8\x01\n" +
+	"\x19GetClickTimeSeriesRequest\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x1a\n" +
+	"\btimezone\x18\x05 \x01(\tR\btimezone\x12 \n" +
+	"\vapproximate\x18\x06 \x01(\bR\vapproximate\"c\n" +
+	"\n" +
+	"TimeBucket\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\x12'\n" +
+	"\x0funique_visitors\x18\x03 \x01(\x03R\x0euniqueVisitors\"\xc0\x02\n" +
+	"\x1aGetClickTimeSeriesResponse\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x05 \x01(\x03R\x02to\x12/\n" +
+	"\abuckets\x18\x06 \x03(\v2\x15.analytics.TimeBucketR\abuckets\x12>\n" +
+	"\x1bunique_visitors_approximate\x18\a \x01(\bR\x19uniqueVisitorsApproximate\x126\n" +
+	"\x17unique_visitors_partial\x18\b \x01(\bR\x15uniqueVisitorsPartial\"E\n" +
+	"\x0fDimensionFilter\x12\x1c\n" +
+	"\tdimension\x18\x01 \x01(\tR\tdimension\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x9c\x01\n" +
+	"\x12GetTopLinksRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x124\n" +
+	"\afilters\x18\x04 \x03(\v2\x1a.analytics.DimensionFilterR\afilters\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"C\n" +
+	"\n" +
+	"LinkClicks\x12\x1d\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\tshortCode\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\"B\n" +
+	"\x13GetTopLinksResponse\x12+\n" +
+	"\x05links\x18\x01 \x03(\v2\x15.analytics.LinkClicksR\x05links\"y\n" +
+	"\x16GetClickRankingRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\tascending\x18\x02 \x01(\bR\tascending\x12+\n" +
+	"\x05after\x18\x03 \x01(\v2\x15.analytics.LinkClicksR\x05after\"F\n" +
+	"\x17GetClickRankingResponse\x12+\n" +
+	"\x05links\x18\x01 \x03(\v2\x15.analytics.LinkClicksR\x05links2\x84\x05\n" +
 	"\tAnalytics\x12R\n" +
-	"\rGetClickCount\x12\x1f.analytics.GetClickCountRequest\x1a .analytics.GetClickCountResponseB\rZ\v./analyticsb\x06proto3"
+	"\rGetClickCount\x12\x1f.analytics.GetClickCountRequest\x1a .analytics.GetClickCountResponse\x12U\n" +
+	"\x0eGetClickCounts\x12 .analytics.GetClickCountsRequest\x1a!.analytics.GetClickCountsResponse\x12[\n" +
+	"\x10GetVariantClicks\x12\".analytics.GetVariantClicksRequest\x1a#.analytics.GetVariantClicksResponse\x12d\n" +
+	"\x13GetAnalyticsSummary\x12%.analytics.GetAnalyticsSummaryRequest\x1a&.analytics.GetAnalyticsSummaryResponse\x12a\n" +
+	"\x12GetClickTimeSeries\x12$.analytics.GetClickTimeSeriesRequest\x1a%.analytics.GetClickTimeSeriesResponse\x12L\n" +
+	"\vGetTopLinks\x12\x1d.analytics.GetTopLinksRequest\x1a\x1e.analytics.GetTopLinksResponse\x12X\n" +
+	"\x0fGetClickRanking\x12!.analytics.GetClickRankingRequest\x1a\".analytics.GetClickRankingResponseB\rZ\v./analyticsb\x06proto3"
 
 var (
 	file_analytics_proto_rawDescOnce sync.Once
@@ -144,19 +1288,58 @@ func file_analytics_proto_rawDescGZIP() []byte {
 	return file_analytics_proto_rawDescData
 }
 
-var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_analytics_proto_goTypes = []any{
-	(*GetClickCountRequest)(nil),  // 0: analytics.GetClickCountRequest
-	(*GetClickCountResponse)(nil), // 1: analytics.GetClickCountResponse
+	(*GetClickCountRequest)(nil),        // 0: analytics.GetClickCountRequest
+	(*GetClickCountResponse)(nil),       // 1: analytics.GetClickCountResponse
+	(*GetClickCountsRequest)(nil),       // 2: analytics.GetClickCountsRequest
+	(*GetClickCountsResponse)(nil),      // 3: analytics.GetClickCountsResponse
+	(*GetVariantClicksRequest)(nil),     // 4: analytics.GetVariantClicksRequest
+	(*VariantClicks)(nil),               // 5: analytics.VariantClicks
+	(*GetVariantClicksResponse)(nil),    // 6: analytics.GetVariantClicksResponse
+	(*GetAnalyticsSummaryRequest)(nil),  // 7: analytics.GetAnalyticsSummaryRequest
+	(*DimensionCount)(nil),              // 8: analytics.DimensionCount
+	(*GetAnalyticsSummaryResponse)(nil), // 9: analytics.GetAnalyticsSummaryResponse
+	(*GetClickTimeSeriesRequest)(nil),   // 10: analytics.GetClickTimeSeriesRequest
+	(*TimeBucket)(nil),                  // 11: analytics.TimeBucket
+	(*GetClickTimeSeriesResponse)(nil),  // 12: analytics.GetClickTimeSeriesResponse
+	(*DimensionFilter)(nil),             // 13: analytics.DimensionFilter
+	(*GetTopLinksRequest)(nil),          // 14: analytics.GetTopLinksRequest
+	(*LinkClicks)(nil),                  // 15: analytics.LinkClicks
+	(*GetTopLinksResponse)(nil),         // 16: analytics.GetTopLinksResponse
+	(*GetClickRankingRequest)(nil),      // 17: analytics.GetClickRankingRequest
+	(*GetClickRankingResponse)(nil),     // 18: analytics.GetClickRankingResponse
 }
 var file_analytics_proto_depIdxs = []int32{
-	0, // 0: analytics.Analytics.GetClickCount:input_type -> analytics.GetClickCountRequest
-	1, // 1: analytics.Analytics.GetClickCount:output_type -> analytics.GetClickCountResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	15, // 0: analytics.GetClickCountsResponse.links:type_name -> analytics.LinkClicks
+	5,  // 1: analytics.GetVariantClicksResponse.variants:type_name -> analytics.VariantClicks
+	8,  // 2: analytics.GetAnalyticsSummaryResponse.countries:type_name -> analytics.DimensionCount
+	8,  // 3: analytics.GetAnalyticsSummaryResponse.devices:type_name -> analytics.DimensionCount
+	8,  // 4: analytics.GetAnalyticsSummaryResponse.traffic_sources:type_name -> analytics.DimensionCount
+	11, // 5: analytics.GetClickTimeSeriesResponse.buckets:type_name -> analytics.TimeBucket
+	13, // 6: analytics.GetTopLinksRequest.filters:type_name -> analytics.DimensionFilter
+	15, // 7: analytics.GetTopLinksResponse.links:type_name -> analytics.LinkClicks
+	15, // 8: analytics.GetClickRankingRequest.after:type_name -> analytics.LinkClicks
+	15, // 9: analytics.GetClickRankingResponse.links:type_name -> analytics.LinkClicks
+	0,  // 10: analytics.Analytics.GetClickCount:input_type -> analytics.GetClickCountRequest
+	2,  // 11: analytics.Analytics.GetClickCounts:input_type -> analytics.GetClickCountsRequest
+	4,  // 12: analytics.Analytics.GetVariantClicks:input_type -> analytics.GetVariantClicksRequest
+	7,  // 13: analytics.Analytics.GetAnalyticsSummary:input_type -> analytics.GetAnalyticsSummaryRequest
+	10, // 14: analytics.Analytics.GetClickTimeSeries:input_type -> analytics.GetClickTimeSeriesRequest
+	14, // 15: analytics.Analytics.GetTopLinks:input_type -> analytics.GetTopLinksRequest
+	17, // 16: analytics.Analytics.GetClickRanking:input_type -> analytics.GetClickRankingRequest
+	1,  // 17: analytics.Analytics.GetClickCount:output_type -> analytics.GetClickCountResponse
+	3,  // 18: analytics.Analytics.GetClickCounts:output_type -> analytics.GetClickCountsResponse
+	6,  // 19: analytics.Analytics.GetVariantClicks:output_type -> analytics.GetVariantClicksResponse
+	9,  // 20: analytics.Analytics.GetAnalyticsSummary:output_type -> analytics.GetAnalyticsSummaryResponse
+	12, // 21: analytics.Analytics.GetClickTimeSeries:output_type -> analytics.GetClickTimeSeriesResponse
+	16, // 22: analytics.Analytics.GetTopLinks:output_type -> analytics.GetTopLinksResponse
+	18, // 23: analytics.Analytics.GetClickRanking:output_type -> analytics.GetClickRankingResponse
+	17, // [17:24] is the sub-list for method output_type
+	10, // [10:17] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_analytics_proto_init() }
@@ -170,7 +1353,7 @@ func file_analytics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
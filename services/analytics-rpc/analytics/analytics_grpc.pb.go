@@ -19,7 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Analytics_GetClickCount_FullMethodName = "/analytics.Analytics/GetClickCount"
+	Analytics_GetClickCount_FullMethodName       = "/analytics.Analytics/GetClickCount"
+	Analytics_GetClickCounts_FullMethodName      = "/analytics.Analytics/GetClickCounts"
+	Analytics_GetVariantClicks_FullMethodName    = "/analytics.Analytics/GetVariantClicks"
+	Analytics_GetAnalyticsSummary_FullMethodName = "/analytics.Analytics/GetAnalyticsSummary"
+	Analytics_GetClickTimeSeries_FullMethodName  = "/analytics.Analytics/GetClickTimeSeries"
+	Analytics_GetTopLinks_FullMethodName         = "/analytics.Analytics/GetTopLinks"
+	Analytics_GetClickRanking_FullMethodName     = "/analytics.Analytics/GetClickRanking"
 )
 
 // AnalyticsClient is the client API for Analytics service.
@@ -27,10 +33,14 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
 // Analytics provides click analytics for shortened URLs.
-// Phase 7: GetClickCount only (minimal scope).
-// Phase 9: Add GetAnalyticsSummary when Kafka consumer is wired.
 type AnalyticsClient interface {
 	GetClickCount(ctx context.Context, in *GetClickCountRequest, opts ...grpc.CallOption) (*GetClickCountResponse, error)
+	GetClickCounts(ctx context.Context, in *GetClickCountsRequest, opts ...grpc.CallOption) (*GetClickCountsResponse, error)
+	GetVariantClicks(ctx context.Context, in *GetVariantClicksRequest, opts ...grpc.CallOption) (*GetVariantClicksResponse, error)
+	GetAnalyticsSummary(ctx context.Context, in *GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*GetAnalyticsSummaryResponse, error)
+	GetClickTimeSeries(ctx context.Context, in *GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*GetClickTimeSeriesResponse, error)
+	GetTopLinks(ctx context.Context, in *GetTopLinksRequest, opts ...grpc.CallOption) (*GetTopLinksResponse, error)
+	GetClickRanking(ctx context.Context, in *GetClickRankingRequest, opts ...grpc.CallOption) (*GetClickRankingResponse, error)
 }
 
 type analyticsClient struct {
@@ -51,15 +61,79 @@ func (c *analyticsClient) GetClickCount(ctx context.Context, in *GetClickCountRe
 	return out, nil
 }
 
+func (c *analyticsClient) GetClickCounts(ctx context.Context, in *GetClickCountsRequest, opts ...grpc.CallOption) (*GetClickCountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClickCountsResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetClickCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsClient) GetVariantClicks(ctx context.Context, in *GetVariantClicksRequest, opts ...grpc.CallOption) (*GetVariantClicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVariantClicksResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetVariantClicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsClient) GetAnalyticsSummary(ctx context.Context, in *GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*GetAnalyticsSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAnalyticsSummaryResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetAnalyticsSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsClient) GetClickTimeSeries(ctx context.Context, in *GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*GetClickTimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClickTimeSeriesResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetClickTimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsClient) GetTopLinks(ctx context.Context, in *GetTopLinksRequest, opts ...grpc.CallOption) (*GetTopLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopLinksResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetTopLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsClient) GetClickRanking(ctx context.Context, in *GetClickRankingRequest, opts ...grpc.CallOption) (*GetClickRankingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClickRankingResponse)
+	err := c.cc.Invoke(ctx, Analytics_GetClickRanking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AnalyticsServer is the server API for Analytics service.
 // All implementations must embed UnimplementedAnalyticsServer
 // for forward compatibility.
 //
 // Analytics provides click analytics for shortened URLs.
-// Phase 7: GetClickCount only (minimal scope).
-// Phase 9: Add GetAnalyticsSummary when Kafka consumer is wired.
 type AnalyticsServer interface {
 	GetClickCount(context.Context, *GetClickCountRequest) (*GetClickCountResponse, error)
+	GetClickCounts(context.Context, *GetClickCountsRequest) (*GetClickCountsResponse, error)
+	GetVariantClicks(context.Context, *GetVariantClicksRequest) (*GetVariantClicksResponse, error)
+	GetAnalyticsSummary(context.Context, *GetAnalyticsSummaryRequest) (*GetAnalyticsSummaryResponse, error)
+	GetClickTimeSeries(context.Context, *GetClickTimeSeriesRequest) (*GetClickTimeSeriesResponse, error)
+	GetTopLinks(context.Context, *GetTopLinksRequest) (*GetTopLinksResponse, error)
+	GetClickRanking(context.Context, *GetClickRankingRequest) (*GetClickRankingResponse, error)
 	mustEmbedUnimplementedAnalyticsServer()
 }
 
@@ -73,6 +147,24 @@ type UnimplementedAnalyticsServer struct{}
 func (UnimplementedAnalyticsServer) GetClickCount(context.Context, *GetClickCountRequest) (*GetClickCountResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetClickCount not implemented")
 }
+func (UnimplementedAnalyticsServer) GetClickCounts(context.Context, *GetClickCountsRequest) (*GetClickCountsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClickCounts not implemented")
+}
+func (UnimplementedAnalyticsServer) GetVariantClicks(context.Context, *GetVariantClicksRequest) (*GetVariantClicksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetVariantClicks not implemented")
+}
+func (UnimplementedAnalyticsServer) GetAnalyticsSummary(context.Context, *GetAnalyticsSummaryRequest) (*GetAnalyticsSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAnalyticsSummary not implemented")
+}
+func (UnimplementedAnalyticsServer) GetClickTimeSeries(context.Context, *GetClickTimeSeriesRequest) (*GetClickTimeSeriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClickTimeSeries not implemented")
+}
+func (UnimplementedAnalyticsServer) GetTopLinks(context.Context, *GetTopLinksRequest) (*GetTopLinksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTopLinks not implemented")
+}
+func (UnimplementedAnalyticsServer) GetClickRanking(context.Context, *GetClickRankingRequest) (*GetClickRankingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClickRanking not implemented")
+}
 func (UnimplementedAnalyticsServer) mustEmbedUnimplementedAnalyticsServer() {}
 func (UnimplementedAnalyticsServer) testEmbeddedByValue()                   {}
 
@@ -112,6 +204,114 @@ func _Analytics_GetClickCount_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Analytics_GetClickCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClickCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetClickCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetClickCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetClickCounts(ctx, req.(*GetClickCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analytics_GetVariantClicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVariantClicksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetVariantClicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetVariantClicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetVariantClicks(ctx, req.(*GetVariantClicksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analytics_GetAnalyticsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAnalyticsSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetAnalyticsSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetAnalyticsSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetAnalyticsSummary(ctx, req.(*GetAnalyticsSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analytics_GetClickTimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClickTimeSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetClickTimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetClickTimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetClickTimeSeries(ctx, req.(*GetClickTimeSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analytics_GetTopLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetTopLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetTopLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetTopLinks(ctx, req.(*GetTopLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Analytics_GetClickRanking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClickRankingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServer).GetClickRanking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Analytics_GetClickRanking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServer).GetClickRanking(ctx, req.(*GetClickRankingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Analytics_ServiceDesc is the grpc.ServiceDesc for Analytics service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -123,6 +323,30 @@ var Analytics_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetClickCount",
 			Handler:    _Analytics_GetClickCount_Handler,
 		},
+		{
+			MethodName: "GetClickCounts",
+			Handler:    _Analytics_GetClickCounts_Handler,
+		},
+		{
+			MethodName: "GetVariantClicks",
+			Handler:    _Analytics_GetVariantClicks_Handler,
+		},
+		{
+			MethodName: "GetAnalyticsSummary",
+			Handler:    _Analytics_GetAnalyticsSummary_Handler,
+		},
+		{
+			MethodName: "GetClickTimeSeries",
+			Handler:    _Analytics_GetClickTimeSeries_Handler,
+		},
+		{
+			MethodName: "GetTopLinks",
+			Handler:    _Analytics_GetTopLinks_Handler,
+		},
+		{
+			MethodName: "GetClickRanking",
+			Handler:    _Analytics_GetClickRanking_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "analytics.proto",
@@ -14,11 +14,34 @@ import (
 )
 
 type (
-	GetClickCountRequest  = analytics.GetClickCountRequest
-	GetClickCountResponse = analytics.GetClickCountResponse
+	DimensionCount              = analytics.DimensionCount
+	DimensionFilter             = analytics.DimensionFilter
+	GetAnalyticsSummaryRequest  = analytics.GetAnalyticsSummaryRequest
+	GetAnalyticsSummaryResponse = analytics.GetAnalyticsSummaryResponse
+	GetClickCountRequest        = analytics.GetClickCountRequest
+	GetClickCountResponse       = analytics.GetClickCountResponse
+	GetClickCountsRequest       = analytics.GetClickCountsRequest
+	GetClickCountsResponse      = analytics.GetClickCountsResponse
+	GetClickRankingRequest      = analytics.GetClickRankingRequest
+	GetClickRankingResponse     = analytics.GetClickRankingResponse
+	GetClickTimeSeriesRequest   = analytics.GetClickTimeSeriesRequest
+	GetClickTimeSeriesResponse  = analytics.GetClickTimeSeriesResponse
+	GetTopLinksRequest          = analytics.GetTopLinksRequest
+	GetTopLinksResponse         = analytics.GetTopLinksResponse
+	GetVariantClicksRequest     = analytics.GetVariantClicksRequest
+	GetVariantClicksResponse    = analytics.GetVariantClicksResponse
+	LinkClicks                  = analytics.LinkClicks
+	TimeBucket                  = analytics.TimeBucket
+	VariantClicks               = analytics.VariantClicks
 
 	Analytics interface {
 		GetClickCount(ctx context.Context, in *GetClickCountRequest, opts ...grpc.CallOption) (*GetClickCountResponse, error)
+		GetVariantClicks(ctx context.Context, in *GetVariantClicksRequest, opts ...grpc.CallOption) (*GetVariantClicksResponse, error)
+		GetAnalyticsSummary(ctx context.Context, in *GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*GetAnalyticsSummaryResponse, error)
+		GetClickTimeSeries(ctx context.Context, in *GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*GetClickTimeSeriesResponse, error)
+		GetTopLinks(ctx context.Context, in *GetTopLinksRequest, opts ...grpc.CallOption) (*GetTopLinksResponse, error)
+		GetClickCounts(ctx context.Context, in *GetClickCountsRequest, opts ...grpc.CallOption) (*GetClickCountsResponse, error)
+		GetClickRanking(ctx context.Context, in *GetClickRankingRequest, opts ...grpc.CallOption) (*GetClickRankingResponse, error)
 	}
 
 	defaultAnalytics struct {
@@ -36,3 +59,33 @@ func (m *defaultAnalytics) GetClickCount(ctx context.Context, in *GetClickCountR
 	client := analytics.NewAnalyticsClient(m.cli.Conn())
 	return client.GetClickCount(ctx, in, opts...)
 }
+
+func (m *defaultAnalytics) GetVariantClicks(ctx context.Context, in *GetVariantClicksRequest, opts ...grpc.CallOption) (*GetVariantClicksResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetVariantClicks(ctx, in, opts...)
+}
+
+func (m *defaultAnalytics) GetAnalyticsSummary(ctx context.Context, in *GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*GetAnalyticsSummaryResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetAnalyticsSummary(ctx, in, opts...)
+}
+
+func (m *defaultAnalytics) GetClickTimeSeries(ctx context.Context, in *GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*GetClickTimeSeriesResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetClickTimeSeries(ctx, in, opts...)
+}
+
+func (m *defaultAnalytics) GetTopLinks(ctx context.Context, in *GetTopLinksRequest, opts ...grpc.CallOption) (*GetTopLinksResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetTopLinks(ctx, in, opts...)
+}
+
+func (m *defaultAnalytics) GetClickCounts(ctx context.Context, in *GetClickCountsRequest, opts ...grpc.CallOption) (*GetClickCountsResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetClickCounts(ctx, in, opts...)
+}
+
+func (m *defaultAnalytics) GetClickRanking(ctx context.Context, in *GetClickRankingRequest, opts ...grpc.CallOption) (*GetClickRankingResponse, error) {
+	client := analytics.NewAnalyticsClient(m.cli.Conn())
+	return client.GetClickRanking(ctx, in, opts...)
+}
@@ -0,0 +1,67 @@
+// Package analyticsrpc wires analytics-rpc so it runs on its own or inside the
+// allinone binary.
+package analyticsrpc
+
+import (
+	"go-shortener/common/health"
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/server"
+	"go-shortener/services/analytics-rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/core/threading"
+	"github.com/zeromicro/go-zero/zrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Config is the analytics-rpc configuration.
+type Config = config.Config
+
+// Server is the analytics gRPC server together with its health check server.
+// Client calls its handlers in process.
+type Server struct {
+	*zrpc.RpcServer
+	health    *health.Server
+	analytics *server.AnalyticsServer
+}
+
+// MustNewServer returns the analytics gRPC server, exiting on error.
+func MustNewServer(c Config) *Server {
+	svcCtx := svc.NewServiceContext(c)
+	analyticsServer := server.NewAnalyticsServer(svcCtx)
+
+	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
+		analytics.RegisterAnalyticsServer(grpcServer, analyticsServer)
+
+		if c.Mode == service.DevMode || c.Mode == service.TestMode {
+			reflection.Register(grpcServer)
+		}
+	})
+
+	return &Server{
+		RpcServer: s,
+		health:    health.NewServer(c.HealthCheckPort, svcCtx.Health),
+		analytics: analyticsServer,
+	}
+}
+
+// Start serves health checks in the background and gRPC until stopped.
+func (s *Server) Start() {
+	threading.GoSafe(s.health.Start)
+	s.RpcServer.Start()
+}
+
+// Stop stops the health check and the gRPC servers.
+func (s *Server) Stop() {
+	s.health.Stop()
+	s.RpcServer.Stop()
+}
+
+// Client returns an analytics client that calls the server's handlers
+// directly, skipping gRPC and its interceptors.
+func (s *Server) Client() analyticsclient.Analytics {
+	return localClient{server: s.analytics}
+}
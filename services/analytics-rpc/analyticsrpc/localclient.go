@@ -0,0 +1,46 @@
+package analyticsrpc
+
+import (
+	"context"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+
+	"google.golang.org/grpc"
+)
+
+var _ analyticsclient.Analytics = localClient{}
+
+// localClient implements analyticsclient.Analytics on top of the server
+// handlers. Call options only apply to gRPC and are ignored.
+type localClient struct {
+	server analytics.AnalyticsServer
+}
+
+func (c localClient) GetClickCount(ctx context.Context, in *analyticsclient.GetClickCountRequest, _ ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+	return c.server.GetClickCount(ctx, in)
+}
+
+func (c localClient) GetVariantClicks(ctx context.Context, in *analyticsclient.GetVariantClicksRequest, _ ...grpc.CallOption) (*analyticsclient.GetVariantClicksResponse, error) {
+	return c.server.GetVariantClicks(ctx, in)
+}
+
+func (c localClient) GetAnalyticsSummary(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, _ ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error) {
+	return c.server.GetAnalyticsSummary(ctx, in)
+}
+
+func (c localClient) GetClickTimeSeries(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, _ ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+	return c.server.GetClickTimeSeries(ctx, in)
+}
+
+func (c localClient) GetTopLinks(ctx context.Context, in *analyticsclient.GetTopLinksRequest, _ ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+	return c.server.GetTopLinks(ctx, in)
+}
+
+func (c localClient) GetClickCounts(ctx context.Context, in *analyticsclient.GetClickCountsRequest, _ ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+	return c.server.GetClickCounts(ctx, in)
+}
+
+func (c localClient) GetClickRanking(ctx context.Context, in *analyticsclient.GetClickRankingRequest, _ ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error) {
+	return c.server.GetClickRanking(ctx, in)
+}
@@ -1,11 +1,20 @@
 package config
 
-import "github.com/zeromicro/go-zero/zrpc"
+import (
+	"go-shortener/common/health"
+
+	"github.com/zeromicro/go-zero/zrpc"
+)
 
 type Config struct {
 	zrpc.RpcServerConf
-	DataSource string
-	Pool       PoolConfig
+	DataSource string     `json:",inherit"`
+	Pool       PoolConfig `json:",inherit"`
+	// UniqueVisitors controls when unique visitor counts are estimated.
+	UniqueVisitors UniqueVisitorsConf
+	// HealthCheckPort serves /livez and /readyz over HTTP.
+	HealthCheckPort int `json:",default=8083"`
+	Readiness       health.Conf
 }
 
 type PoolConfig struct {
@@ -13,3 +22,13 @@ type PoolConfig struct {
 	MaxIdleConns    int `json:",default=5"`
 	ConnMaxLifetime int `json:",default=3600"` // seconds
 }
+
+type UniqueVisitorsConf struct {
+	// ApproximateAbove switches to HyperLogLog estimates once a range holds
+	// more clicks than this. Zero only estimates on request.
+	ApproximateAbove int64 `json:",default=1000000"`
+	// Retention mirrors the consumer's Partitions.Retention: months of raw
+	// clicks kept. Unique visitors are counted from raw clicks only, so ranges
+	// reaching further back are flagged as partial. 0 keeps them forever.
+	Retention int `json:",default=13"`
+}
@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"go-shortener/pkg/hll"
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/mr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// rangePrecision sizes the HyperLogLog sketch of a whole range (~0.8% error).
+	rangePrecision = 14
+	// bucketPrecision sizes the sketch of each time bucket (~3.3% error).
+	bucketPrecision = 10
+)
+
+type GetAnalyticsSummaryLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+	now func() time.Time
+}
+
+func NewGetAnalyticsSummaryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAnalyticsSummaryLogic {
+	return &GetAnalyticsSummaryLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+		now:    time.Now,
+	}
+}
+
+func (l *GetAnalyticsSummaryLogic) GetAnalyticsSummary(in *analytics.GetAnalyticsSummaryRequest) (*analytics.GetAnalyticsSummaryResponse, error) {
+	logx.WithContext(l.ctx).Infow("get analytics summary",
+		logx.Field("short_code", in.ShortCode),
+		logx.Field("from", in.From),
+		logx.Field("to", in.To),
+		logx.Field("approximate", in.Approximate),
+	)
+
+	from, to, err := timeRange(in.From, in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &analytics.GetAnalyticsSummaryResponse{ShortCode: in.ShortCode}
+	dimension := func(name string, dst *[]*analytics.DimensionCount) func() error {
+		return func() error {
+			counts, err := l.svcCtx.ClickModel.CountByDimension(l.ctx, in.ShortCode, name, from, to)
+			if err != nil {
+				return err
+			}
+			*dst = dimensionCounts(counts)
+			return nil
+		}
+	}
+
+	err = mr.Finish(
+		func() error {
+			total, err := l.svcCtx.ClickModel.CountInRange(l.ctx, in.ShortCode, from, to)
+			resp.TotalClicks = total
+			return err
+		},
+		dimension(model.DimensionCountry, &resp.Countries),
+		dimension(model.DimensionDevice, &resp.Devices),
+		dimension(model.DimensionTrafficSource, &resp.TrafficSources),
+	)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get analytics summary",
+			logx.Field("short_code", in.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	resp.UniqueVisitorsApproximate = approximateUniques(l.svcCtx.Config.UniqueVisitors, in.Approximate, resp.TotalClicks)
+	resp.UniqueVisitors, err = l.uniqueVisitors(in.ShortCode, from, to, resp.UniqueVisitorsApproximate)
+	if err == nil {
+		resp.UniqueVisitorsPartial, err = partialUniques(l.ctx, l.svcCtx, in.ShortCode, from, to, l.now())
+	}
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to count unique visitors",
+			logx.Field("short_code", in.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (l *GetAnalyticsSummaryLogic) uniqueVisitors(shortCode string, from, to time.Time, approximate bool) (int64, error) {
+	if !approximate {
+		return l.svcCtx.ClickModel.CountUniqueInRange(l.ctx, shortCode, from, to)
+	}
+
+	registers, err := l.svcCtx.ClickModel.VisitorRegisters(l.ctx, shortCode, rangePrecision, from, to)
+	if err != nil {
+		return 0, err
+	}
+	sketch, err := hll.New(rangePrecision)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range registers {
+		sketch.Set(int(r.Index), uint8(r.Rank))
+	}
+	return sketch.Estimate(), nil
+}
+
+// approximateUniques reports whether unique visitors among clicks are
+// estimated rather than counted exactly.
+func approximateUniques(c config.UniqueVisitorsConf, requested bool, clicks int64) bool {
+	return requested || (c.ApproximateAbove > 0 && clicks > c.ApproximateAbove)
+}
+
+// partialUniques reports whether [from, to) holds clicks older than the raw
+// click retention. Their counts survive in the rollups, but their visitors
+// are gone, so unique visitors only cover the retained part of the range.
+func partialUniques(ctx context.Context, svcCtx *svc.ServiceContext, shortCode string, from, to, now time.Time) (bool, error) {
+	retention := svcCtx.Config.UniqueVisitors.Retention
+	if retention <= 0 {
+		return false, nil
+	}
+
+	// The consumer drops monthly partitions ending at or before this cutoff.
+	now = now.UTC()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -retention, 0)
+	if !from.IsZero() && !from.Before(cutoff) {
+		return false, nil
+	}
+	if !to.IsZero() && to.Before(cutoff) {
+		cutoff = to
+	}
+
+	expired, err := svcCtx.ClickModel.CountInRange(ctx, shortCode, from, cutoff)
+	if err != nil {
+		return false, err
+	}
+	return expired > 0, nil
+}
+
+// timeRange converts an optional [from, to) range of unix seconds; zero leaves
+// that side open.
+func timeRange(from, to int64) (time.Time, time.Time, error) {
+	if from < 0 || to < 0 || (from > 0 && to > 0 && from >= to) {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "invalid time range: from must be before to")
+	}
+
+	var fromTime, toTime time.Time
+	if from > 0 {
+		fromTime = time.Unix(from, 0).UTC()
+	}
+	if to > 0 {
+		toTime = time.Unix(to, 0).UTC()
+	}
+	return fromTime, toTime, nil
+}
+
+func dimensionCounts(counts []*model.DimensionCount) []*analytics.DimensionCount {
+	resp := make([]*analytics.DimensionCount, 0, len(counts))
+	for _, c := range counts {
+		resp = append(resp, &analytics.DimensionCount{
+			Value:  c.Value,
+			Clicks: c.Clicks,
+		})
+	}
+	return resp
+}
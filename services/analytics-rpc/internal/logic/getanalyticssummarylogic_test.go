@@ -0,0 +1,278 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-shortener/pkg/hll"
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAnalyticsSummaryLogic_Success(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mockModel := &model.MockClicksModel{
+		CountInRangeFunc: func(ctx context.Context, shortCode string, f, tt time.Time) (int64, error) {
+			assert.Equal(t, "abc12345", shortCode)
+			assert.Equal(t, from, f)
+			assert.Equal(t, to, tt)
+			return 42, nil
+		},
+		CountByDimensionFunc: func(ctx context.Context, shortCode, dimension string, f, tt time.Time) ([]*model.DimensionCount, error) {
+			assert.Equal(t, from, f)
+			assert.Equal(t, to, tt)
+			switch dimension {
+			case model.DimensionCountry:
+				return []*model.DimensionCount{{Value: "DE", Clicks: 30}, {Value: "US", Clicks: 12}}, nil
+			case model.DimensionDevice:
+				return []*model.DimensionCount{{Value: "Mobile", Clicks: 42}}, nil
+			case model.DimensionTrafficSource:
+				return []*model.DimensionCount{{Value: "Direct", Clicks: 40}, {Value: "Social", Clicks: 2}}, nil
+			}
+			t.Fatalf("unexpected dimension %q", dimension)
+			return nil, nil
+		},
+		CountUniqueInRangeFunc: func(ctx context.Context, shortCode string, f, tt time.Time) (int64, error) {
+			assert.Equal(t, from, f)
+			assert.Equal(t, to, tt)
+			return 17, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{
+		ShortCode: "abc12345",
+		From:      from.Unix(),
+		To:        to.Unix(),
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "abc12345", resp.ShortCode)
+	assert.Equal(t, int64(42), resp.TotalClicks)
+	require.Len(t, resp.Countries, 2)
+	assert.Equal(t, "DE", resp.Countries[0].Value)
+	assert.Equal(t, int64(30), resp.Countries[0].Clicks)
+	require.Len(t, resp.Devices, 1)
+	assert.Equal(t, "Mobile", resp.Devices[0].Value)
+	require.Len(t, resp.TrafficSources, 2)
+	assert.Equal(t, int64(2), resp.TrafficSources[1].Clicks)
+	assert.Equal(t, int64(17), resp.UniqueVisitors)
+	assert.False(t, resp.UniqueVisitorsApproximate)
+}
+
+func TestGetAnalyticsSummaryLogic_ApproximateUniqueVisitors(t *testing.T) {
+	sketch, err := hll.New(rangePrecision)
+	require.NoError(t, err)
+	var registers []*model.VisitorRegister
+	for i := 0; i < 5000; i++ {
+		hash := fmt.Sprintf("%032x", sha256.Sum256([]byte(strconv.Itoa(i))))
+		require.NoError(t, sketch.AddHash(hash))
+	}
+	for i, rank := range sketch.Registers() {
+		if rank > 0 {
+			registers = append(registers, &model.VisitorRegister{Index: int64(i), Rank: int64(rank)})
+		}
+	}
+
+	tests := []struct {
+		name        string
+		threshold   int64
+		approximate bool
+	}{
+		{name: "requested", threshold: 0, approximate: true},
+		{name: "above threshold", threshold: 1000, approximate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModel := &model.MockClicksModel{
+				CountInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+					return 8000, nil
+				},
+				CountByDimensionFunc: func(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*model.DimensionCount, error) {
+					return nil, nil
+				},
+				VisitorRegistersFunc: func(ctx context.Context, shortCode string, precision uint8, from, to time.Time) ([]*model.VisitorRegister, error) {
+					assert.Equal(t, uint8(rangePrecision), precision)
+					return registers, nil
+				},
+			}
+
+			svcCtx := &svc.ServiceContext{
+				Config:     config.Config{UniqueVisitors: config.UniqueVisitorsConf{ApproximateAbove: tt.threshold}},
+				ClickModel: mockModel,
+			}
+
+			logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+			resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{
+				ShortCode:   "abc12345",
+				Approximate: tt.approximate,
+			})
+
+			require.NoError(t, err)
+			assert.True(t, resp.UniqueVisitorsApproximate)
+			assert.InDelta(t, 5000, resp.UniqueVisitors, 5000*0.03)
+		})
+	}
+}
+
+func TestGetAnalyticsSummaryLogic_OpenRange(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+			assert.True(t, from.IsZero())
+			assert.True(t, to.IsZero())
+			return 0, nil
+		},
+		CountByDimensionFunc: func(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*model.DimensionCount, error) {
+			return nil, nil
+		},
+		CountUniqueInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+			return 0, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{ShortCode: "abc12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.TotalClicks)
+	assert.Empty(t, resp.Countries)
+}
+
+func TestGetAnalyticsSummaryLogic_PartialUniqueVisitors(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	// Three months of retention keep raw clicks from December on
+	cutoff := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		expired  int64
+		want     bool
+		countsAt []time.Time
+	}{
+		{
+			name:     "range past retention with expired clicks",
+			from:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			expired:  5,
+			want:     true,
+			countsAt: []time.Time{now, cutoff},
+		},
+		{
+			name:     "range past retention without clicks before cutoff",
+			from:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			countsAt: []time.Time{now, cutoff},
+		},
+		{
+			name:     "range within retention",
+			from:     cutoff,
+			countsAt: []time.Time{now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var countedTo []time.Time
+			mockModel := &model.MockClicksModel{
+				CountInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+					assert.Equal(t, tt.from, from)
+					countedTo = append(countedTo, to)
+					if to.Equal(cutoff) {
+						return tt.expired, nil
+					}
+					return 42, nil
+				},
+				CountByDimensionFunc: func(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*model.DimensionCount, error) {
+					return nil, nil
+				},
+				CountUniqueInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+					return 17, nil
+				},
+			}
+
+			svcCtx := &svc.ServiceContext{
+				Config:     config.Config{UniqueVisitors: config.UniqueVisitorsConf{Retention: 3}},
+				ClickModel: mockModel,
+			}
+
+			logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+			logic.now = func() time.Time { return now }
+			resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{
+				ShortCode: "abc12345",
+				From:      tt.from.Unix(),
+				To:        now.Unix(),
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(17), resp.UniqueVisitors)
+			assert.Equal(t, tt.want, resp.UniqueVisitorsPartial)
+			assert.Equal(t, tt.countsAt, countedTo)
+		})
+	}
+}
+
+func TestGetAnalyticsSummaryLogic_InvalidRange(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{
+		ShortCode: "abc12345",
+		From:      2000,
+		To:        1000,
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetAnalyticsSummaryLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountInRangeFunc: func(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+			return 0, nil
+		},
+		CountByDimensionFunc: func(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*model.DimensionCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&analytics.GetAnalyticsSummaryRequest{ShortCode: "abc12345"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
@@ -0,0 +1,74 @@
+package logic
+
+import (
+	"context"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxClickCountsCodes = 1000
+
+type GetClickCountsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetClickCountsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetClickCountsLogic {
+	return &GetClickCountsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetClickCountsLogic) GetClickCounts(in *analytics.GetClickCountsRequest) (*analytics.GetClickCountsResponse, error) {
+	logx.WithContext(l.ctx).Infow("get click counts",
+		logx.Field("short_codes", len(in.ShortCodes)),
+	)
+
+	if len(in.ShortCodes) > maxClickCountsCodes {
+		return nil, status.Errorf(codes.InvalidArgument, "too many short codes: at most %d allowed", maxClickCountsCodes)
+	}
+
+	shortCodes := make([]string, 0, len(in.ShortCodes))
+	seen := make(map[string]struct{}, len(in.ShortCodes))
+	for _, code := range in.ShortCodes {
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		shortCodes = append(shortCodes, code)
+	}
+	if len(shortCodes) == 0 {
+		return &analytics.GetClickCountsResponse{}, nil
+	}
+
+	counts, err := l.svcCtx.ClickModel.CountByShortCodes(l.ctx, shortCodes)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get click counts",
+			logx.Field("short_codes", len(shortCodes)),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	clicks := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		clicks[c.ShortCode] = c.Clicks
+	}
+	links := make([]*analytics.LinkClicks, 0, len(shortCodes))
+	for _, code := range shortCodes {
+		links = append(links, &analytics.LinkClicks{
+			ShortCode: code,
+			Clicks:    clicks[code],
+		})
+	}
+
+	return &analytics.GetClickCountsResponse{Links: links}, nil
+}
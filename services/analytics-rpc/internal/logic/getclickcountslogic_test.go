@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetClickCountsLogic_Success(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByShortCodesFunc: func(ctx context.Context, shortCodes []string) ([]*model.LinkCount, error) {
+			assert.Equal(t, []string{"abc12345", "unknown", "xyz98765"}, shortCodes, "duplicates are dropped")
+			return []*model.LinkCount{
+				{ShortCode: "xyz98765", Clicks: 7},
+				{ShortCode: "abc12345", Clicks: 42},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickCountsLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickCounts(&analytics.GetClickCountsRequest{
+		ShortCodes: []string{"abc12345", "unknown", "abc12345", "xyz98765"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 3)
+	assert.Equal(t, "abc12345", resp.Links[0].ShortCode)
+	assert.Equal(t, int64(42), resp.Links[0].Clicks)
+	assert.Equal(t, "unknown", resp.Links[1].ShortCode)
+	assert.Equal(t, int64(0), resp.Links[1].Clicks, "codes without clicks count zero")
+	assert.Equal(t, "xyz98765", resp.Links[2].ShortCode)
+	assert.Equal(t, int64(7), resp.Links[2].Clicks)
+}
+
+func TestGetClickCountsLogic_Empty(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	logic := NewGetClickCountsLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickCounts(&analytics.GetClickCountsRequest{})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Links)
+}
+
+func TestGetClickCountsLogic_TooManyCodes(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	logic := NewGetClickCountsLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickCounts(&analytics.GetClickCountsRequest{
+		ShortCodes: make([]string, maxClickCountsCodes+1),
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetClickCountsLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByShortCodesFunc: func(ctx context.Context, shortCodes []string) ([]*model.LinkCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickCountsLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickCounts(&analytics.GetClickCountsRequest{
+		ShortCodes: []string{"abc12345"},
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
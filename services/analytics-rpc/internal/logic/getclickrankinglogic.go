@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"context"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRankingPage = 100
+	maxRankingPage     = 1000
+)
+
+type GetClickRankingLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetClickRankingLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetClickRankingLogic {
+	return &GetClickRankingLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetClickRankingLogic) GetClickRanking(in *analytics.GetClickRankingRequest) (*analytics.GetClickRankingResponse, error) {
+	logx.WithContext(l.ctx).Infow("get click ranking",
+		logx.Field("limit", in.Limit),
+		logx.Field("after", in.After.GetShortCode()),
+		logx.Field("ascending", in.Ascending),
+	)
+
+	limit := int(in.Limit)
+	switch {
+	case limit < 0:
+		return nil, status.Error(codes.InvalidArgument, "invalid limit: must not be negative")
+	case limit == 0:
+		limit = defaultRankingPage
+	case limit > maxRankingPage:
+		limit = maxRankingPage
+	}
+	var after *model.LinkCount
+	if in.After != nil {
+		if in.After.ShortCode == "" || in.After.Clicks < 1 {
+			return nil, status.Error(codes.InvalidArgument, "invalid after: must be an entry of the ranking")
+		}
+		after = &model.LinkCount{ShortCode: in.After.ShortCode, Clicks: in.After.Clicks}
+	}
+
+	counts, err := l.svcCtx.ClickModel.RankShortCodes(l.ctx, in.Ascending, after, limit)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to rank short codes",
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	links := make([]*analytics.LinkClicks, 0, len(counts))
+	for _, c := range counts {
+		links = append(links, &analytics.LinkClicks{
+			ShortCode: c.ShortCode,
+			Clicks:    c.Clicks,
+		})
+	}
+
+	return &analytics.GetClickRankingResponse{Links: links}, nil
+}
@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetClickRankingLogic_Success(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		RankShortCodesFunc: func(ctx context.Context, ascending bool, after *model.LinkCount, limit int) ([]*model.LinkCount, error) {
+			assert.True(t, ascending)
+			assert.Equal(t, 2, limit)
+			assert.Equal(t, &model.LinkCount{ShortCode: "quiet000", Clicks: 1}, after)
+			return []*model.LinkCount{
+				{ShortCode: "quiet001", Clicks: 1},
+				{ShortCode: "quiet002", Clicks: 3},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	resp, err := NewGetClickRankingLogic(context.Background(), svcCtx).GetClickRanking(&analytics.GetClickRankingRequest{
+		Limit:     2,
+		Ascending: true,
+		After:     &analytics.LinkClicks{ShortCode: "quiet000", Clicks: 1},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, "quiet001", resp.Links[0].ShortCode)
+	assert.Equal(t, int64(3), resp.Links[1].Clicks)
+}
+
+func TestGetClickRankingLogic_Limit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int32
+		want  int
+	}{
+		{name: "default", limit: 0, want: defaultRankingPage},
+		{name: "capped", limit: 5000, want: maxRankingPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModel := &model.MockClicksModel{
+				RankShortCodesFunc: func(ctx context.Context, ascending bool, after *model.LinkCount, limit int) ([]*model.LinkCount, error) {
+					assert.Nil(t, after)
+					assert.Equal(t, tt.want, limit)
+					return nil, nil
+				},
+			}
+
+			svcCtx := &svc.ServiceContext{
+				Config:     config.Config{},
+				ClickModel: mockModel,
+			}
+
+			_, err := NewGetClickRankingLogic(context.Background(), svcCtx).GetClickRanking(&analytics.GetClickRankingRequest{Limit: tt.limit})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetClickRankingLogic_InvalidArgument(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *analytics.GetClickRankingRequest
+	}{
+		{name: "negative limit", req: &analytics.GetClickRankingRequest{Limit: -1}},
+		{name: "empty after", req: &analytics.GetClickRankingRequest{After: &analytics.LinkClicks{Clicks: 3}}},
+		{name: "unclicked after", req: &analytics.GetClickRankingRequest{After: &analytics.LinkClicks{ShortCode: "abc12345"}}},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGetClickRankingLogic(context.Background(), svcCtx).GetClickRanking(tt.req)
+			require.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestGetClickRankingLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		RankShortCodesFunc: func(ctx context.Context, ascending bool, after *model.LinkCount, limit int) ([]*model.LinkCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	resp, err := NewGetClickRankingLogic(context.Background(), svcCtx).GetClickRanking(&analytics.GetClickRankingRequest{})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
@@ -0,0 +1,225 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"go-shortener/pkg/hll"
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBuckets bounds the response size, e.g. one day of minutes fits.
+const maxBuckets = 1500
+
+// defaultSpans is the window returned when the request has no range.
+var defaultSpans = map[string]time.Duration{
+	model.IntervalMinute: time.Hour,
+	model.IntervalHour:   24 * time.Hour,
+	model.IntervalDay:    30 * 24 * time.Hour,
+	model.IntervalWeek:   12 * 7 * 24 * time.Hour,
+}
+
+type GetClickTimeSeriesLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+	now func() time.Time
+}
+
+func NewGetClickTimeSeriesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetClickTimeSeriesLogic {
+	return &GetClickTimeSeriesLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+		now:    time.Now,
+	}
+}
+
+func (l *GetClickTimeSeriesLogic) GetClickTimeSeries(in *analytics.GetClickTimeSeriesRequest) (*analytics.GetClickTimeSeriesResponse, error) {
+	logx.WithContext(l.ctx).Infow("get click time series",
+		logx.Field("short_code", in.ShortCode),
+		logx.Field("interval", in.Interval),
+		logx.Field("timezone", in.Timezone),
+		logx.Field("approximate", in.Approximate),
+	)
+
+	interval := in.Interval
+	if interval == "" {
+		interval = model.IntervalHour
+	}
+	span, ok := defaultSpans[interval]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid interval %q: must be minute, hour, day or week", in.Interval)
+	}
+
+	timezone := in.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid timezone %q", in.Timezone)
+	}
+
+	from, to, err := timeRange(in.From, in.To)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = l.now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-span)
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "invalid time range: from must be before to")
+	}
+
+	starts := bucketStarts(from, to, interval, loc)
+	if len(starts) > maxBuckets {
+		return nil, status.Errorf(codes.InvalidArgument, "time range too large: %d %s buckets exceeds the maximum of %d",
+			len(starts), interval, maxBuckets)
+	}
+
+	counts, err := l.svcCtx.ClickModel.CountByBucket(l.ctx, in.ShortCode, interval, timezone, from, to)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get click time series",
+			logx.Field("short_code", in.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	var total int64
+	clicksByStart := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		clicksByStart[c.Bucket.Unix()] = c.Clicks
+		total += c.Clicks
+	}
+
+	approximate := approximateUniques(l.svcCtx.Config.UniqueVisitors, in.Approximate, total)
+	visitorsByStart, err := l.uniqueVisitors(in.ShortCode, interval, timezone, from, to, approximate)
+	var partial bool
+	if err == nil {
+		partial, err = partialUniques(l.ctx, l.svcCtx, in.ShortCode, from, to, l.now())
+	}
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to count unique visitors per bucket",
+			logx.Field("short_code", in.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	buckets := make([]*analytics.TimeBucket, 0, len(starts))
+	for _, start := range starts {
+		buckets = append(buckets, &analytics.TimeBucket{
+			Start:          start.Unix(),
+			Clicks:         clicksByStart[start.Unix()],
+			UniqueVisitors: visitorsByStart[start.Unix()],
+		})
+	}
+
+	return &analytics.GetClickTimeSeriesResponse{
+		ShortCode:                 in.ShortCode,
+		Interval:                  interval,
+		Timezone:                  timezone,
+		From:                      from.Unix(),
+		To:                        to.Unix(),
+		Buckets:                   buckets,
+		UniqueVisitorsApproximate: approximate,
+		UniqueVisitorsPartial:     partial,
+	}, nil
+}
+
+// uniqueVisitors returns the unique visitors of each non-empty bucket keyed by
+// its start in unix seconds.
+func (l *GetClickTimeSeriesLogic) uniqueVisitors(shortCode, interval, timezone string, from, to time.Time, approximate bool) (map[int64]int64, error) {
+	if !approximate {
+		counts, err := l.svcCtx.ClickModel.CountUniqueByBucket(l.ctx, shortCode, interval, timezone, from, to)
+		if err != nil {
+			return nil, err
+		}
+		visitors := make(map[int64]int64, len(counts))
+		for _, c := range counts {
+			visitors[c.Bucket.Unix()] = c.Visitors
+		}
+		return visitors, nil
+	}
+
+	registers, err := l.svcCtx.ClickModel.VisitorRegistersByBucket(l.ctx, shortCode, interval, timezone, bucketPrecision, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sketches := make(map[int64]*hll.Sketch)
+	for _, r := range registers {
+		start := r.Bucket.Unix()
+		sketch, ok := sketches[start]
+		if !ok {
+			if sketch, err = hll.New(bucketPrecision); err != nil {
+				return nil, err
+			}
+			sketches[start] = sketch
+		}
+		sketch.Set(int(r.Index), uint8(r.Rank))
+	}
+	visitors := make(map[int64]int64, len(sketches))
+	for start, sketch := range sketches {
+		visitors[start] = sketch.Estimate()
+	}
+	return visitors, nil
+}
+
+// bucketStarts returns the start of every bucket overlapping [from, to), so
+// buckets without clicks are reported as zero. Stops early once maxBuckets is
+// exceeded.
+func bucketStarts(from, to time.Time, interval string, loc *time.Location) []time.Time {
+	var starts []time.Time
+	for start := truncate(from, interval, loc); start.Before(to) && len(starts) <= maxBuckets; start = next(start, interval, loc) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// truncate mirrors Postgres date_trunc(interval, t, loc). Minutes and hours are
+// truncated on the local wall clock using the offset in effect at t, so the
+// repeated hour of a DST fall-back yields two distinct buckets. Weeks start on
+// Monday (ISO 8601).
+func truncate(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case model.IntervalMinute, model.IntervalHour:
+		d := time.Minute
+		if interval == model.IntervalHour {
+			d = time.Hour
+		}
+		_, offset := t.Zone()
+		shift := time.Duration(offset) * time.Second
+		return t.Add(shift).Truncate(d).Add(-shift).In(loc)
+	case model.IntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// next returns the start of the bucket following start.
+func next(start time.Time, interval string, loc *time.Location) time.Time {
+	switch interval {
+	case model.IntervalMinute:
+		return truncate(start.Add(time.Minute), interval, loc)
+	case model.IntervalHour:
+		return truncate(start.Add(time.Hour), interval, loc)
+	case model.IntervalWeek:
+		return truncate(start.AddDate(0, 0, 7), interval, loc)
+	default:
+		return truncate(start.AddDate(0, 0, 1), interval, loc)
+	}
+}
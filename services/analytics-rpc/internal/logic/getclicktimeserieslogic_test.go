@@ -0,0 +1,337 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"go-shortener/pkg/hll"
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetClickTimeSeriesLogic_ZeroFillsGaps(t *testing.T) {
+	from := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 1, 14, 0, 0, 0, time.UTC)
+
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketCount, error) {
+			assert.Equal(t, "abc12345", shortCode)
+			assert.Equal(t, model.IntervalHour, interval)
+			assert.Equal(t, "UTC", timezone)
+			assert.Equal(t, from, f)
+			assert.Equal(t, to, tt)
+			return []*model.BucketCount{
+				{Bucket: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), Clicks: 3},
+				{Bucket: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Clicks: 7},
+			}, nil
+		},
+		CountUniqueByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketVisitors, error) {
+			assert.Equal(t, model.IntervalHour, interval)
+			return []*model.BucketVisitors{
+				{Bucket: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), Visitors: 2},
+				{Bucket: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Visitors: 7},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{
+		ShortCode: "abc12345",
+		From:      from.Unix(),
+		To:        to.Unix(),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.IntervalHour, resp.Interval)
+	assert.Equal(t, "UTC", resp.Timezone)
+	require.Len(t, resp.Buckets, 4)
+
+	want := []int64{3, 0, 7, 0}
+	wantVisitors := []int64{2, 0, 7, 0}
+	for i, b := range resp.Buckets {
+		assert.Equal(t, time.Date(2025, 6, 1, 10+i, 0, 0, 0, time.UTC).Unix(), b.Start)
+		assert.Equal(t, want[i], b.Clicks)
+		assert.Equal(t, wantVisitors[i], b.UniqueVisitors)
+	}
+	assert.False(t, resp.UniqueVisitorsApproximate)
+}
+
+func TestGetClickTimeSeriesLogic_ApproximateUniqueVisitors(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+
+	// Three visitors on the first day, none on the second
+	sketch, err := hll.New(bucketPrecision)
+	require.NoError(t, err)
+	for _, hash := range []string{"00010000000000001", "40000000000000010", "80000000000000100"} {
+		require.NoError(t, sketch.AddHash(hash))
+	}
+	var registers []*model.VisitorRegister
+	for i, rank := range sketch.Registers() {
+		if rank > 0 {
+			registers = append(registers, &model.VisitorRegister{Bucket: from, Index: int64(i), Rank: int64(rank)})
+		}
+	}
+
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketCount, error) {
+			return []*model.BucketCount{{Bucket: from, Clicks: 5000}}, nil
+		},
+		VisitorRegistersByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, precision uint8, f, tt time.Time) ([]*model.VisitorRegister, error) {
+			assert.Equal(t, uint8(bucketPrecision), precision)
+			assert.Equal(t, model.IntervalDay, interval)
+			return registers, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{UniqueVisitors: config.UniqueVisitorsConf{ApproximateAbove: 1000}},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{
+		ShortCode: "abc12345",
+		Interval:  model.IntervalDay,
+		From:      from.Unix(),
+		To:        to.Unix(),
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.UniqueVisitorsApproximate)
+	require.Len(t, resp.Buckets, 2)
+	assert.Equal(t, int64(3), resp.Buckets[0].UniqueVisitors)
+	assert.Equal(t, int64(0), resp.Buckets[1].UniqueVisitors)
+}
+
+func TestGetClickTimeSeriesLogic_Timezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, berlin)
+	to := time.Date(2025, 6, 4, 0, 0, 0, 0, berlin)
+
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketCount, error) {
+			assert.Equal(t, "Europe/Berlin", timezone)
+			// Postgres returns bucket starts as timestamptz, read back in UTC
+			return []*model.BucketCount{
+				{Bucket: time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC), Clicks: 5},
+			}, nil
+		},
+		CountUniqueByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketVisitors, error) {
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{
+		ShortCode: "abc12345",
+		Interval:  model.IntervalDay,
+		Timezone:  "Europe/Berlin",
+		From:      from.Unix(),
+		To:        to.Unix(),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Buckets, 3)
+	assert.Equal(t, from.Unix(), resp.Buckets[0].Start)
+	assert.Equal(t, int64(0), resp.Buckets[0].Clicks)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, berlin).Unix(), resp.Buckets[1].Start)
+	assert.Equal(t, int64(5), resp.Buckets[1].Clicks)
+}
+
+func TestGetClickTimeSeriesLogic_DefaultRange(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 34, 56, 0, time.UTC)
+
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*model.BucketCount, error) {
+			assert.Equal(t, now, to)
+			assert.Equal(t, now.Add(-time.Hour), from)
+			return nil, nil
+		},
+		CountUniqueByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*model.BucketVisitors, error) {
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	logic.now = func() time.Time { return now }
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{
+		ShortCode: "abc12345",
+		Interval:  model.IntervalMinute,
+	})
+
+	require.NoError(t, err)
+	// 11:34 .. 12:34 inclusive of the partial minute at each end
+	assert.Len(t, resp.Buckets, 61)
+}
+
+func TestGetClickTimeSeriesLogic_PartialUniqueVisitors(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketCount, error) {
+			return []*model.BucketCount{{Bucket: from, Clicks: 7}, {Bucket: to.AddDate(0, 0, -7), Clicks: 3}}, nil
+		},
+		CountUniqueByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, f, tt time.Time) ([]*model.BucketVisitors, error) {
+			// November clicks have expired; only December still has visitors
+			return []*model.BucketVisitors{{Bucket: to.AddDate(0, 0, -7), Visitors: 2}}, nil
+		},
+		CountInRangeFunc: func(ctx context.Context, shortCode string, f, tt time.Time) (int64, error) {
+			assert.Equal(t, from, f)
+			assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), tt)
+			return 7, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{UniqueVisitors: config.UniqueVisitorsConf{Retention: 3}},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	logic.now = func() time.Time { return now }
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{
+		ShortCode: "abc12345",
+		Interval:  model.IntervalDay,
+		From:      from.Unix(),
+		To:        to.Unix(),
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.UniqueVisitorsPartial)
+	assert.Equal(t, int64(7), resp.Buckets[0].Clicks)
+	assert.Equal(t, int64(0), resp.Buckets[0].UniqueVisitors)
+}
+
+func TestGetClickTimeSeriesLogic_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *analytics.GetClickTimeSeriesRequest
+	}{
+		{name: "unknown interval", req: &analytics.GetClickTimeSeriesRequest{ShortCode: "abc12345", Interval: "month"}},
+		{name: "unknown timezone", req: &analytics.GetClickTimeSeriesRequest{ShortCode: "abc12345", Timezone: "Mars/Olympus"}},
+		{name: "inverted range", req: &analytics.GetClickTimeSeriesRequest{ShortCode: "abc12345", From: 2000, To: 1000}},
+		{name: "too many buckets", req: &analytics.GetClickTimeSeriesRequest{ShortCode: "abc12345", Interval: model.IntervalMinute, From: 1, To: 7 * 24 * 3600}},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGetClickTimeSeriesLogic(context.Background(), svcCtx).GetClickTimeSeries(tt.req)
+			require.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestGetClickTimeSeriesLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByBucketFunc: func(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*model.BucketCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetClickTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetClickTimeSeries(&analytics.GetClickTimeSeriesRequest{ShortCode: "abc12345"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
+
+func TestTruncate(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval string
+		loc      *time.Location
+		want     time.Time
+	}{
+		{
+			name:     "minute",
+			t:        time.Date(2025, 6, 1, 10, 45, 30, 0, time.UTC),
+			interval: model.IntervalMinute,
+			loc:      time.UTC,
+			want:     time.Date(2025, 6, 1, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "hour in half-hour offset zone",
+			t:        time.Date(2025, 6, 1, 10, 45, 0, 0, kolkata),
+			interval: model.IntervalHour,
+			loc:      kolkata,
+			want:     time.Date(2025, 6, 1, 10, 0, 0, 0, kolkata),
+		},
+		{
+			name:     "repeated hour after DST fall-back",
+			t:        time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC), // 01:30 EST, second pass
+			interval: model.IntervalHour,
+			loc:      newYork,
+			want:     time.Date(2025, 11, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day in local timezone",
+			t:        time.Date(2025, 6, 2, 2, 0, 0, 0, time.UTC), // 22:00 on June 1 in New York
+			interval: model.IntervalDay,
+			loc:      newYork,
+			want:     time.Date(2025, 6, 1, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:     "week starts on monday",
+			t:        time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), // Sunday
+			interval: model.IntervalWeek,
+			loc:      time.UTC,
+			want:     time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.t, tt.interval, tt.loc)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"context"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTopLinks = 10
+	maxTopLinks     = 100
+)
+
+type GetTopLinksLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetTopLinksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTopLinksLogic {
+	return &GetTopLinksLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetTopLinksLogic) GetTopLinks(in *analytics.GetTopLinksRequest) (*analytics.GetTopLinksResponse, error) {
+	logx.WithContext(l.ctx).Infow("get top links",
+		logx.Field("from", in.From),
+		logx.Field("to", in.To),
+		logx.Field("limit", in.Limit),
+		logx.Field("offset", in.Offset),
+		logx.Field("filters", len(in.Filters)),
+	)
+
+	from, to, err := timeRange(in.From, in.To)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	switch {
+	case limit < 0:
+		return nil, status.Error(codes.InvalidArgument, "invalid limit: must not be negative")
+	case limit == 0:
+		limit = defaultTopLinks
+	case limit > maxTopLinks:
+		limit = maxTopLinks
+	}
+	if in.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid offset: must not be negative")
+	}
+
+	filters := make([]model.DimensionFilter, 0, len(in.Filters))
+	for _, f := range in.Filters {
+		switch f.Dimension {
+		case model.DimensionCountry, model.DimensionDevice, model.DimensionTrafficSource:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid filter dimension %q: must be %s, %s or %s",
+				f.Dimension, model.DimensionCountry, model.DimensionDevice, model.DimensionTrafficSource)
+		}
+		if f.Value == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid filter: %s value is empty", f.Dimension)
+		}
+		filters = append(filters, model.DimensionFilter{Dimension: f.Dimension, Value: f.Value})
+	}
+
+	counts, err := l.svcCtx.ClickModel.TopShortCodes(l.ctx, from, to, filters, limit, int(in.Offset))
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get top links",
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	links := make([]*analytics.LinkClicks, 0, len(counts))
+	for _, c := range counts {
+		links = append(links, &analytics.LinkClicks{
+			ShortCode: c.ShortCode,
+			Clicks:    c.Clicks,
+		})
+	}
+
+	return &analytics.GetTopLinksResponse{Links: links}, nil
+}
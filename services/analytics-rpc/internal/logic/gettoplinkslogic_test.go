@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetTopLinksLogic_Success(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mockModel := &model.MockClicksModel{
+		TopShortCodesFunc: func(ctx context.Context, f, tt time.Time, filters []model.DimensionFilter, limit, offset int) ([]*model.LinkCount, error) {
+			assert.Equal(t, from, f)
+			assert.Equal(t, to, tt)
+			assert.Equal(t, []model.DimensionFilter{{Dimension: model.DimensionCountry, Value: "DE"}}, filters)
+			assert.Equal(t, 5, limit)
+			assert.Equal(t, 10, offset)
+			return []*model.LinkCount{
+				{ShortCode: "popular1", Clicks: 120},
+				{ShortCode: "popular2", Clicks: 80},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetTopLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTopLinks(&analytics.GetTopLinksRequest{
+		From:    from.Unix(),
+		To:      to.Unix(),
+		Limit:   5,
+		Offset:  10,
+		Filters: []*analytics.DimensionFilter{{Dimension: model.DimensionCountry, Value: "DE"}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, "popular1", resp.Links[0].ShortCode)
+	assert.Equal(t, int64(120), resp.Links[0].Clicks)
+	assert.Equal(t, "popular2", resp.Links[1].ShortCode)
+}
+
+func TestGetTopLinksLogic_Limit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int32
+		want  int
+	}{
+		{name: "default", limit: 0, want: defaultTopLinks},
+		{name: "capped", limit: 1000, want: maxTopLinks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockModel := &model.MockClicksModel{
+				TopShortCodesFunc: func(ctx context.Context, from, to time.Time, filters []model.DimensionFilter, limit, offset int) ([]*model.LinkCount, error) {
+					assert.Equal(t, tt.want, limit)
+					return nil, nil
+				},
+			}
+
+			svcCtx := &svc.ServiceContext{
+				Config:     config.Config{},
+				ClickModel: mockModel,
+			}
+
+			resp, err := NewGetTopLinksLogic(context.Background(), svcCtx).GetTopLinks(&analytics.GetTopLinksRequest{Limit: tt.limit})
+			require.NoError(t, err)
+			assert.Empty(t, resp.Links)
+		})
+	}
+}
+
+func TestGetTopLinksLogic_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *analytics.GetTopLinksRequest
+	}{
+		{name: "negative limit", req: &analytics.GetTopLinksRequest{Limit: -1}},
+		{name: "negative offset", req: &analytics.GetTopLinksRequest{Offset: -1}},
+		{name: "inverted range", req: &analytics.GetTopLinksRequest{From: 2000, To: 1000}},
+		{name: "unknown dimension", req: &analytics.GetTopLinksRequest{Filters: []*analytics.DimensionFilter{{Dimension: "short_code", Value: "x"}}}},
+		{name: "empty filter value", req: &analytics.GetTopLinksRequest{Filters: []*analytics.DimensionFilter{{Dimension: model.DimensionDevice}}}},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: &model.MockClicksModel{},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGetTopLinksLogic(context.Background(), svcCtx).GetTopLinks(tt.req)
+			require.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestGetTopLinksLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		TopShortCodesFunc: func(ctx context.Context, from, to time.Time, filters []model.DimensionFilter, limit, offset int) ([]*model.LinkCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	resp, err := NewGetTopLinksLogic(context.Background(), svcCtx).GetTopLinks(&analytics.GetTopLinksRequest{})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"context"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetVariantClicksLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetVariantClicksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetVariantClicksLogic {
+	return &GetVariantClicksLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetVariantClicksLogic) GetVariantClicks(in *analytics.GetVariantClicksRequest) (*analytics.GetVariantClicksResponse, error) {
+	logx.WithContext(l.ctx).Infow("get variant clicks",
+		logx.Field("short_code", in.ShortCode),
+	)
+
+	counts, err := l.svcCtx.ClickModel.CountByVariant(l.ctx, in.ShortCode)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get variant clicks",
+			logx.Field("short_code", in.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	variants := make([]*analytics.VariantClicks, 0, len(counts))
+	for _, c := range counts {
+		variants = append(variants, &analytics.VariantClicks{
+			Variant: c.Variant,
+			Clicks:  c.Clicks,
+		})
+	}
+
+	return &analytics.GetVariantClicksResponse{
+		ShortCode: in.ShortCode,
+		Variants:  variants,
+	}, nil
+}
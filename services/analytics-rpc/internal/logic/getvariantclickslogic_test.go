@@ -0,0 +1,90 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-shortener/services/analytics-rpc/analytics"
+	"go-shortener/services/analytics-rpc/internal/config"
+	"go-shortener/services/analytics-rpc/internal/svc"
+	"go-shortener/services/analytics-rpc/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVariantClicksLogic_Success(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByVariantFunc: func(ctx context.Context, shortCode string) ([]*model.VariantCount, error) {
+			assert.Equal(t, "abc12345", shortCode)
+			return []*model.VariantCount{
+				{Variant: "a", Clicks: 30},
+				{Variant: "b", Clicks: 12},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetVariantClicksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetVariantClicks(&analytics.GetVariantClicksRequest{
+		ShortCode: "abc12345",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "abc12345", resp.ShortCode)
+	require.Len(t, resp.Variants, 2)
+	assert.Equal(t, "a", resp.Variants[0].Variant)
+	assert.Equal(t, int64(30), resp.Variants[0].Clicks)
+	assert.Equal(t, "b", resp.Variants[1].Variant)
+	assert.Equal(t, int64(12), resp.Variants[1].Clicks)
+}
+
+func TestGetVariantClicksLogic_NoVariants(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByVariantFunc: func(ctx context.Context, shortCode string) ([]*model.VariantCount, error) {
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetVariantClicksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetVariantClicks(&analytics.GetVariantClicksRequest{
+		ShortCode: "abc12345",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Empty(t, resp.Variants)
+}
+
+func TestGetVariantClicksLogic_DBError(t *testing.T) {
+	mockModel := &model.MockClicksModel{
+		CountByVariantFunc: func(ctx context.Context, shortCode string) ([]*model.VariantCount, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:     config.Config{},
+		ClickModel: mockModel,
+	}
+
+	logic := NewGetVariantClicksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetVariantClicks(&analytics.GetVariantClicksRequest{
+		ShortCode: "abc12345",
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "database connection timeout")
+}
@@ -27,3 +27,33 @@ func (s *AnalyticsServer) GetClickCount(ctx context.Context, in *analytics.GetCl
 	l := logic.NewGetClickCountLogic(ctx, s.svcCtx)
 	return l.GetClickCount(in)
 }
+
+func (s *AnalyticsServer) GetVariantClicks(ctx context.Context, in *analytics.GetVariantClicksRequest) (*analytics.GetVariantClicksResponse, error) {
+	l := logic.NewGetVariantClicksLogic(ctx, s.svcCtx)
+	return l.GetVariantClicks(in)
+}
+
+func (s *AnalyticsServer) GetAnalyticsSummary(ctx context.Context, in *analytics.GetAnalyticsSummaryRequest) (*analytics.GetAnalyticsSummaryResponse, error) {
+	l := logic.NewGetAnalyticsSummaryLogic(ctx, s.svcCtx)
+	return l.GetAnalyticsSummary(in)
+}
+
+func (s *AnalyticsServer) GetClickTimeSeries(ctx context.Context, in *analytics.GetClickTimeSeriesRequest) (*analytics.GetClickTimeSeriesResponse, error) {
+	l := logic.NewGetClickTimeSeriesLogic(ctx, s.svcCtx)
+	return l.GetClickTimeSeries(in)
+}
+
+func (s *AnalyticsServer) GetTopLinks(ctx context.Context, in *analytics.GetTopLinksRequest) (*analytics.GetTopLinksResponse, error) {
+	l := logic.NewGetTopLinksLogic(ctx, s.svcCtx)
+	return l.GetTopLinks(in)
+}
+
+func (s *AnalyticsServer) GetClickCounts(ctx context.Context, in *analytics.GetClickCountsRequest) (*analytics.GetClickCountsResponse, error) {
+	l := logic.NewGetClickCountsLogic(ctx, s.svcCtx)
+	return l.GetClickCounts(in)
+}
+
+func (s *AnalyticsServer) GetClickRanking(ctx context.Context, in *analytics.GetClickRankingRequest) (*analytics.GetClickRankingResponse, error) {
+	l := logic.NewGetClickRankingLogic(ctx, s.svcCtx)
+	return l.GetClickRanking(in)
+}
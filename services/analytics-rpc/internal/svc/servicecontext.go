@@ -3,6 +3,7 @@ package svc
 import (
 	"time"
 
+	"go-shortener/common/health"
 	"go-shortener/services/analytics-rpc/internal/config"
 	"go-shortener/services/analytics-rpc/model"
 
@@ -14,6 +15,7 @@ import (
 type ServiceContext struct {
 	Config     config.Config
 	ClickModel model.ClicksModel
+	Health     *health.Health
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
@@ -28,8 +30,12 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	logx.Infof("Connection pool configured: MaxOpen=%d, MaxIdle=%d, MaxLifetime=%ds",
 		c.Pool.MaxOpenConns, c.Pool.MaxIdleConns, c.Pool.ConnMaxLifetime)
 
+	checks := health.New(c.Readiness)
+	checks.Register("postgres", health.Ping(conn))
+
 	return &ServiceContext{
 		Config:     c,
 		ClickModel: model.NewClicksModel(conn),
+		Health:     checks,
 	}
 }
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ClickPartitionsModel = (*customClickPartitionsModel)(nil)
+
+const (
+	// clickPartitionLayout names monthly partitions of clicks, e.g.
+	// clicks_2025_06.
+	clickPartitionLayout = "clicks_2006_01"
+	// clickDefaultPartition catches clicks outside every monthly partition.
+	clickDefaultPartition = "clicks_default"
+	// clickDefaultArchive keeps expired clicks of the default partition when
+	// they are archived instead of deleted.
+	clickDefaultArchive = "clicks_default_archive"
+)
+
+type (
+	// ClickPartitionsModel manages the monthly range partitions of clicks.
+	ClickPartitionsModel interface {
+		Create(ctx context.Context, month time.Time) error
+		List(ctx context.Context) ([]string, error)
+		Drop(ctx context.Context, name string) error
+		Detach(ctx context.Context, name string) error
+		ExpireDefault(ctx context.Context, before time.Time, archive bool) (int64, error)
+	}
+
+	customClickPartitionsModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewClickPartitionsModel returns a model for the partitions of clicks.
+func NewClickPartitionsModel(conn sqlx.SqlConn) ClickPartitionsModel {
+	return &customClickPartitionsModel{
+		conn:  conn,
+		table: `"public"."clicks"`,
+	}
+}
+
+// ClickPartitionName returns the name of the partition holding the UTC month
+// starting at month.
+func ClickPartitionName(month time.Time) string {
+	return month.UTC().Format(clickPartitionLayout)
+}
+
+// ParseClickPartitionName returns the UTC month of a monthly partition name.
+// The default partition and foreign tables fail to parse.
+func ParseClickPartitionName(name string) (time.Time, error) {
+	return time.Parse(clickPartitionLayout, name)
+}
+
+// Create adds the partition for the UTC month containing month unless it
+// exists. Clicks of the month already in the default partition would make
+// the new partition overlap it, so they are moved over in the same
+// transaction: the default partition is detached, emptied of the month and
+// attached again. DDL takes no bind parameters; the bounds are formatted
+// timestamps.
+func (m *customClickPartitionsModel) Create(ctx context.Context, month time.Time) error {
+	month = month.UTC()
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	name := ClickPartitionName(from)
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+		name, m.table, from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		var stray bool
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %q WHERE clicked_at >= $1 AND clicked_at < $2)",
+			clickDefaultPartition)
+		if err := session.QueryRowCtx(ctx, &stray, query, from, to); err != nil {
+			return err
+		}
+		if !stray {
+			_, err := session.ExecCtx(ctx, create)
+			return err
+		}
+
+		steps := []struct {
+			query string
+			args  []any
+		}{
+			{query: fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %q", m.table, clickDefaultPartition)},
+			{query: create},
+			{
+				query: fmt.Sprintf("INSERT INTO %q (%s) SELECT %s FROM %q WHERE clicked_at >= $1 AND clicked_at < $2",
+					name, clicksRows, clicksRows, clickDefaultPartition),
+				args: []any{from, to},
+			},
+			{
+				query: fmt.Sprintf("DELETE FROM %q WHERE clicked_at >= $1 AND clicked_at < $2", clickDefaultPartition),
+				args:  []any{from, to},
+			},
+			{query: fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %q DEFAULT", m.table, clickDefaultPartition)},
+		}
+		for _, step := range steps {
+			if _, err := session.ExecCtx(ctx, step.query, step.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns the names of all partitions attached to clicks, including the
+// default partition.
+func (m *customClickPartitionsModel) List(ctx context.Context) ([]string, error) {
+	query := "SELECT c.relname FROM pg_inherits i " +
+		"JOIN pg_class c ON c.oid = i.inhrelid " +
+		"JOIN pg_class p ON p.oid = i.inhparent " +
+		"WHERE p.relname = 'clicks' ORDER BY c.relname"
+	var names []string
+	err := m.conn.QueryRowsCtx(ctx, &names, query)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Drop deletes a partition and its rows.
+func (m *customClickPartitionsModel) Drop(ctx context.Context, name string) error {
+	_, err := m.conn.ExecCtx(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %q", name))
+	return err
+}
+
+// Detach removes a partition from clicks, keeping it as a standalone table
+// for archiving.
+func (m *customClickPartitionsModel) Detach(ctx context.Context, name string) error {
+	_, err := m.conn.ExecCtx(ctx, fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %q", m.table, name))
+	return err
+}
+
+// ExpireDefault removes the clicks of the default partition older than
+// before, which no partition drop ever reaches, and returns how many were
+// removed. With archive set they are moved to clicks_default_archive, created
+// on first use, instead of being deleted.
+func (m *customClickPartitionsModel) ExpireDefault(ctx context.Context, before time.Time, archive bool) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %q WHERE clicked_at < $1", clickDefaultPartition)
+	if !archive {
+		result, err := m.conn.ExecCtx(ctx, query, before)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	var moved int64
+	err := m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %q (LIKE %s)", clickDefaultArchive, m.table)
+		if _, err := session.ExecCtx(ctx, create); err != nil {
+			return err
+		}
+
+		move := fmt.Sprintf("WITH expired AS (%s RETURNING %s) INSERT INTO %q (%s) SELECT %s FROM expired",
+			query, clicksRows, clickDefaultArchive, clicksRows, clicksRows)
+		result, err := session.ExecCtx(ctx, move, before)
+		if err != nil {
+			return err
+		}
+		moved, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
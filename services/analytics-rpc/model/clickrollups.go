@@ -0,0 +1,344 @@
+package model
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Rollup granularities, finest first. A query may read rollups up to the
+// coarsest granularity whose buckets never straddle one of its result groups.
+const (
+	granularityRaw = iota
+	granularityHour
+	granularityDay
+)
+
+const (
+	clickRollupsHourlyTable = `"public"."click_rollups_hourly"`
+	clickRollupsDailyTable  = `"public"."click_rollups_daily"`
+	clickTotalsTable        = `"public"."click_totals"`
+
+	// rollupColumns are the columns every source segment yields: one row per
+	// raw click, or per rollup bucket and dimension combination.
+	rollupColumns = "short_code, %s AS at, country_code, device_type, traffic_source, variant, %s AS clicks"
+
+	upsertRollupQuery = "INSERT INTO %s (short_code, bucket, country_code, device_type, traffic_source, variant, clicks) " +
+		"VALUES ($1, date_trunc('%s', $2::timestamptz, 'UTC'), $3, $4, $5, $6, 1) " +
+		"ON CONFLICT (short_code, bucket, country_code, device_type, traffic_source, variant) " +
+		"DO UPDATE SET clicks = %[1]s.clicks + 1"
+
+	upsertRollupsQuery = "INSERT INTO %s (short_code, bucket, country_code, device_type, traffic_source, variant, clicks) " +
+		"VALUES %s " +
+		"ON CONFLICT (short_code, bucket, country_code, device_type, traffic_source, variant) " +
+		"DO UPDATE SET clicks = %[1]s.clicks + EXCLUDED.clicks"
+
+	upsertTotalsQuery = "INSERT INTO %s (short_code, clicks) VALUES %s " +
+		"ON CONFLICT (short_code) DO UPDATE SET clicks = %[1]s.clicks + EXCLUDED.clicks"
+)
+
+// rollupKey identifies one rollup row.
+type rollupKey struct {
+	shortCode, countryCode, deviceType, trafficSource, variant string
+	bucket                                                     time.Time
+}
+
+// segment is a [from, to) slice of a query range served by one table. Zero
+// bounds are open.
+type segment struct {
+	granularity int
+	from, to    time.Time
+}
+
+// InsertWithRollups inserts a click and counts it in the hourly and daily
+// rollups and the click totals atomically, so a redelivered click rejected as
+// a duplicate is not counted twice.
+func (m *customClicksModel) InsertWithRollups(ctx context.Context, data *Clicks) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := m.withSession(session).Insert(ctx, data); err != nil {
+			return err
+		}
+		for _, rollup := range []struct{ table, field string }{
+			{clickRollupsHourlyTable, "hour"},
+			{clickRollupsDailyTable, "day"},
+		} {
+			query := fmt.Sprintf(upsertRollupQuery, rollup.table, rollup.field)
+			if _, err := session.ExecCtx(ctx, query, data.ShortCode, data.ClickedAt,
+				data.CountryCode, data.DeviceType, data.TrafficSource, data.Variant); err != nil {
+				return err
+			}
+		}
+		_, err := session.ExecCtx(ctx, fmt.Sprintf(upsertTotalsQuery, clickTotalsTable, "($1, 1)"), data.ShortCode)
+		return err
+	})
+}
+
+// InsertBatchWithRollups inserts clicks with a single multi-row statement and
+// counts the newly inserted ones in the hourly and daily rollups and the click
+// totals, all in one transaction. Clicks whose key already exists are skipped rather than failing
+// the batch; the ids of the inserted clicks are returned.
+func (m *customClicksModel) InsertBatchWithRollups(ctx context.Context, data []*Clicks) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(data))
+	args := make([]any, 0, len(data)*8)
+	for _, c := range data {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8))
+		args = append(args, c.Id, c.ShortCode, c.ClickedAt, c.CountryCode, c.DeviceType, c.TrafficSource, c.Variant, c.VisitorHash)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT DO NOTHING RETURNING id",
+		m.table, clicksRowsExpectAutoSet, strings.Join(values, ", "))
+
+	var inserted []string
+	err := m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		inserted = nil
+		if err := session.QueryRowsCtx(ctx, &inserted, query, args...); err != nil {
+			return err
+		}
+		if len(inserted) == 0 {
+			return nil
+		}
+
+		ids := make(map[string]struct{}, len(inserted))
+		for _, id := range inserted {
+			ids[id] = struct{}{}
+		}
+		for _, rollup := range []struct {
+			table       string
+			granularity int
+		}{
+			{clickRollupsHourlyTable, granularityHour},
+			{clickRollupsDailyTable, granularityDay},
+		} {
+			query, args := upsertRollups(rollup.table, rollup.granularity, data, ids)
+			if _, err := session.ExecCtx(ctx, query, args...); err != nil {
+				return err
+			}
+		}
+		query, args := upsertTotals(data, ids)
+		_, err := session.ExecCtx(ctx, query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// upsertRollups aggregates the inserted clicks into rollup rows, since one
+// upsert statement cannot touch the same row twice. Rows are sorted so that
+// concurrent batches lock them in the same order.
+func upsertRollups(table string, granularity int, data []*Clicks, inserted map[string]struct{}) (string, []any) {
+	counts := make(map[rollupKey]int64)
+	counted := make(map[string]struct{}, len(inserted))
+	for _, c := range data {
+		if _, ok := inserted[c.Id]; !ok {
+			continue
+		}
+		// A batch may hold the same event twice; only one row was inserted.
+		if _, ok := counted[c.Id]; ok {
+			continue
+		}
+		counted[c.Id] = struct{}{}
+		counts[rollupKey{
+			shortCode:     c.ShortCode,
+			countryCode:   c.CountryCode,
+			deviceType:    c.DeviceType,
+			trafficSource: c.TrafficSource,
+			variant:       c.Variant,
+			bucket:        floorBucket(c.ClickedAt, granularity),
+		}]++
+	}
+
+	keys := make([]rollupKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b rollupKey) int {
+		return cmp.Or(
+			strings.Compare(a.shortCode, b.shortCode),
+			a.bucket.Compare(b.bucket),
+			strings.Compare(a.countryCode, b.countryCode),
+			strings.Compare(a.deviceType, b.deviceType),
+			strings.Compare(a.trafficSource, b.trafficSource),
+			strings.Compare(a.variant, b.variant),
+		)
+	})
+
+	values := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys)*7)
+	for _, key := range keys {
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7))
+		args = append(args, key.shortCode, key.bucket, key.countryCode, key.deviceType,
+			key.trafficSource, key.variant, counts[key])
+	}
+	return fmt.Sprintf(upsertRollupsQuery, table, strings.Join(values, ", ")), args
+}
+
+// upsertTotals adds the inserted clicks to the click totals of their short
+// codes, sorted like upsertRollups.
+func upsertTotals(data []*Clicks, inserted map[string]struct{}) (string, []any) {
+	counts := make(map[string]int64)
+	counted := make(map[string]struct{}, len(inserted))
+	for _, c := range data {
+		if _, ok := inserted[c.Id]; !ok {
+			continue
+		}
+		if _, ok := counted[c.Id]; ok {
+			continue
+		}
+		counted[c.Id] = struct{}{}
+		counts[c.ShortCode]++
+	}
+
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	values := make([]string, 0, len(codes))
+	args := make([]any, 0, len(codes)*2)
+	for _, code := range codes {
+		values = append(values, fmt.Sprintf("($%d, $%d)", len(args)+1, len(args)+2))
+		args = append(args, code, counts[code])
+	}
+	return fmt.Sprintf(upsertTotalsQuery, clickTotalsTable, strings.Join(values, ", ")), args
+}
+
+// clickSource returns a subquery yielding the clicks matching conditions
+// within [from, to) as rollupColumns rows, reading rollups up to granularity
+// for closed buckets and raw clicks for the rest. Placeholders continue after
+// args, which conditions may reference in every segment.
+func (m *customClicksModel) clickSource(conditions []string, args []any, from, to time.Time, granularity int) (string, []any) {
+	segments := planSegments(from, to, time.Now(), granularity)
+	parts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		table, at, clicks := m.table, "clicked_at", "1::bigint"
+		switch s.granularity {
+		case granularityHour:
+			table, at, clicks = clickRollupsHourlyTable, "bucket", "clicks"
+		case granularityDay:
+			table, at, clicks = clickRollupsDailyTable, "bucket", "clicks"
+		}
+
+		where := append([]string(nil), conditions...)
+		if !s.from.IsZero() {
+			args = append(args, s.from)
+			where = append(where, fmt.Sprintf("%s >= $%d", at, len(args)))
+		}
+		if !s.to.IsZero() {
+			args = append(args, s.to)
+			where = append(where, fmt.Sprintf("%s < $%d", at, len(args)))
+		}
+		if len(where) == 0 {
+			where = append(where, "TRUE")
+		}
+		parts = append(parts, fmt.Sprintf("SELECT "+rollupColumns+" FROM %s WHERE %s",
+			at, clicks, table, strings.Join(where, " AND ")))
+	}
+	return "(" + strings.Join(parts, " UNION ALL ") + ") c", args
+}
+
+// planSegments splits [from, to) into raw edges, hourly rollups for whole
+// hours and, at granularityDay, daily rollups for whole UTC days. Buckets from
+// the hour containing now onwards are still filling and always read raw.
+func planSegments(from, to, now time.Time, granularity int) []segment {
+	end := now.UTC().Truncate(time.Hour)
+	if !to.IsZero() && to.Before(end) {
+		end = floorBucket(to, granularityHour)
+	}
+
+	start := from
+	if !from.IsZero() {
+		start = ceilBucket(from, granularityHour)
+	}
+	if granularity == granularityRaw || (!start.IsZero() && !start.Before(end)) {
+		return []segment{{granularityRaw, from, to}}
+	}
+
+	var segments []segment
+	if !from.IsZero() && from.Before(start) {
+		segments = append(segments, segment{granularityRaw, from, start})
+	}
+
+	dayStart, dayEnd := start, floorBucket(end, granularityDay)
+	if !start.IsZero() {
+		dayStart = ceilBucket(start, granularityDay)
+	}
+	if granularity == granularityDay && (dayStart.IsZero() || dayStart.Before(dayEnd)) {
+		if !start.Equal(dayStart) {
+			segments = append(segments, segment{granularityHour, start, dayStart})
+		}
+		segments = append(segments, segment{granularityDay, dayStart, dayEnd})
+		if dayEnd.Before(end) {
+			segments = append(segments, segment{granularityHour, dayEnd, end})
+		}
+	} else {
+		segments = append(segments, segment{granularityHour, start, end})
+	}
+
+	if to.IsZero() || end.Before(to) {
+		segments = append(segments, segment{granularityRaw, end, to})
+	}
+	return segments
+}
+
+// bucketGranularity returns the coarsest rollup whose UTC buckets each fall
+// into a single interval bucket in loc. Hourly rollups need a zone offset of
+// whole hours, checked at both ends of the range and across a year.
+func bucketGranularity(interval string, loc *time.Location, from, to time.Time) int {
+	if interval == IntervalMinute {
+		return granularityRaw
+	}
+	for _, t := range []time.Time{from, to, to.AddDate(0, -6, 0), to.AddDate(-1, 0, 0)} {
+		if t.IsZero() {
+			continue
+		}
+		if _, offset := t.In(loc).Zone(); offset%3600 != 0 {
+			return granularityRaw
+		}
+	}
+	if interval == IntervalHour {
+		return granularityHour
+	}
+	for _, t := range []time.Time{from, to, to.AddDate(0, -6, 0)} {
+		if t.IsZero() {
+			continue
+		}
+		if _, offset := t.In(loc).Zone(); offset != 0 {
+			return granularityHour
+		}
+	}
+	return granularityDay
+}
+
+func floorBucket(t time.Time, granularity int) time.Time {
+	t = t.UTC()
+	if granularity == granularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+func ceilBucket(t time.Time, granularity int) time.Time {
+	f := floorBucket(t, granularity)
+	if f.Equal(t) {
+		return f
+	}
+	if granularity == granularityDay {
+		return f.AddDate(0, 0, 1)
+	}
+	return f.Add(time.Hour)
+}
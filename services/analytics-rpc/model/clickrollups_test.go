@@ -0,0 +1,146 @@
+package model
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSegments(t *testing.T) {
+	now := time.Date(2025, 6, 10, 14, 25, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		from, to    time.Time
+		granularity int
+		want        []segment
+	}{
+		{
+			name:        "raw only",
+			from:        at(1, 0, 0),
+			to:          at(5, 0, 0),
+			granularity: granularityRaw,
+			want:        []segment{{granularityRaw, at(1, 0, 0), at(5, 0, 0)}},
+		},
+		{
+			name:        "within one hour",
+			from:        at(1, 10, 5),
+			to:          at(1, 10, 55),
+			granularity: granularityDay,
+			want:        []segment{{granularityRaw, at(1, 10, 5), at(1, 10, 55)}},
+		},
+		{
+			name:        "unaligned edges",
+			from:        at(1, 22, 30),
+			to:          at(4, 3, 15),
+			granularity: granularityDay,
+			want: []segment{
+				{granularityRaw, at(1, 22, 30), at(1, 23, 0)},
+				{granularityHour, at(1, 23, 0), at(2, 0, 0)},
+				{granularityDay, at(2, 0, 0), at(4, 0, 0)},
+				{granularityHour, at(4, 0, 0), at(4, 3, 0)},
+				{granularityRaw, at(4, 3, 0), at(4, 3, 15)},
+			},
+		},
+		{
+			name:        "aligned to hours",
+			from:        at(1, 10, 0),
+			to:          at(1, 18, 0),
+			granularity: granularityDay,
+			want:        []segment{{granularityHour, at(1, 10, 0), at(1, 18, 0)}},
+		},
+		{
+			name:        "hourly only",
+			from:        at(1, 22, 30),
+			to:          at(4, 3, 15),
+			granularity: granularityHour,
+			want: []segment{
+				{granularityRaw, at(1, 22, 30), at(1, 23, 0)},
+				{granularityHour, at(1, 23, 0), at(4, 3, 0)},
+				{granularityRaw, at(4, 3, 0), at(4, 3, 15)},
+			},
+		},
+		{
+			name:        "open range reads the current hour raw",
+			granularity: granularityDay,
+			want: []segment{
+				{granularityDay, time.Time{}, at(10, 0, 0)},
+				{granularityHour, at(10, 0, 0), at(10, 14, 0)},
+				{granularityRaw, at(10, 14, 0), time.Time{}},
+			},
+		},
+		{
+			name:        "range ending in the future",
+			from:        at(10, 0, 0),
+			to:          at(11, 0, 0),
+			granularity: granularityDay,
+			want: []segment{
+				{granularityHour, at(10, 0, 0), at(10, 14, 0)},
+				{granularityRaw, at(10, 14, 0), at(11, 0, 0)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planSegments(tt.from, tt.to, now, tt.granularity))
+		})
+	}
+}
+
+func TestBucketGranularity(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)
+	load := func(name string) *time.Location {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+		return loc
+	}
+
+	assert.Equal(t, granularityRaw, bucketGranularity(IntervalMinute, time.UTC, from, to))
+	assert.Equal(t, granularityHour, bucketGranularity(IntervalHour, time.UTC, from, to))
+	assert.Equal(t, granularityDay, bucketGranularity(IntervalDay, time.UTC, from, to))
+	assert.Equal(t, granularityDay, bucketGranularity(IntervalWeek, time.UTC, from, to))
+	assert.Equal(t, granularityHour, bucketGranularity(IntervalDay, load("Europe/Berlin"), from, to))
+	assert.Equal(t, granularityRaw, bucketGranularity(IntervalDay, load("Asia/Kolkata"), from, to))
+}
+
+func TestUpsertRollups(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 6, 1, hour, minute, 0, 0, time.UTC)
+	}
+	data := []*Clicks{
+		{Id: "1", ShortCode: "bbbbbbbb", ClickedAt: at(10, 5), CountryCode: "DE"},
+		{Id: "2", ShortCode: "aaaaaaaa", ClickedAt: at(11, 0), CountryCode: "US"},
+		{Id: "3", ShortCode: "bbbbbbbb", ClickedAt: at(10, 55), CountryCode: "DE"},
+		{Id: "4", ShortCode: "bbbbbbbb", ClickedAt: at(10, 30), CountryCode: "DE"},
+		{Id: "5", ShortCode: "aaaaaaaa", ClickedAt: at(12, 0), CountryCode: "US"},
+		// Redelivery of click 5 in the same batch
+		{Id: "5", ShortCode: "aaaaaaaa", ClickedAt: at(12, 0), CountryCode: "US"},
+	}
+	// Click 4 was a duplicate and is not counted
+	inserted := map[string]struct{}{"1": {}, "2": {}, "3": {}, "5": {}}
+
+	query, args := upsertRollups(clickRollupsHourlyTable, granularityHour, data, inserted)
+	assert.Contains(t, query, "VALUES ($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14), ($15, $16, $17, $18, $19, $20, $21) ")
+	require.Len(t, args, 21)
+	assert.Equal(t, []any{"aaaaaaaa", at(11, 0), "US", "", "", "", int64(1)}, args[0:7])
+	assert.Equal(t, []any{"aaaaaaaa", at(12, 0), "US", "", "", "", int64(1)}, args[7:14])
+	assert.Equal(t, []any{"bbbbbbbb", at(10, 0), "DE", "", "", "", int64(2)}, args[14:21])
+
+	query, args = upsertRollups(clickRollupsDailyTable, granularityDay, data, inserted)
+	assert.Contains(t, query, clickRollupsDailyTable)
+	require.Len(t, args, 14)
+	assert.Equal(t, []any{"aaaaaaaa", at(0, 0), "US", "", "", "", int64(2)}, args[0:7])
+	assert.Equal(t, []any{"bbbbbbbb", at(0, 0), "DE", "", "", "", int64(2)}, args[7:14])
+
+	query, args = upsertTotals(data, inserted)
+	assert.Contains(t, query, "VALUES ($1, $2), ($3, $4) ")
+	assert.Equal(t, []any{"aaaaaaaa", int64(2), "bbbbbbbb", int64(2)}, args)
+}
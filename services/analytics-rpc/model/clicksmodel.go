@@ -3,7 +3,10 @@ package model
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
@@ -15,14 +18,83 @@ type (
 	ClicksModel interface {
 		clicksModel
 		withSession(session sqlx.Session) ClicksModel
+		InsertWithRollups(ctx context.Context, data *Clicks) error
+		InsertBatchWithRollups(ctx context.Context, data []*Clicks) ([]string, error)
 		CountByShortCode(ctx context.Context, shortCode string) (int64, error)
+		CountByShortCodes(ctx context.Context, shortCodes []string) ([]*LinkCount, error)
+		CountByVariant(ctx context.Context, shortCode string) ([]*VariantCount, error)
+		CountInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error)
+		CountByDimension(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*DimensionCount, error)
+		CountByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketCount, error)
+		CountUniqueInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error)
+		CountUniqueByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketVisitors, error)
+		VisitorRegisters(ctx context.Context, shortCode string, precision uint8, from, to time.Time) ([]*VisitorRegister, error)
+		VisitorRegistersByBucket(ctx context.Context, shortCode, interval, timezone string, precision uint8, from, to time.Time) ([]*VisitorRegister, error)
+		TopShortCodes(ctx context.Context, from, to time.Time, filters []DimensionFilter, limit, offset int) ([]*LinkCount, error)
+		RankShortCodes(ctx context.Context, ascending bool, after *LinkCount, limit int) ([]*LinkCount, error)
 	}
 
 	customClicksModel struct {
 		*defaultClicksModel
 	}
+
+	// VariantCount is the number of clicks recorded for one A/B destination of a link.
+	VariantCount struct {
+		Variant string `db:"variant"`
+		Clicks  int64  `db:"clicks"`
+	}
+
+	// DimensionCount is the number of clicks sharing one value of a dimension,
+	// e.g. country_code = 'DE'.
+	DimensionCount struct {
+		Value  string `db:"value"`
+		Clicks int64  `db:"clicks"`
+	}
+
+	// BucketCount is the number of clicks within one time bucket starting at Bucket.
+	BucketCount struct {
+		Bucket time.Time `db:"bucket"`
+		Clicks int64     `db:"clicks"`
+	}
+
+	// DimensionFilter restricts counted clicks to one value of a dimension column.
+	DimensionFilter struct {
+		Dimension string
+		Value     string
+	}
+
+	// LinkCount is the number of clicks recorded for one short code.
+	LinkCount struct {
+		ShortCode string `db:"short_code"`
+		Clicks    int64  `db:"clicks"`
+	}
+
+	// BucketVisitors is the number of distinct visitors within one time bucket
+	// starting at Bucket.
+	BucketVisitors struct {
+		Bucket   time.Time `db:"bucket"`
+		Visitors int64     `db:"visitors"`
+	}
+
+	// VisitorRegister is one non-empty HyperLogLog register: the highest rank
+	// among the visitor hashes mapped to Index. Bucket is zero for registers
+	// covering a whole range.
+	VisitorRegister struct {
+		Bucket time.Time `db:"bucket"`
+		Index  int64     `db:"idx"`
+		Rank   int64     `db:"rank"`
+	}
 )
 
+// visitorWhere excludes clicks recorded without a visitor fingerprint.
+const visitorWhere = "visitor_hash <> ''"
+
+// registerColumns derives the HyperLogLog register index from the first 16
+// bits of visitor_hash and the rank from the following 52 bits, matching
+// hll.Sketch.AddHash. %[1]d is the placeholder holding the precision.
+const registerColumns = "(('x' || substr(visitor_hash, 1, 4))::bit(16)::int >> (16 - $%[1]d::int)) AS idx, " +
+	"MAX(53 - length(ltrim(('x' || substr(visitor_hash, 5, 13))::bit(52)::text, '0'))) AS rank"
+
 // NewClicksModel returns a model for the database table.
 func NewClicksModel(conn sqlx.SqlConn) ClicksModel {
 	return &customClicksModel{
@@ -36,11 +108,255 @@ func (m *customClicksModel) withSession(session sqlx.Session) ClicksModel {
 
 // CountByShortCode returns the total number of clicks for a given short code.
 func (m *customClicksModel) CountByShortCode(ctx context.Context, shortCode string) (int64, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE short_code = $1", m.table)
+	return m.CountInRange(ctx, shortCode, time.Time{}, time.Time{})
+}
+
+// CountByShortCodes returns the total number of clicks for each of shortCodes,
+// read from the click totals. Short codes without clicks are left out.
+func (m *customClicksModel) CountByShortCodes(ctx context.Context, shortCodes []string) ([]*LinkCount, error) {
+	query := fmt.Sprintf("SELECT short_code, clicks FROM %s WHERE short_code = ANY($1)", clickTotalsTable)
+	var resp []*LinkCount
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, pq.Array(shortCodes))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CountByVariant returns click counts grouped by variant for a given short code.
+// Clicks recorded without a variant (single-destination links) are excluded.
+func (m *customClicksModel) CountByVariant(ctx context.Context, shortCode string) ([]*VariantCount, error) {
+	source, args := m.clickSource([]string{"short_code = $1", "variant <> ''"}, []any{shortCode}, time.Time{}, time.Time{}, granularityDay)
+	query := fmt.Sprintf(
+		"SELECT variant, SUM(clicks)::bigint AS clicks FROM %s GROUP BY variant ORDER BY variant",
+		source,
+	)
+	var resp []*VariantCount
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CountInRange returns the number of clicks for a short code within [from, to).
+// A zero from or to leaves that side of the range open.
+func (m *customClicksModel) CountInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	source, args := m.clickSource([]string{"short_code = $1"}, []any{shortCode}, from, to, granularityDay)
+	query := fmt.Sprintf("SELECT COALESCE(SUM(clicks), 0)::bigint FROM %s", source)
 	var count int64
-	err := m.conn.QueryRowCtx(ctx, &count, query, shortCode)
+	err := m.conn.QueryRowCtx(ctx, &count, query, args...)
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+
+// CountByDimension returns click counts grouped by one dimension column for a
+// short code within [from, to), most clicked first.
+func (m *customClicksModel) CountByDimension(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*DimensionCount, error) {
+	if !isDimension(dimension) {
+		return nil, fmt.Errorf("unknown dimension %q", dimension)
+	}
+
+	source, args := m.clickSource([]string{"short_code = $1"}, []any{shortCode}, from, to, granularityDay)
+	query := fmt.Sprintf(
+		"SELECT %[1]s AS value, SUM(clicks)::bigint AS clicks FROM %[2]s GROUP BY %[1]s ORDER BY clicks DESC, %[1]s",
+		dimension, source,
+	)
+	var resp []*DimensionCount
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CountByBucket returns click counts for a short code within [from, to), grouped
+// by date_trunc(interval) in the given IANA timezone. Only non-empty buckets
+// are returned, ordered by start.
+func (m *customClicksModel) CountByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketCount, error) {
+	if !isInterval(interval) {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	source, args := m.clickSource([]string{"short_code = $1"}, []any{shortCode}, from, to,
+		bucketGranularity(interval, loc, from, to))
+	args = append(args, interval, timezone)
+	query := fmt.Sprintf(
+		"SELECT date_trunc($%[1]d, at, $%[2]d) AS bucket, SUM(clicks)::bigint AS clicks FROM %[3]s GROUP BY bucket ORDER BY bucket",
+		len(args)-1, len(args), source,
+	)
+	var resp []*BucketCount
+	err = m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CountUniqueInRange returns the exact number of distinct visitors for a short
+// code within [from, to). Visitors are only known from raw clicks, so clicks
+// past partition retention are not counted.
+func (m *customClicksModel) CountUniqueInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	where, args := rangeWhere(shortCode, from, to)
+	query := fmt.Sprintf("SELECT COUNT(DISTINCT visitor_hash) FROM %s WHERE %s AND %s", m.table, where, visitorWhere)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountUniqueByBucket returns the exact number of distinct visitors per
+// date_trunc(interval) bucket, like CountByBucket.
+func (m *customClicksModel) CountUniqueByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketVisitors, error) {
+	if !isInterval(interval) {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	where, args := rangeWhere(shortCode, from, to)
+	args = append(args, interval, timezone)
+	query := fmt.Sprintf(
+		"SELECT date_trunc($%[1]d, clicked_at, $%[2]d) AS bucket, COUNT(DISTINCT visitor_hash) AS visitors "+
+			"FROM %[3]s WHERE %[4]s AND %[5]s GROUP BY bucket ORDER BY bucket",
+		len(args)-1, len(args), m.table, where, visitorWhere,
+	)
+	var resp []*BucketVisitors
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VisitorRegisters returns the non-empty HyperLogLog registers over the
+// visitors of a short code within [from, to).
+func (m *customClicksModel) VisitorRegisters(ctx context.Context, shortCode string, precision uint8, from, to time.Time) ([]*VisitorRegister, error) {
+	where, args := rangeWhere(shortCode, from, to)
+	args = append(args, precision)
+	query := fmt.Sprintf(
+		"SELECT "+registerColumns+" FROM %[2]s WHERE %[3]s AND %[4]s GROUP BY idx",
+		len(args), m.table, where, visitorWhere,
+	)
+	var resp []*VisitorRegister
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VisitorRegistersByBucket returns the non-empty HyperLogLog registers of
+// every date_trunc(interval) bucket, like CountByBucket.
+func (m *customClicksModel) VisitorRegistersByBucket(ctx context.Context, shortCode, interval, timezone string, precision uint8, from, to time.Time) ([]*VisitorRegister, error) {
+	if !isInterval(interval) {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	where, args := rangeWhere(shortCode, from, to)
+	args = append(args, precision, interval, timezone)
+	query := fmt.Sprintf(
+		"SELECT date_trunc($%[5]d, clicked_at, $%[6]d) AS bucket, "+registerColumns+
+			" FROM %[2]s WHERE %[3]s AND %[4]s GROUP BY bucket, idx",
+		len(args)-2, m.table, where, visitorWhere, len(args)-1, len(args),
+	)
+	var resp []*VisitorRegister
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// TopShortCodes returns the limit most clicked short codes within [from, to)
+// among clicks matching every filter, most clicked first, skipping the first
+// offset.
+func (m *customClicksModel) TopShortCodes(ctx context.Context, from, to time.Time, filters []DimensionFilter, limit, offset int) ([]*LinkCount, error) {
+	conditions := make([]string, 0, len(filters))
+	args := make([]any, 0, len(filters)+3)
+	for _, f := range filters {
+		if !isDimension(f.Dimension) {
+			return nil, fmt.Errorf("unknown dimension %q", f.Dimension)
+		}
+		args = append(args, f.Value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", f.Dimension, len(args)))
+	}
+
+	source, args := m.clickSource(conditions, args, from, to, granularityDay)
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(
+		"SELECT short_code, SUM(clicks)::bigint AS clicks FROM %s GROUP BY short_code ORDER BY clicks DESC, short_code LIMIT $%d OFFSET $%d",
+		source, len(args)-1, len(args),
+	)
+	var resp []*LinkCount
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RankShortCodes returns limit short codes with clicks ranked by their total
+// clicks, most clicked first unless ascending, with ties ordered by short code
+// in the same direction. It continues after the given position in the
+// ranking, or starts from the top when after is nil.
+func (m *customClicksModel) RankShortCodes(ctx context.Context, ascending bool, after *LinkCount, limit int) ([]*LinkCount, error) {
+	direction, cmp := "DESC", "<"
+	if ascending {
+		direction, cmp = "ASC", ">"
+	}
+	var where string
+	var args []any
+	if after != nil {
+		args = append(args, after.Clicks, after.ShortCode)
+		where = fmt.Sprintf(" WHERE (clicks, short_code) %s ($1, $2)", cmp)
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT short_code, clicks FROM %s%s ORDER BY clicks %s, short_code %s LIMIT $%d",
+		clickTotalsTable, where, direction, direction, len(args))
+	var resp []*LinkCount
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// rangeWhere builds the WHERE clause selecting a short code's clicks within
+// [from, to), skipping zero bounds.
+func rangeWhere(shortCode string, from, to time.Time) (string, []any) {
+	conditions := []string{"short_code = $1"}
+	args := []any{shortCode}
+	if !from.IsZero() {
+		args = append(args, from)
+		conditions = append(conditions, fmt.Sprintf("clicked_at >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conditions = append(conditions, fmt.Sprintf("clicked_at < $%d", len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// isDimension whitelists dimension column names to prevent SQL injection.
+func isDimension(dimension string) bool {
+	switch dimension {
+	case DimensionCountry, DimensionDevice, DimensionTrafficSource:
+		return true
+	}
+	return false
+}
+
+func isInterval(interval string) bool {
+	switch interval {
+	case IntervalMinute, IntervalHour, IntervalDay, IntervalWeek:
+		return true
+	}
+	return false
+}
@@ -43,6 +43,8 @@ type (
 		CountryCode   string    `db:"country_code"`
 		DeviceType    string    `db:"device_type"`
 		TrafficSource string    `db:"traffic_source"`
+		Variant       string    `db:"variant"`
+		VisitorHash   string    `db:"visitor_hash"`
 	}
 )
 
@@ -74,14 +76,14 @@ func (m *defaultClicksModel) FindOne(ctx context.Context, id string) (*Clicks, e
 }
 
 func (m *defaultClicksModel) Insert(ctx context.Context, data *Clicks) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values ($1, $2, $3, $4, $5, $6)", m.table, clicksRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.ClickedAt, data.CountryCode, data.DeviceType, data.TrafficSource)
+	query := fmt.Sprintf("insert into %s (%s) values ($1, $2, $3, $4, $5, $6, $7, $8)", m.table, clicksRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.ClickedAt, data.CountryCode, data.DeviceType, data.TrafficSource, data.Variant, data.VisitorHash)
 	return ret, err
 }
 
 func (m *defaultClicksModel) Update(ctx context.Context, data *Clicks) error {
 	query := fmt.Sprintf("update %s set %s where id = $1", m.table, clicksRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.ClickedAt, data.CountryCode, data.DeviceType, data.TrafficSource)
+	_, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.ClickedAt, data.CountryCode, data.DeviceType, data.TrafficSource, data.Variant, data.VisitorHash)
 	return err
 }
 
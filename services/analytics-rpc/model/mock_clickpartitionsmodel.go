@@ -0,0 +1,53 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// MockClickPartitionsModel is a test mock for ClickPartitionsModel interface.
+type MockClickPartitionsModel struct {
+	CreateFunc        func(ctx context.Context, month time.Time) error
+	ListFunc          func(ctx context.Context) ([]string, error)
+	DropFunc          func(ctx context.Context, name string) error
+	DetachFunc        func(ctx context.Context, name string) error
+	ExpireDefaultFunc func(ctx context.Context, before time.Time, archive bool) (int64, error)
+}
+
+// Ensure MockClickPartitionsModel implements ClickPartitionsModel interface
+var _ ClickPartitionsModel = (*MockClickPartitionsModel)(nil)
+
+func (m *MockClickPartitionsModel) Create(ctx context.Context, month time.Time) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, month)
+	}
+	panic("MockClickPartitionsModel.CreateFunc not set")
+}
+
+func (m *MockClickPartitionsModel) List(ctx context.Context) ([]string, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	panic("MockClickPartitionsModel.ListFunc not set")
+}
+
+func (m *MockClickPartitionsModel) Drop(ctx context.Context, name string) error {
+	if m.DropFunc != nil {
+		return m.DropFunc(ctx, name)
+	}
+	panic("MockClickPartitionsModel.DropFunc not set")
+}
+
+func (m *MockClickPartitionsModel) Detach(ctx context.Context, name string) error {
+	if m.DetachFunc != nil {
+		return m.DetachFunc(ctx, name)
+	}
+	panic("MockClickPartitionsModel.DetachFunc not set")
+}
+
+func (m *MockClickPartitionsModel) ExpireDefault(ctx context.Context, before time.Time, archive bool) (int64, error) {
+	if m.ExpireDefaultFunc != nil {
+		return m.ExpireDefaultFunc(ctx, before, archive)
+	}
+	panic("MockClickPartitionsModel.ExpireDefaultFunc not set")
+}
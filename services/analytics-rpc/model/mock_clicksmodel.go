@@ -3,18 +3,32 @@ package model
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // MockClicksModel is a test mock for ClicksModel interface.
 type MockClicksModel struct {
-	InsertFunc           func(ctx context.Context, data *Clicks) (sql.Result, error)
-	FindOneFunc          func(ctx context.Context, id string) (*Clicks, error)
-	UpdateFunc           func(ctx context.Context, data *Clicks) error
-	DeleteFunc           func(ctx context.Context, id string) error
-	CountByShortCodeFunc func(ctx context.Context, shortCode string) (int64, error)
-	WithSessionFunc      func(session sqlx.Session) ClicksModel
+	InsertFunc                   func(ctx context.Context, data *Clicks) (sql.Result, error)
+	FindOneFunc                  func(ctx context.Context, id string) (*Clicks, error)
+	UpdateFunc                   func(ctx context.Context, data *Clicks) error
+	InsertWithRollupsFunc        func(ctx context.Context, data *Clicks) error
+	InsertBatchWithRollupsFunc   func(ctx context.Context, data []*Clicks) ([]string, error)
+	DeleteFunc                   func(ctx context.Context, id string) error
+	CountByShortCodeFunc         func(ctx context.Context, shortCode string) (int64, error)
+	CountByShortCodesFunc        func(ctx context.Context, shortCodes []string) ([]*LinkCount, error)
+	CountByVariantFunc           func(ctx context.Context, shortCode string) ([]*VariantCount, error)
+	CountInRangeFunc             func(ctx context.Context, shortCode string, from, to time.Time) (int64, error)
+	CountByDimensionFunc         func(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*DimensionCount, error)
+	CountByBucketFunc            func(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketCount, error)
+	CountUniqueInRangeFunc       func(ctx context.Context, shortCode string, from, to time.Time) (int64, error)
+	CountUniqueByBucketFunc      func(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketVisitors, error)
+	VisitorRegistersFunc         func(ctx context.Context, shortCode string, precision uint8, from, to time.Time) ([]*VisitorRegister, error)
+	VisitorRegistersByBucketFunc func(ctx context.Context, shortCode, interval, timezone string, precision uint8, from, to time.Time) ([]*VisitorRegister, error)
+	TopShortCodesFunc            func(ctx context.Context, from, to time.Time, filters []DimensionFilter, limit, offset int) ([]*LinkCount, error)
+	RankShortCodesFunc           func(ctx context.Context, ascending bool, after *LinkCount, limit int) ([]*LinkCount, error)
+	WithSessionFunc              func(session sqlx.Session) ClicksModel
 }
 
 // Ensure MockClicksModel implements ClicksModel interface
@@ -48,6 +62,20 @@ func (m *MockClicksModel) Delete(ctx context.Context, id string) error {
 	panic("MockClicksModel.DeleteFunc not set")
 }
 
+func (m *MockClicksModel) InsertWithRollups(ctx context.Context, data *Clicks) error {
+	if m.InsertWithRollupsFunc != nil {
+		return m.InsertWithRollupsFunc(ctx, data)
+	}
+	panic("MockClicksModel.InsertWithRollupsFunc not set")
+}
+
+func (m *MockClicksModel) InsertBatchWithRollups(ctx context.Context, data []*Clicks) ([]string, error) {
+	if m.InsertBatchWithRollupsFunc != nil {
+		return m.InsertBatchWithRollupsFunc(ctx, data)
+	}
+	panic("MockClicksModel.InsertBatchWithRollupsFunc not set")
+}
+
 func (m *MockClicksModel) CountByShortCode(ctx context.Context, shortCode string) (int64, error) {
 	if m.CountByShortCodeFunc != nil {
 		return m.CountByShortCodeFunc(ctx, shortCode)
@@ -55,6 +83,83 @@ func (m *MockClicksModel) CountByShortCode(ctx context.Context, shortCode string
 	panic("MockClicksModel.CountByShortCodeFunc not set")
 }
 
+func (m *MockClicksModel) CountByShortCodes(ctx context.Context, shortCodes []string) ([]*LinkCount, error) {
+	if m.CountByShortCodesFunc != nil {
+		return m.CountByShortCodesFunc(ctx, shortCodes)
+	}
+	panic("MockClicksModel.CountByShortCodesFunc not set")
+}
+
+func (m *MockClicksModel) CountByVariant(ctx context.Context, shortCode string) ([]*VariantCount, error) {
+	if m.CountByVariantFunc != nil {
+		return m.CountByVariantFunc(ctx, shortCode)
+	}
+	panic("MockClicksModel.CountByVariantFunc not set")
+}
+
+func (m *MockClicksModel) CountInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	if m.CountInRangeFunc != nil {
+		return m.CountInRangeFunc(ctx, shortCode, from, to)
+	}
+	panic("MockClicksModel.CountInRangeFunc not set")
+}
+
+func (m *MockClicksModel) CountByDimension(ctx context.Context, shortCode, dimension string, from, to time.Time) ([]*DimensionCount, error) {
+	if m.CountByDimensionFunc != nil {
+		return m.CountByDimensionFunc(ctx, shortCode, dimension, from, to)
+	}
+	panic("MockClicksModel.CountByDimensionFunc not set")
+}
+
+func (m *MockClicksModel) CountByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketCount, error) {
+	if m.CountByBucketFunc != nil {
+		return m.CountByBucketFunc(ctx, shortCode, interval, timezone, from, to)
+	}
+	panic("MockClicksModel.CountByBucketFunc not set")
+}
+
+func (m *MockClicksModel) CountUniqueInRange(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	if m.CountUniqueInRangeFunc != nil {
+		return m.CountUniqueInRangeFunc(ctx, shortCode, from, to)
+	}
+	panic("MockClicksModel.CountUniqueInRangeFunc not set")
+}
+
+func (m *MockClicksModel) CountUniqueByBucket(ctx context.Context, shortCode, interval, timezone string, from, to time.Time) ([]*BucketVisitors, error) {
+	if m.CountUniqueByBucketFunc != nil {
+		return m.CountUniqueByBucketFunc(ctx, shortCode, interval, timezone, from, to)
+	}
+	panic("MockClicksModel.CountUniqueByBucketFunc not set")
+}
+
+func (m *MockClicksModel) VisitorRegisters(ctx context.Context, shortCode string, precision uint8, from, to time.Time) ([]*VisitorRegister, error) {
+	if m.VisitorRegistersFunc != nil {
+		return m.VisitorRegistersFunc(ctx, shortCode, precision, from, to)
+	}
+	panic("MockClicksModel.VisitorRegistersFunc not set")
+}
+
+func (m *MockClicksModel) VisitorRegistersByBucket(ctx context.Context, shortCode, interval, timezone string, precision uint8, from, to time.Time) ([]*VisitorRegister, error) {
+	if m.VisitorRegistersByBucketFunc != nil {
+		return m.VisitorRegistersByBucketFunc(ctx, shortCode, interval, timezone, precision, from, to)
+	}
+	panic("MockClicksModel.VisitorRegistersByBucketFunc not set")
+}
+
+func (m *MockClicksModel) TopShortCodes(ctx context.Context, from, to time.Time, filters []DimensionFilter, limit, offset int) ([]*LinkCount, error) {
+	if m.TopShortCodesFunc != nil {
+		return m.TopShortCodesFunc(ctx, from, to, filters, limit, offset)
+	}
+	panic("MockClicksModel.TopShortCodesFunc not set")
+}
+
+func (m *MockClicksModel) RankShortCodes(ctx context.Context, ascending bool, after *LinkCount, limit int) ([]*LinkCount, error) {
+	if m.RankShortCodesFunc != nil {
+		return m.RankShortCodesFunc(ctx, ascending, after, limit)
+	}
+	panic("MockClicksModel.RankShortCodesFunc not set")
+}
+
 func (m *MockClicksModel) withSession(session sqlx.Session) ClicksModel {
 	if m.WithSessionFunc != nil {
 		return m.WithSessionFunc(session)
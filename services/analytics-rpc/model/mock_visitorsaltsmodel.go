@@ -0,0 +1,72 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// MockVisitorSaltsModel is a test mock for VisitorSaltsModel interface.
+type MockVisitorSaltsModel struct {
+	InsertFunc       func(ctx context.Context, data *VisitorSalts) (sql.Result, error)
+	FindOneFunc      func(ctx context.Context, day time.Time) (*VisitorSalts, error)
+	UpdateFunc       func(ctx context.Context, data *VisitorSalts) error
+	DeleteFunc       func(ctx context.Context, day time.Time) error
+	FindOrCreateFunc func(ctx context.Context, day time.Time, salt []byte) ([]byte, error)
+	DeleteBeforeFunc func(ctx context.Context, day time.Time) error
+	WithSessionFunc  func(session sqlx.Session) VisitorSaltsModel
+}
+
+// Ensure MockVisitorSaltsModel implements VisitorSaltsModel interface
+var _ VisitorSaltsModel = (*MockVisitorSaltsModel)(nil)
+
+func (m *MockVisitorSaltsModel) Insert(ctx context.Context, data *VisitorSalts) (sql.Result, error) {
+	if m.InsertFunc != nil {
+		return m.InsertFunc(ctx, data)
+	}
+	panic("MockVisitorSaltsModel.InsertFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) FindOne(ctx context.Context, day time.Time) (*VisitorSalts, error) {
+	if m.FindOneFunc != nil {
+		return m.FindOneFunc(ctx, day)
+	}
+	panic("MockVisitorSaltsModel.FindOneFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) Update(ctx context.Context, data *VisitorSalts) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, data)
+	}
+	panic("MockVisitorSaltsModel.UpdateFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) Delete(ctx context.Context, day time.Time) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, day)
+	}
+	panic("MockVisitorSaltsModel.DeleteFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) FindOrCreate(ctx context.Context, day time.Time, salt []byte) ([]byte, error) {
+	if m.FindOrCreateFunc != nil {
+		return m.FindOrCreateFunc(ctx, day, salt)
+	}
+	panic("MockVisitorSaltsModel.FindOrCreateFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) DeleteBefore(ctx context.Context, day time.Time) error {
+	if m.DeleteBeforeFunc != nil {
+		return m.DeleteBeforeFunc(ctx, day)
+	}
+	panic("MockVisitorSaltsModel.DeleteBeforeFunc not set")
+}
+
+func (m *MockVisitorSaltsModel) withSession(session sqlx.Session) VisitorSaltsModel {
+	if m.WithSessionFunc != nil {
+		return m.WithSessionFunc(session)
+	}
+	panic("MockVisitorSaltsModel.WithSessionFunc not set")
+}
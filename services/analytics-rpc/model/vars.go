@@ -3,3 +3,19 @@ package model
 import "github.com/zeromicro/go-zero/core/stores/sqlx"
 
 var ErrNotFound = sqlx.ErrNotFound
+
+// Click dimensions enriched by analytics-consumer. They double as column names
+// and are the only values accepted for grouping and filtering.
+const (
+	DimensionCountry       = "country_code"
+	DimensionDevice        = "device_type"
+	DimensionTrafficSource = "traffic_source"
+)
+
+// Time-series bucket widths, named after the Postgres date_trunc fields.
+const (
+	IntervalMinute = "minute"
+	IntervalHour   = "hour"
+	IntervalDay    = "day"
+	IntervalWeek   = "week"
+)
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ VisitorSaltsModel = (*customVisitorSaltsModel)(nil)
+
+type (
+	// VisitorSaltsModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customVisitorSaltsModel.
+	VisitorSaltsModel interface {
+		visitorSaltsModel
+		withSession(session sqlx.Session) VisitorSaltsModel
+		FindOrCreate(ctx context.Context, day time.Time, salt []byte) ([]byte, error)
+		DeleteBefore(ctx context.Context, day time.Time) error
+	}
+
+	customVisitorSaltsModel struct {
+		*defaultVisitorSaltsModel
+	}
+)
+
+// NewVisitorSaltsModel returns a model for the database table.
+func NewVisitorSaltsModel(conn sqlx.SqlConn) VisitorSaltsModel {
+	return &customVisitorSaltsModel{
+		defaultVisitorSaltsModel: newVisitorSaltsModel(conn),
+	}
+}
+
+func (m *customVisitorSaltsModel) withSession(session sqlx.Session) VisitorSaltsModel {
+	return NewVisitorSaltsModel(sqlx.NewSqlConnFromSession(session))
+}
+
+// FindOrCreate returns the salt stored for day, storing salt first if the day
+// has none yet. Concurrent consumers racing on a new day all get the winner's salt.
+func (m *customVisitorSaltsModel) FindOrCreate(ctx context.Context, day time.Time, salt []byte) ([]byte, error) {
+	query := fmt.Sprintf(
+		"WITH ins AS (INSERT INTO %[1]s (day, salt) VALUES ($1, $2) ON CONFLICT (day) DO NOTHING RETURNING salt) "+
+			"SELECT salt FROM ins UNION ALL SELECT salt FROM %[1]s WHERE day = $1 LIMIT 1",
+		m.table,
+	)
+	var stored []byte
+	err := m.conn.QueryRowCtx(ctx, &stored, query, day, salt)
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// DeleteBefore removes the salts of all days before day.
+func (m *customVisitorSaltsModel) DeleteBefore(ctx context.Context, day time.Time) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE day < $1", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, day)
+	return err
+}
@@ -0,0 +1,7 @@
+ALTER TABLE clicks
+  DROP COLUMN IF EXISTS variant;
+
+DROP TABLE IF EXISTS url_variants;
+
+ALTER TABLE urls
+  DROP COLUMN IF EXISTS rotation;
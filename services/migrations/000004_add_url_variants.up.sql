@@ -0,0 +1,15 @@
+ALTER TABLE urls
+  ADD COLUMN rotation VARCHAR(10) NOT NULL DEFAULT '';
+
+CREATE TABLE url_variants (
+  id              UUID PRIMARY KEY,
+  short_code      VARCHAR(8) NOT NULL REFERENCES urls (short_code) ON DELETE CASCADE,
+  label           VARCHAR(32) NOT NULL,
+  destination_url TEXT NOT NULL,
+  weight          INTEGER NOT NULL CHECK (weight > 0),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (short_code, label)
+);
+
+ALTER TABLE clicks
+  ADD COLUMN variant VARCHAR(32) NOT NULL DEFAULT '';
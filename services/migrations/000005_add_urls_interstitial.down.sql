@@ -0,0 +1,3 @@
+ALTER TABLE urls
+  DROP COLUMN IF EXISTS title,
+  DROP COLUMN IF EXISTS interstitial;
@@ -0,0 +1,3 @@
+ALTER TABLE urls
+  ADD COLUMN title        VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN interstitial BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+ALTER TABLE urls
+  ADD COLUMN og_title       VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN og_description VARCHAR(1000) NOT NULL DEFAULT '',
+  ADD COLUMN og_image       TEXT NOT NULL DEFAULT '';
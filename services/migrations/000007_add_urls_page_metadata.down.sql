@@ -0,0 +1,5 @@
+ALTER TABLE urls
+  DROP COLUMN IF EXISTS page_title,
+  DROP COLUMN IF EXISTS page_description,
+  DROP COLUMN IF EXISTS favicon_url,
+  DROP COLUMN IF EXISTS metadata_fetched_at;
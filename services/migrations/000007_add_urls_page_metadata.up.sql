@@ -0,0 +1,5 @@
+ALTER TABLE urls
+  ADD COLUMN page_title          VARCHAR(255) NOT NULL DEFAULT '',
+  ADD COLUMN page_description    VARCHAR(1000) NOT NULL DEFAULT '',
+  ADD COLUMN favicon_url         TEXT NOT NULL DEFAULT '',
+  ADD COLUMN metadata_fetched_at TIMESTAMPTZ;
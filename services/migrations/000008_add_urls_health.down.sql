@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_urls_health_next_check_at;
+
+ALTER TABLE urls
+  DROP COLUMN IF EXISTS health_status_code,
+  DROP COLUMN IF EXISTS health_latency_ms,
+  DROP COLUMN IF EXISTS health_error,
+  DROP COLUMN IF EXISTS health_failures,
+  DROP COLUMN IF EXISTS health_checked_at,
+  DROP COLUMN IF EXISTS health_next_check_at;
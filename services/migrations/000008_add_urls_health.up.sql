@@ -0,0 +1,10 @@
+ALTER TABLE urls
+  ADD COLUMN health_status_code   INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN health_latency_ms    INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN health_error         TEXT NOT NULL DEFAULT '',
+  ADD COLUMN health_failures      INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN health_checked_at    TIMESTAMPTZ,
+  ADD COLUMN health_next_check_at TIMESTAMPTZ;
+
+-- Scheduler scan: links never checked (NULL) sort first
+CREATE INDEX idx_urls_health_next_check_at ON urls (health_next_check_at NULLS FIRST);
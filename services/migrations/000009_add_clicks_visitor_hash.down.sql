@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS visitor_salts;
+
+DROP INDEX IF EXISTS idx_clicks_short_code_visitor_hash;
+
+ALTER TABLE clicks
+  DROP COLUMN IF EXISTS visitor_hash;
@@ -0,0 +1,12 @@
+ALTER TABLE clicks
+  ADD COLUMN visitor_hash VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_clicks_short_code_visitor_hash ON clicks (short_code, visitor_hash);
+
+-- One random salt per UTC day for visitor fingerprints. Salts are deleted a day
+-- after they expire, so fingerprints cannot be linked across days or reversed.
+CREATE TABLE visitor_salts (
+  day        DATE PRIMARY KEY,
+  salt       BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
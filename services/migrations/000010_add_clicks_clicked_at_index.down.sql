@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_clicks_clicked_at_short_code;
@@ -0,0 +1,3 @@
+-- Serves range scans across all links, e.g. the top links leaderboard.
+-- Including short_code lets the GROUP BY run as an index-only scan.
+CREATE INDEX idx_clicks_clicked_at_short_code ON clicks (clicked_at, short_code);
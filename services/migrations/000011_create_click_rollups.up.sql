@@ -0,0 +1,29 @@
+-- Click counts pre-aggregated per UTC hour and day and per combination of
+-- dimensions. analytics-consumer upserts both in the transaction inserting the
+-- raw click; analytics-rpc reads them for closed buckets and falls back to
+-- clicks for partial buckets at the range edges and the current hour.
+CREATE TABLE click_rollups_hourly (
+  short_code     VARCHAR(8) NOT NULL,
+  bucket         TIMESTAMPTZ NOT NULL,
+  country_code   VARCHAR(2) NOT NULL,
+  device_type    VARCHAR(10) NOT NULL,
+  traffic_source VARCHAR(10) NOT NULL,
+  variant        VARCHAR(32) NOT NULL,
+  clicks         BIGINT NOT NULL,
+  PRIMARY KEY (short_code, bucket, country_code, device_type, traffic_source, variant)
+);
+
+CREATE INDEX idx_click_rollups_hourly_bucket ON click_rollups_hourly (bucket);
+
+CREATE TABLE click_rollups_daily (LIKE click_rollups_hourly INCLUDING ALL);
+
+-- Backfill from clicks recorded before the rollups existed
+INSERT INTO click_rollups_hourly
+SELECT short_code, date_trunc('hour', clicked_at, 'UTC'), country_code, device_type, traffic_source, variant, COUNT(*)
+FROM clicks
+GROUP BY 1, 2, 3, 4, 5, 6;
+
+INSERT INTO click_rollups_daily
+SELECT short_code, date_trunc('day', bucket, 'UTC'), country_code, device_type, traffic_source, variant, SUM(clicks)
+FROM click_rollups_hourly
+GROUP BY 1, 2, 3, 4, 5, 6;
@@ -0,0 +1,26 @@
+ALTER TABLE IF EXISTS clicks RENAME TO clicks_partitioned;
+ALTER INDEX IF EXISTS idx_clicks_short_code RENAME TO idx_clicks_partitioned_short_code;
+ALTER INDEX IF EXISTS idx_clicks_short_code_visitor_hash RENAME TO idx_clicks_partitioned_short_code_visitor_hash;
+ALTER INDEX IF EXISTS idx_clicks_clicked_at_short_code RENAME TO idx_clicks_partitioned_clicked_at_short_code;
+
+CREATE TABLE clicks (
+  id             UUID PRIMARY KEY,
+  short_code     VARCHAR(8) NOT NULL,
+  clicked_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  country_code   VARCHAR(2) NOT NULL DEFAULT 'XX',
+  device_type    VARCHAR(10) NOT NULL DEFAULT 'Unknown',
+  traffic_source VARCHAR(10) NOT NULL DEFAULT 'Unknown',
+  variant        VARCHAR(32) NOT NULL DEFAULT '',
+  visitor_hash   VARCHAR(32) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_clicks_short_code ON clicks (short_code);
+CREATE INDEX idx_clicks_short_code_visitor_hash ON clicks (short_code, visitor_hash);
+CREATE INDEX idx_clicks_clicked_at_short_code ON clicks (clicked_at, short_code);
+
+INSERT INTO clicks (id, short_code, clicked_at, country_code, device_type, traffic_source, variant, visitor_hash)
+SELECT id, short_code, clicked_at, country_code, device_type, traffic_source, variant, visitor_hash
+FROM clicks_partitioned
+ON CONFLICT (id) DO NOTHING;
+
+DROP TABLE IF EXISTS clicks_partitioned;
@@ -0,0 +1,58 @@
+-- Range-partition clicks by month (UTC) so old months can be dropped cheaply.
+-- The primary key must include the partition key. analytics-consumer creates
+-- upcoming partitions and enforces retention; the default partition only
+-- catches clicks outside every monthly partition.
+ALTER TABLE clicks RENAME TO clicks_unpartitioned;
+ALTER INDEX clicks_pkey RENAME TO clicks_unpartitioned_pkey;
+ALTER INDEX idx_clicks_short_code RENAME TO idx_clicks_unpartitioned_short_code;
+ALTER INDEX idx_clicks_short_code_visitor_hash RENAME TO idx_clicks_unpartitioned_short_code_visitor_hash;
+ALTER INDEX idx_clicks_clicked_at_short_code RENAME TO idx_clicks_unpartitioned_clicked_at_short_code;
+
+CREATE TABLE clicks (
+  id             UUID NOT NULL,
+  short_code     VARCHAR(8) NOT NULL,
+  clicked_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  country_code   VARCHAR(2) NOT NULL DEFAULT 'XX',
+  device_type    VARCHAR(10) NOT NULL DEFAULT 'Unknown',
+  traffic_source VARCHAR(10) NOT NULL DEFAULT 'Unknown',
+  variant        VARCHAR(32) NOT NULL DEFAULT '',
+  visitor_hash   VARCHAR(32) NOT NULL DEFAULT '',
+  PRIMARY KEY (id, clicked_at)
+) PARTITION BY RANGE (clicked_at);
+
+CREATE INDEX idx_clicks_short_code ON clicks (short_code);
+CREATE INDEX idx_clicks_short_code_visitor_hash ON clicks (short_code, visitor_hash);
+CREATE INDEX idx_clicks_clicked_at_short_code ON clicks (clicked_at, short_code);
+
+CREATE TABLE clicks_default PARTITION OF clicks DEFAULT;
+
+-- One partition per month from the oldest click through three months ahead.
+-- Month arithmetic on timestamptz follows the session time zone.
+SET TIME ZONE 'UTC';
+
+DO $$
+DECLARE
+  part_start TIMESTAMPTZ;
+BEGIN
+  FOR part_start IN
+    SELECT generate_series(
+      date_trunc('month', COALESCE((SELECT MIN(clicked_at) FROM clicks_unpartitioned), NOW()), 'UTC'),
+      date_trunc('month', NOW(), 'UTC') + INTERVAL '3 months',
+      INTERVAL '1 month'
+    )
+  LOOP
+    EXECUTE format(
+      'CREATE TABLE %I PARTITION OF clicks FOR VALUES FROM (%L) TO (%L)',
+      'clicks_' || to_char(part_start AT TIME ZONE 'UTC', 'YYYY_MM'),
+      part_start,
+      part_start + INTERVAL '1 month'
+    );
+  END LOOP;
+END
+$$;
+
+INSERT INTO clicks (id, short_code, clicked_at, country_code, device_type, traffic_source, variant, visitor_hash)
+SELECT id, short_code, clicked_at, country_code, device_type, traffic_source, variant, visitor_hash
+FROM clicks_unpartitioned;
+
+DROP TABLE clicks_unpartitioned;
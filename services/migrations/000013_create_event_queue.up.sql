@@ -0,0 +1,13 @@
+-- Click events waiting for analytics-consumer when the event bus runs on
+-- Postgres instead of Kafka. Consumers claim rows with FOR UPDATE SKIP LOCKED
+-- and delete them once handled.
+CREATE TABLE event_queue (
+  id         BIGSERIAL PRIMARY KEY,
+  topic      TEXT NOT NULL,
+  key        TEXT NOT NULL,
+  value      BYTEA NOT NULL,
+  headers    JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_queue_topic_id ON event_queue (topic, id);
@@ -0,0 +1,3 @@
+-- Serves keyset pagination of the links list in creation order, the default
+-- sort. The id breaks ties between links created in the same microsecond.
+CREATE INDEX idx_urls_created_at_id ON urls (created_at, id);
@@ -0,0 +1,3 @@
+ALTER TABLE event_queue
+  DROP COLUMN IF EXISTS visible_at,
+  DROP COLUMN IF EXISTS attempts;
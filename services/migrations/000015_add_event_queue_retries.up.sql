@@ -0,0 +1,5 @@
+-- Events whose handler failed stay queued and are retried once visible_at
+-- passes, backing off with each attempt.
+ALTER TABLE event_queue
+  ADD COLUMN attempts   INT NOT NULL DEFAULT 0,
+  ADD COLUMN visible_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
@@ -0,0 +1,14 @@
+-- All-time click count per short code. analytics-consumer upserts it in the
+-- transaction inserting the raw click, so links can be ranked by clicks with
+-- an index walk instead of aggregating the rollups on every page.
+CREATE TABLE click_totals (
+  short_code VARCHAR(8) PRIMARY KEY,
+  clicks     BIGINT NOT NULL
+);
+
+CREATE INDEX idx_click_totals_clicks ON click_totals (clicks, short_code);
+
+INSERT INTO click_totals
+SELECT short_code, SUM(clicks)
+FROM click_rollups_daily
+GROUP BY short_code;
@@ -0,0 +1,15 @@
+-- Events of the postgres event bus whose handler failed MaxAttempts times.
+-- They are moved here instead of being retried forever, keeping their queue
+-- id, and can be requeued by inserting them into event_queue again.
+CREATE TABLE event_dead_letters (
+  id         BIGINT PRIMARY KEY,
+  topic      TEXT NOT NULL,
+  key        TEXT NOT NULL,
+  value      BYTEA NOT NULL,
+  headers    JSONB NOT NULL DEFAULT '{}',
+  attempts   INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  failed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_dead_letters_topic_id ON event_dead_letters (topic, id);
@@ -0,0 +1,190 @@
+package clickcount
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/breaker"
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// StatusLive marks a count just read from analytics-rpc.
+	StatusLive = "live"
+	// StatusStale marks the last known count, served while analytics-rpc fails.
+	StatusStale = "stale"
+	// StatusUnavailable marks a missing count: analytics-rpc fails and no
+	// count is known.
+	StatusUnavailable = "unavailable"
+
+	breakerName = "analytics-rpc-click-count"
+	// maxCodesPerCall is the most short codes GetClickCounts accepts.
+	maxCodesPerCall = 1000
+	cacheName       = "click-count"
+)
+
+// Count is the click count of a link.
+type Count struct {
+	Clicks int64
+	// Status is StatusLive or StatusStale.
+	Status string
+	// AsOf is when Clicks was read from analytics-rpc.
+	AsOf time.Time
+}
+
+// Counts are the click counts of several links.
+type Counts struct {
+	Clicks map[string]int64
+	// Status is StatusLive or StatusStale.
+	Status string
+}
+
+type cachedCount struct {
+	clicks int64
+	asOf   time.Time
+}
+
+// Counter reads click counts from analytics-rpc through a circuit breaker.
+// It remembers the last count of each link and serves it, marked stale,
+// while calls fail or the breaker is open.
+type Counter struct {
+	analytics analyticsclient.Analytics
+	brk       breaker.Breaker
+	cache     *collection.Cache
+}
+
+// NewCounter returns a Counter reading from analytics.
+func NewCounter(c config.ClickCountConf, analytics analyticsclient.Analytics) (*Counter, error) {
+	cache, err := collection.NewCache(time.Duration(c.CacheExpiry)*time.Second,
+		collection.WithLimit(c.CacheLimit), collection.WithName(cacheName))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Counter{
+		analytics: analytics,
+		brk:       breaker.NewBreaker(breaker.WithName(breakerName)),
+		cache:     cache,
+	}, nil
+}
+
+// Count returns the click count of shortCode, falling back to the last known
+// count when analytics-rpc fails. It returns the error only when no count is
+// known.
+func (c *Counter) Count(ctx context.Context, shortCode string) (Count, error) {
+	var clicks int64
+	err := c.brk.DoWithAcceptableCtx(ctx, func() error {
+		resp, err := c.analytics.GetClickCount(ctx, &analyticsclient.GetClickCountRequest{
+			ShortCode: shortCode,
+		})
+		if err != nil {
+			return err
+		}
+		clicks = resp.TotalClicks
+		return nil
+	}, acceptable)
+	if err == nil {
+		now := time.Now()
+		c.cache.Set(shortCode, cachedCount{clicks: clicks, asOf: now})
+		return Count{Clicks: clicks, Status: StatusLive, AsOf: now}, nil
+	}
+
+	cached, ok := c.cache.Get(shortCode)
+	if !ok {
+		return Count{}, err
+	}
+
+	logx.WithContext(ctx).Infow("serving last known click count",
+		logx.Field("code", shortCode),
+		logx.Field("error", err.Error()),
+	)
+	last := cached.(cachedCount)
+	return Count{Clicks: last.clicks, Status: StatusStale, AsOf: last.asOf}, nil
+}
+
+// Cached returns the last known count of shortCode, marked stale, without
+// calling analytics-rpc, for callers that must not wait on it.
+func (c *Counter) Cached(shortCode string) (Count, bool) {
+	cached, ok := c.cache.Get(shortCode)
+	if !ok {
+		return Count{}, false
+	}
+
+	last := cached.(cachedCount)
+	return Count{Clicks: last.clicks, Status: StatusStale, AsOf: last.asOf}, true
+}
+
+// Counts returns the click counts of shortCodes, falling back to the last
+// known counts when analytics-rpc fails. It returns the error unless the count
+// of every short code is known.
+func (c *Counter) Counts(ctx context.Context, shortCodes []string) (Counts, error) {
+	clicks := make(map[string]int64, len(shortCodes))
+	err := c.fetch(ctx, shortCodes, clicks)
+	if err == nil {
+		now := time.Now()
+		for code, n := range clicks {
+			c.cache.Set(code, cachedCount{clicks: n, asOf: now})
+		}
+		return Counts{Clicks: clicks, Status: StatusLive}, nil
+	}
+
+	stale := make(map[string]int64, len(shortCodes))
+	for _, code := range shortCodes {
+		cached, ok := c.cache.Get(code)
+		if !ok {
+			return Counts{}, err
+		}
+		stale[code] = cached.(cachedCount).clicks
+	}
+
+	logx.WithContext(ctx).Infow("serving last known click counts",
+		logx.Field("codes", len(shortCodes)),
+		logx.Field("error", err.Error()),
+	)
+	return Counts{Clicks: stale, Status: StatusStale}, nil
+}
+
+// fetch reads the counts of shortCodes into clicks, maxCodesPerCall at a time.
+func (c *Counter) fetch(ctx context.Context, shortCodes []string, clicks map[string]int64) error {
+	for start := 0; start < len(shortCodes); start += maxCodesPerCall {
+		chunk := shortCodes[start:min(start+maxCodesPerCall, len(shortCodes))]
+		err := c.brk.DoWithAcceptableCtx(ctx, func() error {
+			resp, err := c.analytics.GetClickCounts(ctx, &analyticsclient.GetClickCountsRequest{
+				ShortCodes: chunk,
+			})
+			if err != nil {
+				return err
+			}
+			for _, link := range resp.Links {
+				clicks[link.ShortCode] = link.Clicks
+			}
+			return nil
+		}, acceptable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acceptable reports whether err leaves analytics-rpc healthy in the eyes of
+// the breaker: callers giving up and rejected requests are not its failures.
+func acceptable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,169 @@
+package clickcount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/breaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAnalytics answers GetClickCount and GetClickCounts with clicks for
+// every short code, or err when set.
+type fakeAnalytics struct {
+	analyticsclient.Analytics
+	clicks int64
+	err    error
+	calls  int
+	// batches records the short codes of each GetClickCounts call.
+	batches [][]string
+}
+
+func (f *fakeAnalytics) GetClickCount(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &analyticsclient.GetClickCountResponse{ShortCode: in.ShortCode, TotalClicks: f.clicks}, nil
+}
+
+func (f *fakeAnalytics) GetClickCounts(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+	f.calls++
+	f.batches = append(f.batches, in.ShortCodes)
+	if f.err != nil {
+		return nil, f.err
+	}
+	links := make([]*analyticsclient.LinkClicks, 0, len(in.ShortCodes))
+	for _, code := range in.ShortCodes {
+		links = append(links, &analyticsclient.LinkClicks{ShortCode: code, Clicks: f.clicks})
+	}
+	return &analyticsclient.GetClickCountsResponse{Links: links}, nil
+}
+
+func newTestCounter(t *testing.T, analytics analyticsclient.Analytics) *Counter {
+	counter, err := NewCounter(config.ClickCountConf{CacheLimit: 100, CacheExpiry: 60}, analytics)
+	require.NoError(t, err)
+	return counter
+}
+
+func TestCounter_Live(t *testing.T) {
+	counter := newTestCounter(t, &fakeAnalytics{clicks: 42})
+
+	count, err := counter.Count(context.Background(), "abc12345")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count.Clicks)
+	assert.Equal(t, StatusLive, count.Status)
+}
+
+func TestCounter_ServesLastKnownCountOnFailure(t *testing.T) {
+	analytics := &fakeAnalytics{clicks: 42}
+	counter := newTestCounter(t, analytics)
+	live, err := counter.Count(context.Background(), "abc12345")
+	require.NoError(t, err)
+
+	analytics.err = errors.New("connection refused")
+	count, err := counter.Count(context.Background(), "abc12345")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), count.Clicks)
+	assert.Equal(t, StatusStale, count.Status)
+	assert.Equal(t, live.AsOf, count.AsOf)
+}
+
+func TestCounter_CachedSkipsAnalytics(t *testing.T) {
+	analytics := &fakeAnalytics{clicks: 42}
+	counter := newTestCounter(t, analytics)
+
+	_, ok := counter.Cached("abc12345")
+	assert.False(t, ok)
+
+	live, err := counter.Count(context.Background(), "abc12345")
+	require.NoError(t, err)
+	count, ok := counter.Cached("abc12345")
+
+	require.True(t, ok)
+	assert.Equal(t, int64(42), count.Clicks)
+	assert.Equal(t, StatusStale, count.Status)
+	assert.Equal(t, live.AsOf, count.AsOf)
+	assert.Equal(t, 1, analytics.calls)
+}
+
+func TestCounter_UnknownCountFails(t *testing.T) {
+	counter := newTestCounter(t, &fakeAnalytics{err: errors.New("connection refused")})
+
+	_, err := counter.Count(context.Background(), "abc12345")
+
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestCounter_OpenBreakerSkipsAnalytics(t *testing.T) {
+	analytics := &fakeAnalytics{clicks: 42}
+	counter := newTestCounter(t, analytics)
+	_, err := counter.Count(context.Background(), "abc12345")
+	require.NoError(t, err)
+
+	analytics.err = status.Error(codes.Unavailable, "connection refused")
+	const requests = 200
+	for i := 0; i < requests; i++ {
+		count, err := counter.Count(context.Background(), "abc12345")
+		require.NoError(t, err)
+		assert.Equal(t, StatusStale, count.Status)
+	}
+
+	assert.Less(t, analytics.calls, requests, "the open breaker rejects calls")
+}
+
+func TestCounter_CountsChunksCodes(t *testing.T) {
+	analytics := &fakeAnalytics{clicks: 3}
+	counter := newTestCounter(t, analytics)
+	codes := make([]string, maxCodesPerCall+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("code%04d", i)
+	}
+
+	counts, err := counter.Counts(context.Background(), codes)
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusLive, counts.Status)
+	assert.Len(t, counts.Clicks, len(codes))
+	assert.Equal(t, int64(3), counts.Clicks["code1000"])
+	require.Len(t, analytics.batches, 2)
+	assert.Len(t, analytics.batches[0], maxCodesPerCall)
+	assert.Len(t, analytics.batches[1], 1)
+}
+
+func TestCounter_CountsServesLastKnownCounts(t *testing.T) {
+	analytics := &fakeAnalytics{clicks: 42}
+	counter := newTestCounter(t, analytics)
+	_, err := counter.Counts(context.Background(), []string{"abc12345", "xyz98765"})
+	require.NoError(t, err)
+
+	analytics.err = errors.New("connection refused")
+	counts, err := counter.Counts(context.Background(), []string{"abc12345", "xyz98765"})
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusStale, counts.Status)
+	assert.Equal(t, map[string]int64{"abc12345": 42, "xyz98765": 42}, counts.Clicks)
+
+	_, err = counter.Counts(context.Background(), []string{"abc12345", "unknown"})
+	assert.EqualError(t, err, "connection refused", "one unknown count fails the lot")
+}
+
+func TestAcceptable(t *testing.T) {
+	assert.True(t, acceptable(nil))
+	assert.True(t, acceptable(context.Canceled))
+	assert.True(t, acceptable(status.Error(codes.NotFound, "no clicks")))
+	assert.False(t, acceptable(status.Error(codes.Unavailable, "connection refused")))
+	assert.False(t, acceptable(context.DeadlineExceeded))
+	assert.False(t, acceptable(breaker.ErrServiceUnavailable))
+}
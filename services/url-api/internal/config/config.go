@@ -4,6 +4,10 @@
 package config
 
 import (
+	"go-shortener/common/eventbus"
+	"go-shortener/common/events"
+	"go-shortener/common/health"
+
 	"github.com/zeromicro/go-zero/rest"
 	"github.com/zeromicro/go-zero/zrpc"
 )
@@ -11,10 +15,21 @@ import (
 type Config struct {
 	rest.RestConf
 	BaseUrl      string
-	DataSource   string
-	Pool         PoolConfig
+	DataSource   string     `json:",inherit"`
+	Pool         PoolConfig `json:",inherit"`
 	KqPusherConf KqPusherConf
+	EventBus     eventbus.Conf
 	AnalyticsRpc zrpc.RpcClientConf
+	ClickCounts  ClickCountConf
+	// InterstitialCountdown is how many seconds the interstitial page waits
+	// before forwarding visitors of links flagged "always show interstitial".
+	InterstitialCountdown int `json:",default=5"`
+	Metadata              MetadataConf
+	HealthCheck           HealthCheckConf
+	Outbox                OutboxConf
+	Publisher             PublisherConf
+	// Readiness controls the dependency checks behind /readyz.
+	Readiness health.Conf
 }
 
 type PoolConfig struct {
@@ -23,7 +38,79 @@ type PoolConfig struct {
 	ConnMaxLifetime int `json:",default=3600"` // seconds
 }
 
+// KqPusherConf names the click event topic. Brokers are only used by the
+// kafka event bus driver.
 type KqPusherConf struct {
-	Brokers []string
+	Brokers []string `json:",optional"`
 	Topic   string
+	// Encoding of published click events; consumers read it from the
+	// content-type header.
+	Encoding string `json:",default=json,options=json|protobuf"`
+}
+
+// ContentType returns the content type of events published with Encoding.
+func (c KqPusherConf) ContentType() string {
+	if c.Encoding == "protobuf" {
+		return events.ContentTypeProtobuf
+	}
+	return events.ContentTypeJSON
+}
+
+// ClickCountConf sizes the cache of last known click counts served while
+// analytics-rpc is unavailable. Counts not refreshed within CacheExpiry are
+// reported unavailable.
+type ClickCountConf struct {
+	CacheLimit  int `json:",default=10000"`
+	CacheExpiry int `json:",default=86400"` // seconds
+}
+
+// OutboxConf controls the on-disk spool that holds click events while Kafka
+// is unavailable. Once MaxBytes are spooled, further events are dropped. The
+// relay pushes up to RelayBatchSize spooled events at once.
+type OutboxConf struct {
+	Dir            string `json:",default=data/outbox"`
+	MaxBytes       int64  `json:",default=1073741824"` // 1 GiB
+	SegmentBytes   int64  `json:",default=67108864"`   // 64 MiB
+	PushTimeout    int    `json:",default=5000"`       // milliseconds
+	RelayInterval  int    `json:",default=1000"`       // milliseconds
+	RelayBatchSize int    `json:",default=500,range=[1:10000]"`
+}
+
+// PublisherConf sizes the queue and worker pool that hand click events from
+// redirects to the outbox. Each worker pushes one event at a time, so the
+// workers publish about Workers events per EventBus.BatchTimeout.
+// ShutdownTimeout bounds the flush of queued events on shutdown and must stay
+// below the process shutdown wait (5.5s by default).
+type PublisherConf struct {
+	QueueSize       int `json:",default=10000"`
+	Workers         int `json:",default=8"`
+	ShutdownTimeout int `json:",default=3000"` // milliseconds
+}
+
+// MetadataConf controls the background fetch of destination page titles,
+// descriptions and favicons after a link is created. Workers fetch at a time
+// from a queue of QueueSize; links created while it is full get no metadata.
+type MetadataConf struct {
+	Enabled              bool  `json:",default=true"`
+	Timeout              int   `json:",default=5000"` // milliseconds
+	MaxBodyBytes         int64 `json:",default=1048576"`
+	AllowPrivateNetworks bool  `json:",optional"`
+	Workers              int   `json:",default=4"`
+	QueueSize            int   `json:",default=1000"`
+}
+
+// HealthCheckConf controls the background checker that periodically probes
+// link destinations, including every A/B variant destination. Broken destinations back off exponentially from Interval
+// up to MaxBackoff between checks. A replica claims each batch for Lease, so
+// other replicas skip it; it must outlast probing a whole batch.
+type HealthCheckConf struct {
+	Enabled              bool `json:",default=true"`
+	Interval             int  `json:",default=3600"`  // seconds between checks of a healthy link
+	PollInterval         int  `json:",default=60"`    // seconds between scans for due links
+	MaxBackoff           int  `json:",default=86400"` // seconds
+	Lease                int  `json:",default=600"`   // seconds
+	BatchSize            int  `json:",default=100"`
+	Concurrency          int  `json:",default=10"`
+	Timeout              int  `json:",default=5000"` // milliseconds
+	AllowPrivateNetworks bool `json:",optional"`
 }
@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package analytics
+
+import (
+	"net/http"
+
+	"go-shortener/services/url-api/internal/logic/analytics"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// Get click counts grouped by country, device and traffic source
+func GetAnalyticsSummaryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AnalyticsRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := analytics.NewGetAnalyticsSummaryLogic(r.Context(), svcCtx)
+		resp, err := l.GetAnalyticsSummary(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -5,15 +5,21 @@ package redirect
 
 import (
 	"net/http"
+	"strings"
 
 	"go-shortener/services/url-api/internal/logic/redirect"
+	"go-shortener/services/url-api/internal/pages"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
 
+	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
-// Redirect to original URL
+// previewSuffix appended to a short code (/:code+) requests the preview page.
+const previewSuffix = "+"
+
+// Redirect to original URL, or render a preview page for /:code+ and ?preview=1
 func RedirectHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req types.RedirectRequest
@@ -22,13 +28,47 @@ func RedirectHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
+		if code, ok := strings.CutSuffix(req.Code, previewSuffix); ok {
+			req.Code = code
+			req.Preview = true
+		}
+
+		if req.Preview {
+			page, err := redirect.NewPreviewLogic(r.Context(), svcCtx).Preview(&req)
+			if err != nil {
+				httpx.ErrorCtx(r.Context(), w, err)
+				return
+			}
+			writePage(r, w, page)
+			return
+		}
+
 		l := redirect.NewRedirectLogic(r.Context(), svcCtx)
-		originalUrl, err := l.Redirect(&req, r)
+		target, err := l.Redirect(&req, r)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 
-		http.Redirect(w, r, originalUrl, http.StatusFound)
+		if target.Cookie != nil {
+			http.SetCookie(w, target.Cookie)
+		}
+		if target.Unfurl != nil {
+			if err := pages.WriteOpenGraph(w, target.Unfurl); err != nil {
+				logx.WithContext(r.Context()).Errorw("failed to render open graph page", logx.Field("error", err.Error()))
+			}
+			return
+		}
+		if target.Interstitial != nil {
+			writePage(r, w, target.Interstitial)
+			return
+		}
+		http.Redirect(w, r, target.Url, http.StatusFound)
+	}
+}
+
+func writePage(r *http.Request, w http.ResponseWriter, page *pages.Preview) {
+	if err := pages.WritePreview(w, page); err != nil {
+		logx.WithContext(r.Context()).Errorw("failed to render preview page", logx.Field("error", err.Error()))
 	}
 }
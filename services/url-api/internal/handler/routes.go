@@ -6,6 +6,7 @@ package handler
 import (
 	"net/http"
 
+	analytics "go-shortener/services/url-api/internal/handler/analytics"
 	links "go-shortener/services/url-api/internal/handler/links"
 	redirect "go-shortener/services/url-api/internal/handler/redirect"
 	shorten "go-shortener/services/url-api/internal/handler/shorten"
@@ -15,6 +16,30 @@ import (
 )
 
 func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// Get click counts grouped by country, device and traffic source
+				Method:  http.MethodGet,
+				Path:    "/links/:code/analytics",
+				Handler: analytics.GetAnalyticsSummaryHandler(serverCtx),
+			},
+			{
+				// Get click counts bucketed by minute, hour, day or week
+				Method:  http.MethodGet,
+				Path:    "/links/:code/analytics/timeseries",
+				Handler: analytics.GetTimeSeriesHandler(serverCtx),
+			},
+			{
+				// Get the most clicked links of the last 24h, 7d or 30d
+				Method:  http.MethodGet,
+				Path:    "/analytics/top",
+				Handler: analytics.GetTopLinksHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -42,7 +67,7 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
 		[]rest.Route{
 			{
-				// Redirect to original URL
+				// Redirect to original URL, or render a preview page for /:code+ and ?preview=1
 				Method:  http.MethodGet,
 				Path:    "/:code",
 				Handler: redirect.RedirectHandler(serverCtx),
@@ -0,0 +1,284 @@
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/safehttp"
+	"go-shortener/services/url-api/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+const (
+	userAgent = "go-shortener-healthcheck/1.0"
+
+	// maxErrorLength bounds the stored error message.
+	maxErrorLength = 255
+)
+
+// Result is the outcome of probing a single destination. StatusCode is 0 when
+// the request itself failed.
+type Result struct {
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// Checker periodically probes link destinations and records status code,
+// latency and check time on each link. A/B split links are probed at
+// original_url, their fallback, and at every variant destination, and count as
+// broken when any of them is. It implements service.Service so it can run in
+// the same service group as the REST server.
+type Checker struct {
+	conf     config.HealthCheckConf
+	model    model.UrlsModel
+	variants model.UrlVariantsModel
+	client   *http.Client
+	now      func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewChecker returns a Checker backed by an SSRF-guarded HTTP client.
+func NewChecker(c config.HealthCheckConf, m model.UrlsModel, variants model.UrlVariantsModel) *Checker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Checker{
+		conf:     c,
+		model:    m,
+		variants: variants,
+		client: safehttp.NewClient(safehttp.Options{
+			Timeout:              time.Duration(c.Timeout) * time.Millisecond,
+			AllowPrivateNetworks: c.AllowPrivateNetworks,
+		}),
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start scans for due links every PollInterval until Stop is called.
+func (c *Checker) Start() {
+	logx.Infof("Destination health checker started: interval=%ds, concurrency=%d",
+		c.conf.Interval, c.conf.Concurrency)
+
+	ticker := time.NewTicker(time.Duration(c.conf.PollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		c.RunDue(c.ctx)
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop cancels in-flight probes and ends the Start loop.
+func (c *Checker) Stop() {
+	c.once.Do(c.cancel)
+}
+
+// RunDue checks batches of due links until a batch comes back short, so a
+// backlog larger than BatchSize does not wait a PollInterval per batch. It
+// returns how many links were checked.
+func (c *Checker) RunDue(ctx context.Context) int {
+	var total int
+	for ctx.Err() == nil {
+		n := c.RunOnce(ctx)
+		total += n
+		if n < c.conf.BatchSize {
+			break
+		}
+	}
+	return total
+}
+
+// RunOnce claims up to BatchSize due links, checks them at most Concurrency at
+// a time, and returns how many were checked.
+func (c *Checker) RunOnce(ctx context.Context) int {
+	now := c.now()
+	links, err := c.model.ClaimDueForHealthCheck(ctx, now, now.Add(c.lease()), c.conf.BatchSize)
+	if err != nil {
+		logx.WithContext(ctx).Errorw("failed to load links due for health check", logx.Field("error", err.Error()))
+		return 0
+	}
+
+	runner := threading.NewTaskRunner(c.conf.Concurrency)
+	for _, link := range links {
+		runner.Schedule(func() {
+			c.check(ctx, link)
+		})
+	}
+	runner.Wait()
+
+	return len(links)
+}
+
+func (c *Checker) check(ctx context.Context, link *model.Urls) {
+	result := c.probeLink(ctx, link)
+	if ctx.Err() != nil {
+		// Shutting down: the probe was cut short, not the destination.
+		return
+	}
+
+	apply(link, result, c.now(), c.interval(), c.maxBackoff())
+
+	if err := c.model.UpdateHealth(ctx, link); err != nil {
+		logx.WithContext(ctx).Errorw("failed to store link health",
+			logx.Field("short_code", link.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return
+	}
+
+	if link.Health() == model.HealthBroken {
+		logx.WithContext(ctx).Infow("destination health check failed",
+			logx.Field("short_code", link.ShortCode),
+			logx.Field("status_code", link.HealthStatusCode),
+			logx.Field("failures", link.HealthFailures),
+			logx.Field("error", link.HealthError),
+		)
+	}
+}
+
+// probeLink probes original_url and, for A/B split links, each variant
+// destination, stopping at the first broken one. The error of a broken
+// variant names its label. Variants that cannot be loaded are skipped, since
+// that is no fault of the destinations.
+func (c *Checker) probeLink(ctx context.Context, link *model.Urls) Result {
+	result := c.Probe(ctx, link.OriginalUrl)
+	if broken(result) || link.Rotation == "" {
+		return result
+	}
+
+	variants, err := c.variants.FindAllByShortCode(ctx, link.ShortCode)
+	if err != nil {
+		logx.WithContext(ctx).Errorw("failed to load variants for health check",
+			logx.Field("short_code", link.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return result
+	}
+	for _, v := range variants {
+		r := c.Probe(ctx, v.DestinationUrl)
+		if !broken(r) {
+			continue
+		}
+		err := r.Err
+		if err == nil {
+			err = errors.New(http.StatusText(r.StatusCode))
+		}
+		r.Err = fmt.Errorf("variant %s: %w", v.Label, err)
+		return r
+	}
+	return result
+}
+
+// broken reports whether result makes a link broken, as Urls.Health does.
+func broken(result Result) bool {
+	return result.StatusCode == 0 || result.StatusCode >= 400
+}
+
+// Probe sends a HEAD request to rawURL, falling back to GET for servers that
+// do not support HEAD. Redirects are followed; the final status is reported.
+func (c *Checker) Probe(ctx context.Context, rawURL string) Result {
+	start := time.Now()
+
+	resp, err := c.do(ctx, http.MethodHead, rawURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = c.do(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return Result{Latency: time.Since(start), Err: err}
+	}
+
+	return Result{StatusCode: resp.StatusCode, Latency: time.Since(start)}
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// Only the status matters; drain a little so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+func (c *Checker) interval() time.Duration {
+	return time.Duration(c.conf.Interval) * time.Second
+}
+
+func (c *Checker) lease() time.Duration {
+	return time.Duration(c.conf.Lease) * time.Second
+}
+
+func (c *Checker) maxBackoff() time.Duration {
+	return time.Duration(c.conf.MaxBackoff) * time.Second
+}
+
+// apply records result on link and schedules its next check. Healthy links are
+// rechecked after interval; each consecutive failure doubles the delay, capped
+// at maxBackoff.
+func apply(link *model.Urls, result Result, now time.Time, interval, maxBackoff time.Duration) {
+	link.HealthStatusCode = int64(result.StatusCode)
+	link.HealthLatencyMs = result.Latency.Milliseconds()
+	link.HealthError = ""
+	if result.Err != nil {
+		link.HealthError = truncate(result.Err.Error(), maxErrorLength)
+	}
+	link.HealthCheckedAt = sql.NullTime{Time: now, Valid: true}
+
+	if link.Health() == model.HealthBroken {
+		link.HealthFailures++
+	} else {
+		link.HealthFailures = 0
+	}
+
+	link.HealthNextCheckAt = sql.NullTime{Time: now.Add(backoff(link.HealthFailures, interval, maxBackoff)), Valid: true}
+}
+
+// backoff returns the delay before the next check after failures consecutive
+// failures.
+func backoff(failures int64, interval, maxBackoff time.Duration) time.Duration {
+	delay := interval
+	for i := int64(0); i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// truncate cuts s to at most limit bytes without splitting a UTF-8 rune.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !isRuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
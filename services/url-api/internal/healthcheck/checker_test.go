@@ -0,0 +1,296 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConf() config.HealthCheckConf {
+	return config.HealthCheckConf{
+		Enabled:              true,
+		Interval:             3600,
+		PollInterval:         60,
+		MaxBackoff:           86400,
+		Lease:                600,
+		BatchSize:            100,
+		Concurrency:          2,
+		Timeout:              2000,
+		AllowPrivateNetworks: true,
+	}
+}
+
+func newDestination(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProbe(t *testing.T) {
+	srv := newDestination(t)
+	checker := NewChecker(testConf(), &model.MockUrlsModel{}, &model.MockUrlVariantsModel{})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "healthy", path: "/ok", wantStatus: http.StatusOK},
+		{name: "falls back to GET", path: "/get-only", wantStatus: http.StatusOK},
+		{name: "follows redirects", path: "/moved", wantStatus: http.StatusOK},
+		{name: "not found", path: "/missing", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Probe(context.Background(), srv.URL+tt.path)
+			require.NoError(t, result.Err)
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestProbe_ConnectionError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	result := NewChecker(testConf(), &model.MockUrlsModel{}, &model.MockUrlVariantsModel{}).Probe(context.Background(), url)
+	require.Error(t, result.Err)
+	assert.Equal(t, 0, result.StatusCode)
+}
+
+func TestRunOnce_RecordsHealth(t *testing.T) {
+	srv := newDestination(t)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	updated := make(map[string]*model.Urls)
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, due, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			assert.Equal(t, now, due)
+			assert.Equal(t, now.Add(10*time.Minute), leaseUntil)
+			assert.Equal(t, 100, limit)
+			return []*model.Urls{
+				{Id: "id-ok", ShortCode: "okokokok", OriginalUrl: srv.URL + "/ok"},
+				{Id: "id-gone", ShortCode: "gonegone", OriginalUrl: srv.URL + "/missing", HealthFailures: 2},
+			}, nil
+		},
+		UpdateHealthFunc: func(ctx context.Context, data *model.Urls) error {
+			mu.Lock()
+			defer mu.Unlock()
+			updated[data.Id] = data
+			return nil
+		},
+	}
+
+	checker := NewChecker(testConf(), mockModel, &model.MockUrlVariantsModel{})
+	checker.now = func() time.Time { return now }
+
+	assert.Equal(t, 2, checker.RunOnce(context.Background()))
+	require.Len(t, updated, 2)
+
+	ok := updated["id-ok"]
+	assert.Equal(t, model.HealthHealthy, ok.Health())
+	assert.Equal(t, int64(http.StatusOK), ok.HealthStatusCode)
+	assert.Equal(t, int64(0), ok.HealthFailures)
+	assert.Equal(t, now, ok.HealthCheckedAt.Time)
+	assert.Equal(t, now.Add(time.Hour), ok.HealthNextCheckAt.Time)
+
+	gone := updated["id-gone"]
+	assert.Equal(t, model.HealthBroken, gone.Health())
+	assert.Equal(t, int64(http.StatusNotFound), gone.HealthStatusCode)
+	assert.Equal(t, int64(3), gone.HealthFailures)
+	assert.Equal(t, now.Add(8*time.Hour), gone.HealthNextCheckAt.Time)
+}
+
+func TestRunOnce_ChecksVariantDestinations(t *testing.T) {
+	srv := newDestination(t)
+
+	var mu sync.Mutex
+	updated := make(map[string]*model.Urls)
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, due, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			return []*model.Urls{
+				{Id: "id-split", ShortCode: "splitabc", OriginalUrl: srv.URL + "/ok", Rotation: model.RotationRandom},
+				{Id: "id-sound", ShortCode: "soundabc", OriginalUrl: srv.URL + "/ok", Rotation: model.RotationRandom},
+			}, nil
+		},
+		UpdateHealthFunc: func(ctx context.Context, data *model.Urls) error {
+			mu.Lock()
+			defer mu.Unlock()
+			updated[data.Id] = data
+			return nil
+		},
+	}
+	variants := &model.MockUrlVariantsModel{
+		FindAllByShortCodeFunc: func(ctx context.Context, shortCode string) ([]*model.UrlVariants, error) {
+			if shortCode == "soundabc" {
+				return []*model.UrlVariants{{Label: "a", DestinationUrl: srv.URL + "/moved"}}, nil
+			}
+			return []*model.UrlVariants{
+				{Label: "a", DestinationUrl: srv.URL + "/ok"},
+				{Label: "b", DestinationUrl: srv.URL + "/missing"},
+			}, nil
+		},
+	}
+
+	assert.Equal(t, 2, NewChecker(testConf(), mockModel, variants).RunOnce(context.Background()))
+	require.Len(t, updated, 2)
+
+	split := updated["id-split"]
+	assert.Equal(t, model.HealthBroken, split.Health())
+	assert.Equal(t, int64(http.StatusNotFound), split.HealthStatusCode)
+	assert.Equal(t, "variant b: Not Found", split.HealthError)
+
+	sound := updated["id-sound"]
+	assert.Equal(t, model.HealthHealthy, sound.Health())
+	assert.Empty(t, sound.HealthError)
+}
+
+func TestRunOnce_ConcurrencyLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	links := make([]*model.Urls, 8)
+	for i := range links {
+		links[i] = &model.Urls{Id: string(rune('a' + i)), OriginalUrl: srv.URL}
+	}
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			return links, nil
+		},
+		UpdateHealthFunc: func(ctx context.Context, data *model.Urls) error { return nil },
+	}
+
+	assert.Equal(t, 8, NewChecker(testConf(), mockModel, &model.MockUrlVariantsModel{}).RunOnce(context.Background()))
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestRunOnce_FindError(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	assert.Equal(t, 0, NewChecker(testConf(), mockModel, &model.MockUrlVariantsModel{}).RunOnce(context.Background()))
+}
+
+func TestRunDue_LoopsWhileBatchesAreFull(t *testing.T) {
+	srv := newDestination(t)
+	batches := [][]*model.Urls{
+		{{Id: "a", OriginalUrl: srv.URL + "/ok"}, {Id: "b", OriginalUrl: srv.URL + "/ok"}},
+		{{Id: "c", OriginalUrl: srv.URL + "/ok"}, {Id: "d", OriginalUrl: srv.URL + "/ok"}},
+		{{Id: "e", OriginalUrl: srv.URL + "/ok"}},
+	}
+	var claims atomic.Int32
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			n := int(claims.Add(1)) - 1
+			require.Less(t, n, len(batches), "a short batch ends the run")
+			return batches[n], nil
+		},
+		UpdateHealthFunc: func(ctx context.Context, data *model.Urls) error { return nil },
+	}
+
+	conf := testConf()
+	conf.BatchSize = 2
+	assert.Equal(t, 5, NewChecker(conf, mockModel, &model.MockUrlVariantsModel{}).RunDue(context.Background()))
+	assert.Equal(t, int32(3), claims.Load())
+}
+
+func TestStartStop(t *testing.T) {
+	scanned := make(chan struct{}, 1)
+	mockModel := &model.MockUrlsModel{
+		ClaimDueForHealthCheckFunc: func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*model.Urls, error) {
+			select {
+			case scanned <- struct{}{}:
+			default:
+			}
+			return nil, nil
+		},
+	}
+
+	checker := NewChecker(testConf(), mockModel, &model.MockUrlVariantsModel{})
+	done := make(chan struct{})
+	go func() {
+		checker.Start()
+		close(done)
+	}()
+
+	<-scanned
+	checker.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("checker did not stop")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	interval := time.Hour
+	maxBackoff := 24 * time.Hour
+
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{failures: 0, want: time.Hour},
+		{failures: 1, want: 2 * time.Hour},
+		{failures: 3, want: 8 * time.Hour},
+		{failures: 5, want: 24 * time.Hour},
+		{failures: 100, want: 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, backoff(tt.failures, interval, maxBackoff), "failures=%d", tt.failures)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "dial tcp: ", truncate("dial tcp: lookup", 10))
+	// "é" is two bytes; cutting inside it drops the whole rune
+	assert.Equal(t, "caf", truncate("café au lait", 4))
+	assert.Equal(t, "café", truncate("café au lait", 5))
+}
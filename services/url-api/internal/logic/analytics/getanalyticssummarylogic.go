@@ -0,0 +1,128 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package analytics
+
+import (
+	"context"
+	"errors"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetAnalyticsSummaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// Get click counts grouped by country, device and traffic source
+func NewGetAnalyticsSummaryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAnalyticsSummaryLogic {
+	return &GetAnalyticsSummaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAnalyticsSummaryLogic) GetAnalyticsSummary(req *types.AnalyticsRequest) (resp *types.AnalyticsSummaryResponse, err error) {
+	logx.WithContext(l.ctx).Infow("get analytics summary",
+		logx.Field("code", req.Code),
+		logx.Field("from", req.From),
+		logx.Field("to", req.To),
+		logx.Field("approximate", req.Approximate),
+	)
+
+	if rangeErr := validateRange(req.From, req.To); rangeErr != nil {
+		return nil, rangeErr
+	}
+	if findErr := findLink(l.ctx, l.svcCtx, req.Code); findErr != nil {
+		return nil, findErr
+	}
+
+	summary, rpcErr := l.svcCtx.AnalyticsRpc.GetAnalyticsSummary(l.ctx, &analyticsclient.GetAnalyticsSummaryRequest{
+		ShortCode:   req.Code,
+		From:        req.From,
+		To:          req.To,
+		Approximate: req.Approximate,
+	})
+	if rpcErr != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get analytics summary from analytics rpc",
+			logx.Field("code", req.Code),
+			logx.Field("error", rpcErr.Error()),
+		)
+		return nil, analyticsUnavailable()
+	}
+
+	return &types.AnalyticsSummaryResponse{
+		ShortCode:                 req.Code,
+		From:                      req.From,
+		To:                        req.To,
+		TotalClicks:               summary.TotalClicks,
+		Countries:                 dimensionCounts(summary.Countries),
+		Devices:                   dimensionCounts(summary.Devices),
+		TrafficSources:            dimensionCounts(summary.TrafficSources),
+		UniqueVisitors:            summary.UniqueVisitors,
+		UniqueVisitorsApproximate: summary.UniqueVisitorsApproximate,
+		UniqueVisitorsPartial:     summary.UniqueVisitorsPartial,
+	}, nil
+}
+
+// findLink ensures the short code exists so unknown links return 404 rather
+// than empty analytics.
+func findLink(ctx context.Context, svcCtx *svc.ServiceContext, code string) error {
+	_, err := svcCtx.UrlModel.FindOneByShortCode(ctx, code)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		return problemdetails.New(404, problemdetails.TypeNotFound, "Not Found",
+			"short code '"+code+"' not found")
+	}
+	logx.WithContext(ctx).Errorw("failed to find URL", logx.Field("error", err.Error()))
+	return problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error",
+		"failed to look up link")
+}
+
+// validateRange checks the optional [from, to) range of unix seconds.
+func validateRange(from, to int64) error {
+	var fieldErrors []problemdetails.FieldError
+	if from < 0 {
+		fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: "from", Message: "must be a unix timestamp"})
+	}
+	if to < 0 {
+		fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: "to", Message: "must be a unix timestamp"})
+	}
+	if from > 0 && to > 0 && from >= to {
+		fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: "to", Message: "must be after from"})
+	}
+
+	if len(fieldErrors) > 0 {
+		return problemdetails.NewValidation(fieldErrors)
+	}
+	return nil
+}
+
+// analyticsUnavailable is returned when the Analytics RPC fails. Unlike link
+// detail, analytics endpoints have nothing to degrade to.
+func analyticsUnavailable() error {
+	return problemdetails.New(503, problemdetails.TypeServiceUnavailable, "Service Unavailable",
+		"analytics are temporarily unavailable")
+}
+
+func dimensionCounts(counts []*analyticsclient.DimensionCount) []types.DimensionCount {
+	resp := make([]types.DimensionCount, 0, len(counts))
+	for _, c := range counts {
+		resp = append(resp, types.DimensionCount{
+			Value:  c.Value,
+			Clicks: c.Clicks,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,151 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockAnalyticsClient is a test mock for the Analytics RPC client. Methods
+// without a Func field panic through the embedded nil interface.
+type mockAnalyticsClient struct {
+	analyticsclient.Analytics
+	getAnalyticsSummaryFunc func(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error)
+	getClickTimeSeriesFunc  func(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error)
+	getTopLinksFunc         func(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error)
+}
+
+func (m *mockAnalyticsClient) GetAnalyticsSummary(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error) {
+	return m.getAnalyticsSummaryFunc(ctx, in, opts...)
+}
+
+func (m *mockAnalyticsClient) GetClickTimeSeries(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+	return m.getClickTimeSeriesFunc(ctx, in, opts...)
+}
+
+func (m *mockAnalyticsClient) GetTopLinks(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+	return m.getTopLinksFunc(ctx, in, opts...)
+}
+
+// existingLink returns a UrlsModel mock that finds every short code.
+func existingLink() *model.MockUrlsModel {
+	return &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{Id: "test-id", ShortCode: shortCode, OriginalUrl: "https://example.com", CreatedAt: time.Now()}, nil
+		},
+	}
+}
+
+func TestGetAnalyticsSummaryLogic_Success(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getAnalyticsSummaryFunc: func(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error) {
+			assert.Equal(t, "abc12345", in.ShortCode)
+			assert.Equal(t, int64(1000), in.From)
+			assert.Equal(t, int64(2000), in.To)
+			assert.True(t, in.Approximate)
+			return &analyticsclient.GetAnalyticsSummaryResponse{
+				ShortCode:                 "abc12345",
+				TotalClicks:               42,
+				Countries:                 []*analyticsclient.DimensionCount{{Value: "DE", Clicks: 30}, {Value: "US", Clicks: 12}},
+				Devices:                   []*analyticsclient.DimensionCount{{Value: "Mobile", Clicks: 42}},
+				TrafficSources:            []*analyticsclient.DimensionCount{{Value: "Direct", Clicks: 42}},
+				UniqueVisitors:            9,
+				UniqueVisitorsApproximate: true,
+				UniqueVisitorsPartial:     true,
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     existingLink(),
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&types.AnalyticsRequest{Code: "abc12345", From: 1000, To: 2000, Approximate: true})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "abc12345", resp.ShortCode)
+	assert.Equal(t, int64(42), resp.TotalClicks)
+	assert.Equal(t, []types.DimensionCount{{Value: "DE", Clicks: 30}, {Value: "US", Clicks: 12}}, resp.Countries)
+	assert.Equal(t, []types.DimensionCount{{Value: "Mobile", Clicks: 42}}, resp.Devices)
+	assert.Equal(t, []types.DimensionCount{{Value: "Direct", Clicks: 42}}, resp.TrafficSources)
+	assert.Equal(t, int64(9), resp.UniqueVisitors)
+	assert.True(t, resp.UniqueVisitorsApproximate)
+	assert.True(t, resp.UniqueVisitorsPartial)
+}
+
+func TestGetAnalyticsSummaryLogic_NotFound(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return nil, model.ErrNotFound
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&types.AnalyticsRequest{Code: "notfound"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 404, pd.Status)
+}
+
+func TestGetAnalyticsSummaryLogic_InvalidRange(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: &model.MockUrlsModel{},
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&types.AnalyticsRequest{Code: "abc12345", From: 2000, To: 1000})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 400, pd.Status)
+}
+
+func TestGetAnalyticsSummaryLogic_RPCFailure(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getAnalyticsSummaryFunc: func(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     existingLink(),
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetAnalyticsSummaryLogic(context.Background(), svcCtx)
+	resp, err := logic.GetAnalyticsSummary(&types.AnalyticsRequest{Code: "abc12345"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 503, pd.Status)
+}
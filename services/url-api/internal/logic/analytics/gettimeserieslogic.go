@@ -0,0 +1,96 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GetTimeSeriesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// Get click counts bucketed by minute, hour, day or week
+func NewGetTimeSeriesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTimeSeriesLogic {
+	return &GetTimeSeriesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetTimeSeriesLogic) GetTimeSeries(req *types.TimeSeriesRequest) (resp *types.TimeSeriesResponse, err error) {
+	logx.WithContext(l.ctx).Infow("get click time series",
+		logx.Field("code", req.Code),
+		logx.Field("interval", req.Interval),
+		logx.Field("tz", req.Timezone),
+		logx.Field("approximate", req.Approximate),
+	)
+
+	if rangeErr := validateRange(req.From, req.To); rangeErr != nil {
+		return nil, rangeErr
+	}
+	if req.Timezone != "" {
+		if _, tzErr := time.LoadLocation(req.Timezone); tzErr != nil {
+			return nil, problemdetails.NewValidation([]problemdetails.FieldError{
+				{Field: "tz", Message: "must be an IANA timezone name, e.g. Europe/Berlin"},
+			})
+		}
+	}
+	if findErr := findLink(l.ctx, l.svcCtx, req.Code); findErr != nil {
+		return nil, findErr
+	}
+
+	series, rpcErr := l.svcCtx.AnalyticsRpc.GetClickTimeSeries(l.ctx, &analyticsclient.GetClickTimeSeriesRequest{
+		ShortCode:   req.Code,
+		Interval:    req.Interval,
+		From:        req.From,
+		To:          req.To,
+		Timezone:    req.Timezone,
+		Approximate: req.Approximate,
+	})
+	if rpcErr != nil {
+		// The RPC rejects ranges spanning too many buckets
+		if st, ok := status.FromError(rpcErr); ok && st.Code() == codes.InvalidArgument {
+			return nil, problemdetails.New(400, problemdetails.TypeValidationError, "Bad Request", st.Message())
+		}
+		logx.WithContext(l.ctx).Errorw("failed to get click time series from analytics rpc",
+			logx.Field("code", req.Code),
+			logx.Field("error", rpcErr.Error()),
+		)
+		return nil, analyticsUnavailable()
+	}
+
+	buckets := make([]types.TimeBucket, 0, len(series.Buckets))
+	for _, b := range series.Buckets {
+		buckets = append(buckets, types.TimeBucket{
+			Start:          b.Start,
+			Clicks:         b.Clicks,
+			UniqueVisitors: b.UniqueVisitors,
+		})
+	}
+
+	return &types.TimeSeriesResponse{
+		ShortCode:                 req.Code,
+		Interval:                  series.Interval,
+		Timezone:                  series.Timezone,
+		From:                      series.From,
+		To:                        series.To,
+		Buckets:                   buckets,
+		UniqueVisitorsApproximate: series.UniqueVisitorsApproximate,
+		UniqueVisitorsPartial:     series.UniqueVisitorsPartial,
+	}, nil
+}
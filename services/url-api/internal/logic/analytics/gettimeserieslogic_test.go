@@ -0,0 +1,120 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetTimeSeriesLogic_Success(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getClickTimeSeriesFunc: func(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+			assert.Equal(t, "abc12345", in.ShortCode)
+			assert.Equal(t, "day", in.Interval)
+			assert.Equal(t, "Europe/Berlin", in.Timezone)
+			return &analyticsclient.GetClickTimeSeriesResponse{
+				ShortCode: "abc12345",
+				Interval:  "day",
+				Timezone:  "Europe/Berlin",
+				From:      1748728800,
+				To:        1748901600,
+				Buckets: []*analyticsclient.TimeBucket{
+					{Start: 1748728800, Clicks: 4, UniqueVisitors: 3},
+					{Start: 1748815200, Clicks: 0},
+				},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     existingLink(),
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTimeSeries(&types.TimeSeriesRequest{Code: "abc12345", Interval: "day", Timezone: "Europe/Berlin"})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, "day", resp.Interval)
+	assert.Equal(t, "Europe/Berlin", resp.Timezone)
+	assert.Equal(t, int64(1748728800), resp.From)
+	assert.Equal(t, []types.TimeBucket{{Start: 1748728800, Clicks: 4, UniqueVisitors: 3}, {Start: 1748815200, Clicks: 0}}, resp.Buckets)
+}
+
+func TestGetTimeSeriesLogic_InvalidTimezone(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: &model.MockUrlsModel{},
+	}
+
+	logic := NewGetTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTimeSeries(&types.TimeSeriesRequest{Code: "abc12345", Interval: "hour", Timezone: "Mars/Olympus"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 400, pd.Status)
+}
+
+func TestGetTimeSeriesLogic_RangeRejectedByRPC(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getClickTimeSeriesFunc: func(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+			return nil, status.Error(codes.InvalidArgument, "time range too large")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     existingLink(),
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTimeSeries(&types.TimeSeriesRequest{Code: "abc12345", Interval: "minute", From: 1, To: 1000000})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 400, pd.Status)
+	assert.Equal(t, "time range too large", pd.Detail)
+}
+
+func TestGetTimeSeriesLogic_RPCFailure(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getClickTimeSeriesFunc: func(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     existingLink(),
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTimeSeriesLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTimeSeries(&types.TimeSeriesRequest{Code: "abc12345", Interval: "hour"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 503, pd.Status)
+}
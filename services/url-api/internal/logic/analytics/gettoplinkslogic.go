@@ -0,0 +1,155 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package analytics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	urlmodel "go-shortener/services/url-api/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxTopLinksPages bounds how many pages of ranked short codes are read to
+// make up for deleted links.
+const maxTopLinksPages = 5
+
+// topLinksPeriods maps the period parameter to the window ending now.
+var topLinksPeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+type GetTopLinksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	now    func() time.Time
+}
+
+// Get the most clicked links of the last 24h, 7d or 30d
+func NewGetTopLinksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTopLinksLogic {
+	return &GetTopLinksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		now:    time.Now,
+	}
+}
+
+func (l *GetTopLinksLogic) GetTopLinks(req *types.TopLinksRequest) (resp *types.TopLinksResponse, err error) {
+	logx.WithContext(l.ctx).Infow("get top links",
+		logx.Field("period", req.Period),
+		logx.Field("limit", req.Limit),
+		logx.Field("country", req.Country),
+		logx.Field("device", req.Device),
+		logx.Field("source", req.Source),
+	)
+
+	period, ok := topLinksPeriods[req.Period]
+	if !ok {
+		return nil, problemdetails.NewValidation([]problemdetails.FieldError{
+			{Field: "period", Message: "must be one of 24h, 7d, 30d"},
+		})
+	}
+	country := strings.ToUpper(req.Country)
+	if country != "" && len(country) != 2 {
+		return nil, problemdetails.NewValidation([]problemdetails.FieldError{
+			{Field: "country", Message: "must be an ISO 3166-1 alpha-2 code, e.g. DE"},
+		})
+	}
+
+	var filters []*analyticsclient.DimensionFilter
+	for _, f := range []struct{ dimension, value string }{
+		{"country_code", country},
+		{"device_type", req.Device},
+		{"traffic_source", req.Source},
+	} {
+		if f.value != "" {
+			filters = append(filters, &analyticsclient.DimensionFilter{Dimension: f.dimension, Value: f.value})
+		}
+	}
+
+	to := l.now().Truncate(time.Second)
+	from := to.Add(-period)
+	// Clicks outlive deleted links, so pages of ranked short codes are read
+	// until limit of them resolve to links.
+	links := make([]types.TopLink, 0, req.Limit)
+	for page, offset := 0, 0; page < maxTopLinksPages && len(links) < req.Limit; page++ {
+		top, rpcErr := l.svcCtx.AnalyticsRpc.GetTopLinks(l.ctx, &analyticsclient.GetTopLinksRequest{
+			From:    from.Unix(),
+			To:      to.Unix(),
+			Limit:   int32(req.Limit),
+			Offset:  int32(offset),
+			Filters: filters,
+		})
+		if rpcErr != nil {
+			logx.WithContext(l.ctx).Errorw("failed to get top links from analytics rpc",
+				logx.Field("error", rpcErr.Error()),
+			)
+			return nil, analyticsUnavailable()
+		}
+
+		resolved, err := l.resolve(top.Links)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, resolved[:min(len(resolved), req.Limit-len(links))]...)
+
+		if len(top.Links) < req.Limit {
+			break
+		}
+		offset += len(top.Links)
+	}
+
+	return &types.TopLinksResponse{
+		Period: req.Period,
+		From:   from.Unix(),
+		To:     to.Unix(),
+		Links:  links,
+	}, nil
+}
+
+// resolve returns the ranked links that still exist, in rank order.
+func (l *GetTopLinksLogic) resolve(ranked []*analyticsclient.LinkClicks) ([]types.TopLink, error) {
+	shortCodes := make([]string, 0, len(ranked))
+	for _, link := range ranked {
+		shortCodes = append(shortCodes, link.ShortCode)
+	}
+	urls, err := l.svcCtx.UrlModel.FindByShortCodes(l.ctx, shortCodes)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to find top links", logx.Field("error", err.Error()))
+		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error",
+			"failed to look up links")
+	}
+	byShortCode := make(map[string]*urlmodel.Urls, len(urls))
+	for _, u := range urls {
+		byShortCode[u.ShortCode] = u
+	}
+
+	links := make([]types.TopLink, 0, len(ranked))
+	for _, link := range ranked {
+		u, ok := byShortCode[link.ShortCode]
+		if !ok {
+			continue
+		}
+		links = append(links, types.TopLink{
+			ShortCode:   u.ShortCode,
+			ShortUrl:    l.svcCtx.Config.BaseUrl + "/" + u.ShortCode,
+			OriginalUrl: u.OriginalUrl,
+			PageTitle:   u.PageTitle,
+			FaviconUrl:  u.FaviconUrl,
+			CreatedAt:   u.CreatedAt.Unix(),
+			Clicks:      link.Clicks,
+		})
+	}
+	return links, nil
+}
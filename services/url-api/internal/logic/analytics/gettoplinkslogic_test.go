@@ -0,0 +1,178 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestGetTopLinksLogic_Success(t *testing.T) {
+	now := time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var offsets []int32
+	mockAnalytics := &mockAnalyticsClient{
+		getTopLinksFunc: func(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+			assert.Equal(t, now.Add(-7*24*time.Hour).Unix(), in.From)
+			assert.Equal(t, now.Unix(), in.To)
+			assert.Equal(t, int32(3), in.Limit)
+			require.Len(t, in.Filters, 2)
+			assert.Equal(t, "country_code", in.Filters[0].Dimension)
+			assert.Equal(t, "DE", in.Filters[0].Value)
+			assert.Equal(t, "traffic_source", in.Filters[1].Dimension)
+			assert.Equal(t, "Social", in.Filters[1].Value)
+			offsets = append(offsets, in.Offset)
+			if in.Offset > 0 {
+				return &analyticsclient.GetTopLinksResponse{
+					Links: []*analyticsclient.LinkClicks{
+						{ShortCode: "popular3", Clicks: 70},
+						{ShortCode: "popular4", Clicks: 60},
+					},
+				}, nil
+			}
+			return &analyticsclient.GetTopLinksResponse{
+				Links: []*analyticsclient.LinkClicks{
+					{ShortCode: "popular1", Clicks: 120},
+					{ShortCode: "deleted1", Clicks: 90},
+					{ShortCode: "popular2", Clicks: 80},
+				},
+			}, nil
+		},
+	}
+
+	links := map[string]*model.Urls{
+		"popular1": {ShortCode: "popular1", OriginalUrl: "https://example.com/a", PageTitle: "Example A", CreatedAt: createdAt},
+		"popular2": {ShortCode: "popular2", OriginalUrl: "https://example.com/b", CreatedAt: createdAt},
+		"popular3": {ShortCode: "popular3", OriginalUrl: "https://example.com/c", CreatedAt: createdAt},
+		"popular4": {ShortCode: "popular4", OriginalUrl: "https://example.com/d", CreatedAt: createdAt},
+	}
+	mockModel := &model.MockUrlsModel{
+		FindByShortCodesFunc: func(ctx context.Context, shortCodes []string) ([]*model.Urls, error) {
+			// Returned in database order, not rank order
+			var found []*model.Urls
+			for i := len(shortCodes) - 1; i >= 0; i-- {
+				if u, ok := links[shortCodes[i]]; ok {
+					found = append(found, u)
+				}
+			}
+			return found, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTopLinksLogic(context.Background(), svcCtx)
+	logic.now = func() time.Time { return now }
+	resp, err := logic.GetTopLinks(&types.TopLinksRequest{Period: "7d", Limit: 3, Country: "de", Source: "Social"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "7d", resp.Period)
+	assert.Equal(t, now.Unix(), resp.To)
+	assert.Equal(t, []int32{0, 3}, offsets, "the deleted link is made up for from the next page")
+	assert.Equal(t, []types.TopLink{
+		{
+			ShortCode:   "popular1",
+			ShortUrl:    "http://localhost:8080/popular1",
+			OriginalUrl: "https://example.com/a",
+			PageTitle:   "Example A",
+			CreatedAt:   createdAt.Unix(),
+			Clicks:      120,
+		},
+		{
+			ShortCode:   "popular2",
+			ShortUrl:    "http://localhost:8080/popular2",
+			OriginalUrl: "https://example.com/b",
+			CreatedAt:   createdAt.Unix(),
+			Clicks:      80,
+		},
+		{
+			ShortCode:   "popular3",
+			ShortUrl:    "http://localhost:8080/popular3",
+			OriginalUrl: "https://example.com/c",
+			CreatedAt:   createdAt.Unix(),
+			Clicks:      70,
+		},
+	}, resp.Links)
+}
+
+func TestGetTopLinksLogic_InvalidCountry(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config: config.Config{BaseUrl: "http://localhost:8080"},
+	}
+
+	logic := NewGetTopLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTopLinks(&types.TopLinksRequest{Period: "24h", Limit: 10, Country: "Germany"})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 400, pd.Status)
+}
+
+func TestGetTopLinksLogic_RPCFailure(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getTopLinksFunc: func(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     &model.MockUrlsModel{},
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTopLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTopLinks(&types.TopLinksRequest{Period: "24h", Limit: 10})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 503, pd.Status)
+}
+
+func TestGetTopLinksLogic_DBError(t *testing.T) {
+	mockAnalytics := &mockAnalyticsClient{
+		getTopLinksFunc: func(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+			return &analyticsclient.GetTopLinksResponse{Links: []*analyticsclient.LinkClicks{{ShortCode: "popular1", Clicks: 1}}}, nil
+		},
+	}
+	mockModel := &model.MockUrlsModel{
+		FindByShortCodesFunc: func(ctx context.Context, shortCodes []string) ([]*model.Urls, error) {
+			return nil, errors.New("database connection timeout")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewGetTopLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.GetTopLinks(&types.TopLinksRequest{Period: "30d", Limit: 10})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.True(t, errors.As(err, &pd))
+	assert.Equal(t, 500, pd.Status)
+}
@@ -0,0 +1,92 @@
+package links
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go-shortener/services/url-api/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sortCreatedAt   = "created_at"
+	sortOriginalUrl = "original_url"
+
+	// cursorStart asks for the first page in cursor mode, without counting
+	// the matching links.
+	cursorStart = "start"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// listCursor is a position in the links list, handed to clients as an opaque
+// next_cursor or prev_cursor. It records the sort it was issued for, since a
+// position means nothing in another order.
+type listCursor struct {
+	Sort     string `json:"s"`
+	Order    string `json:"o"`
+	Backward bool   `json:"b,omitempty"`
+	Value    string `json:"v"`
+	Id       string `json:"i"`
+}
+
+// newListCursor returns the cursor continuing the list from u, forward to the
+// links after it or backward to the links before it.
+func newListCursor(sort, order string, u *model.Urls, backward bool) listCursor {
+	c := listCursor{Sort: sort, Order: order, Backward: backward, Id: u.Id}
+	if sort == sortOriginalUrl {
+		c.Value = u.OriginalUrl
+	} else {
+		c.Value = u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c
+}
+
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(s string) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return listCursor{}, errInvalidCursor
+	}
+
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return listCursor{}, errInvalidCursor
+	}
+	if _, err := uuid.Parse(c.Id); err != nil {
+		return listCursor{}, errInvalidCursor
+	}
+	switch c.Sort {
+	case sortCreatedAt, sortOriginalUrl:
+	default:
+		return listCursor{}, errInvalidCursor
+	}
+	if c.Order != "asc" && c.Order != "desc" {
+		return listCursor{}, errInvalidCursor
+	}
+	if c.Sort == sortCreatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return listCursor{}, errInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+// key returns the position of the cursor for the keyset query.
+func (c listCursor) key() *model.ListKey {
+	key := &model.ListKey{Id: c.Id}
+	if c.Sort == sortOriginalUrl {
+		key.OriginalUrl = c.Value
+	} else {
+		// Validated by decodeListCursor.
+		key.CreatedAt, _ = time.Parse(time.RFC3339Nano, c.Value)
+	}
+	return key
+}
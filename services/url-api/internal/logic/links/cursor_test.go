@@ -0,0 +1,50 @@
+package links
+
+import (
+	"testing"
+	"time"
+
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	u := &model.Urls{
+		Id:          "0190a5c8-0000-7000-8000-000000000001",
+		OriginalUrl: "https://example.com",
+		CreatedAt:   createdAt,
+	}
+
+	c, err := decodeListCursor(newListCursor(sortCreatedAt, "desc", u, true).encode())
+	require.NoError(t, err)
+	assert.Equal(t, sortCreatedAt, c.Sort)
+	assert.Equal(t, "desc", c.Order)
+	assert.True(t, c.Backward)
+	assert.Equal(t, &model.ListKey{CreatedAt: createdAt, Id: u.Id}, c.key())
+
+	c, err = decodeListCursor(newListCursor(sortOriginalUrl, "asc", u, false).encode())
+	require.NoError(t, err)
+	assert.False(t, c.Backward)
+	assert.Equal(t, &model.ListKey{OriginalUrl: "https://example.com", Id: u.Id}, c.key())
+}
+
+func TestDecodeListCursor_Invalid(t *testing.T) {
+	u := &model.Urls{Id: "0190a5c8-0000-7000-8000-000000000001", CreatedAt: time.Now()}
+	tests := map[string]string{
+		"not base64":   "%%%",
+		"not json":     "bm90IGpzb24",
+		"unknown sort": listCursor{Sort: "clicks", Order: "desc", Value: "9", Id: u.Id}.encode(),
+		"bad order":    listCursor{Sort: sortOriginalUrl, Order: "up", Id: u.Id}.encode(),
+		"bad id":       listCursor{Sort: sortOriginalUrl, Order: "asc", Id: "1; DROP TABLE urls"}.encode(),
+		"bad time":     listCursor{Sort: sortCreatedAt, Order: "desc", Value: "yesterday", Id: u.Id}.encode(),
+	}
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeListCursor(cursor)
+			assert.ErrorIs(t, err, errInvalidCursor)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"go-shortener/pkg/problemdetails"
 	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
 	"go-shortener/services/url-api/model"
@@ -45,25 +46,99 @@ func (l *GetLinkDetailLogic) GetLinkDetail(req *types.LinkDetailRequest) (resp *
 			"failed to look up link detail")
 	}
 
-	// Get click count from Analytics RPC service
-	var totalClicks int64
-	clickResp, rpcErr := l.svcCtx.AnalyticsRpc.GetClickCount(l.ctx, &analyticsclient.GetClickCountRequest{
-		ShortCode: req.Code,
+	resp = &types.LinkDetailResponse{
+		ShortCode:       url.ShortCode,
+		OriginalUrl:     url.OriginalUrl,
+		CreatedAt:       url.CreatedAt.Unix(),
+		Rotation:        url.Rotation,
+		Variants:        l.variantItems(url),
+		Title:           url.Title,
+		Interstitial:    url.Interstitial,
+		OgTitle:         url.OgTitle,
+		OgDescription:   url.OgDescription,
+		OgImage:         url.OgImage,
+		PageTitle:       url.PageTitle,
+		PageDescription: url.PageDescription,
+		FaviconUrl:      url.FaviconUrl,
+		Health:          linkHealth(url),
+	}
+	l.setClicks(resp, req.Code)
+
+	return resp, nil
+}
+
+// setClicks fills in the click count from analytics-rpc. While analytics-rpc
+// is unavailable, the last known count is reported stale; without one, the
+// count is reported unavailable rather than as 0 clicks.
+func (l *GetLinkDetailLogic) setClicks(resp *types.LinkDetailResponse, code string) {
+	count, err := l.svcCtx.ClickCounts.Count(l.ctx, code)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get click count from analytics rpc, degrading gracefully",
+			logx.Field("code", code),
+			logx.Field("error", err.Error()),
+		)
+		resp.ClicksStatus = clickcount.StatusUnavailable
+		return
+	}
+
+	resp.TotalClicks = count.Clicks
+	resp.ClicksStatus = count.Status
+	if count.Status == clickcount.StatusStale {
+		resp.ClicksAsOf = count.AsOf.Unix()
+	}
+}
+
+// linkHealth maps the result of the last destination health check.
+func linkHealth(url *model.Urls) types.LinkHealth {
+	health := types.LinkHealth{Status: url.Health()}
+	if url.HealthCheckedAt.Valid {
+		health.StatusCode = int(url.HealthStatusCode)
+		health.LatencyMs = url.HealthLatencyMs
+		health.Error = url.HealthError
+		health.CheckedAt = url.HealthCheckedAt.Time.Unix()
+	}
+	return health
+}
+
+// variantItems returns the weighted destinations of an A/B split link with their
+// click counts. Failures degrade gracefully: missing counts are reported as 0.
+func (l *GetLinkDetailLogic) variantItems(url *model.Urls) []types.VariantItem {
+	if url.Rotation == "" {
+		return nil
+	}
+
+	variants, findErr := l.svcCtx.UrlVariantModel.FindAllByShortCode(l.ctx, url.ShortCode)
+	if findErr != nil {
+		logx.WithContext(l.ctx).Errorw("failed to load link variants",
+			logx.Field("code", url.ShortCode),
+			logx.Field("error", findErr.Error()),
+		)
+		return nil
+	}
+
+	clicksByVariant := make(map[string]int64, len(variants))
+	variantResp, rpcErr := l.svcCtx.AnalyticsRpc.GetVariantClicks(l.ctx, &analyticsclient.GetVariantClicksRequest{
+		ShortCode: url.ShortCode,
 	})
 	if rpcErr != nil {
-		// Log but don't fail - degrade gracefully, return 0 clicks
-		logx.WithContext(l.ctx).Errorw("failed to get click count from analytics rpc, degrading gracefully",
-			logx.Field("code", req.Code),
+		logx.WithContext(l.ctx).Errorw("failed to get variant clicks from analytics rpc, degrading gracefully",
+			logx.Field("code", url.ShortCode),
 			logx.Field("error", rpcErr.Error()),
 		)
 	} else {
-		totalClicks = clickResp.TotalClicks
+		for _, vc := range variantResp.Variants {
+			clicksByVariant[vc.Variant] = vc.Clicks
+		}
 	}
 
-	return &types.LinkDetailResponse{
-		ShortCode:   url.ShortCode,
-		OriginalUrl: url.OriginalUrl,
-		CreatedAt:   url.CreatedAt.Unix(),
-		TotalClicks: totalClicks,
-	}, nil
+	items := make([]types.VariantItem, 0, len(variants))
+	for _, v := range variants {
+		items = append(items, types.VariantItem{
+			Label:          v.Label,
+			DestinationUrl: v.DestinationUrl,
+			Weight:         v.Weight,
+			Clicks:         clicksByVariant[v.Label],
+		})
+	}
+	return items
 }
@@ -2,11 +2,13 @@ package links
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
 
 	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
 	"go-shortener/services/url-api/internal/config"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
@@ -19,7 +21,13 @@ import (
 
 // MockAnalyticsClient is a test mock for Analytics interface
 type MockAnalyticsClient struct {
-	GetClickCountFunc func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error)
+	GetClickCountFunc       func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error)
+	GetVariantClicksFunc    func(ctx context.Context, in *analyticsclient.GetVariantClicksRequest, opts ...grpc.CallOption) (*analyticsclient.GetVariantClicksResponse, error)
+	GetAnalyticsSummaryFunc func(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error)
+	GetClickTimeSeriesFunc  func(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error)
+	GetTopLinksFunc         func(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error)
+	GetClickCountsFunc      func(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error)
+	GetClickRankingFunc     func(ctx context.Context, in *analyticsclient.GetClickRankingRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error)
 }
 
 func (m *MockAnalyticsClient) GetClickCount(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
@@ -29,6 +37,54 @@ func (m *MockAnalyticsClient) GetClickCount(ctx context.Context, in *analyticscl
 	panic("MockAnalyticsClient.GetClickCountFunc not set")
 }
 
+func (m *MockAnalyticsClient) GetVariantClicks(ctx context.Context, in *analyticsclient.GetVariantClicksRequest, opts ...grpc.CallOption) (*analyticsclient.GetVariantClicksResponse, error) {
+	if m.GetVariantClicksFunc != nil {
+		return m.GetVariantClicksFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetVariantClicksFunc not set")
+}
+
+func (m *MockAnalyticsClient) GetAnalyticsSummary(ctx context.Context, in *analyticsclient.GetAnalyticsSummaryRequest, opts ...grpc.CallOption) (*analyticsclient.GetAnalyticsSummaryResponse, error) {
+	if m.GetAnalyticsSummaryFunc != nil {
+		return m.GetAnalyticsSummaryFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetAnalyticsSummaryFunc not set")
+}
+
+func (m *MockAnalyticsClient) GetClickTimeSeries(ctx context.Context, in *analyticsclient.GetClickTimeSeriesRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickTimeSeriesResponse, error) {
+	if m.GetClickTimeSeriesFunc != nil {
+		return m.GetClickTimeSeriesFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetClickTimeSeriesFunc not set")
+}
+
+func (m *MockAnalyticsClient) GetTopLinks(ctx context.Context, in *analyticsclient.GetTopLinksRequest, opts ...grpc.CallOption) (*analyticsclient.GetTopLinksResponse, error) {
+	if m.GetTopLinksFunc != nil {
+		return m.GetTopLinksFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetTopLinksFunc not set")
+}
+
+func newClickCounter(t *testing.T, analytics analyticsclient.Analytics) *clickcount.Counter {
+	counter, err := clickcount.NewCounter(config.ClickCountConf{CacheLimit: 100, CacheExpiry: 60}, analytics)
+	require.NoError(t, err)
+	return counter
+}
+
+func (m *MockAnalyticsClient) GetClickCounts(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+	if m.GetClickCountsFunc != nil {
+		return m.GetClickCountsFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetClickCountsFunc not set")
+}
+
+func (m *MockAnalyticsClient) GetClickRanking(ctx context.Context, in *analyticsclient.GetClickRankingRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error) {
+	if m.GetClickRankingFunc != nil {
+		return m.GetClickRankingFunc(ctx, in, opts...)
+	}
+	panic("MockAnalyticsClient.GetClickRankingFunc not set")
+}
+
 func TestGetLinkDetailLogic_Success(t *testing.T) {
 	createdAt := time.Now().Add(-24 * time.Hour)
 
@@ -59,6 +115,7 @@ func TestGetLinkDetailLogic_Success(t *testing.T) {
 		Config:       config.Config{BaseUrl: "http://localhost:8080"},
 		UrlModel:     mockModel,
 		AnalyticsRpc: mockAnalytics,
+		ClickCounts:  newClickCounter(t, mockAnalytics),
 	}
 
 	logic := NewGetLinkDetailLogic(context.Background(), svcCtx)
@@ -70,6 +127,107 @@ func TestGetLinkDetailLogic_Success(t *testing.T) {
 	assert.Equal(t, "https://example.com", resp.OriginalUrl)
 	assert.Equal(t, createdAt.Unix(), resp.CreatedAt)
 	assert.Equal(t, int64(42), resp.TotalClicks)
+	assert.Equal(t, clickcount.StatusLive, resp.ClicksStatus)
+	assert.Zero(t, resp.ClicksAsOf)
+	assert.Equal(t, types.LinkHealth{Status: model.HealthUnchecked}, resp.Health)
+}
+
+func TestGetLinkDetailLogic_Health(t *testing.T) {
+	checkedAt := time.Now().Add(-time.Hour)
+
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:               "test-id",
+				ShortCode:        "abc12345",
+				OriginalUrl:      "https://example.com/gone",
+				CreatedAt:        time.Now(),
+				HealthStatusCode: 404,
+				HealthLatencyMs:  120,
+				HealthFailures:   1,
+				HealthCheckedAt:  sql.NullTime{Time: checkedAt, Valid: true},
+			}, nil
+		},
+	}
+
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+			return &analyticsclient.GetClickCountResponse{ShortCode: in.ShortCode}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+		ClickCounts:  newClickCounter(t, mockAnalytics),
+	}
+
+	logic := NewGetLinkDetailLogic(context.Background(), svcCtx)
+	resp, err := logic.GetLinkDetail(&types.LinkDetailRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, types.LinkHealth{
+		Status:     model.HealthBroken,
+		StatusCode: 404,
+		LatencyMs:  120,
+		CheckedAt:  checkedAt.Unix(),
+	}, resp.Health)
+}
+
+func TestGetLinkDetailLogic_WithVariants(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:          "test-id",
+				ShortCode:   "abc12345",
+				OriginalUrl: "https://example.com",
+				CreatedAt:   time.Now(),
+				Rotation:    model.RotationSticky,
+			}, nil
+		},
+	}
+
+	mockVariants := &model.MockUrlVariantsModel{
+		FindAllByShortCodeFunc: func(ctx context.Context, shortCode string) ([]*model.UrlVariants, error) {
+			assert.Equal(t, "abc12345", shortCode)
+			return []*model.UrlVariants{
+				{ShortCode: "abc12345", Label: "a", DestinationUrl: "https://example.com/a", Weight: 3},
+				{ShortCode: "abc12345", Label: "b", DestinationUrl: "https://example.com/b", Weight: 1},
+			}, nil
+		},
+	}
+
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+			return &analyticsclient.GetClickCountResponse{ShortCode: "abc12345", TotalClicks: 10}, nil
+		},
+		GetVariantClicksFunc: func(ctx context.Context, in *analyticsclient.GetVariantClicksRequest, opts ...grpc.CallOption) (*analyticsclient.GetVariantClicksResponse, error) {
+			assert.Equal(t, "abc12345", in.ShortCode)
+			return &analyticsclient.GetVariantClicksResponse{
+				ShortCode: "abc12345",
+				Variants:  []*analyticsclient.VariantClicks{{Variant: "a", Clicks: 7}},
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        mockModel,
+		UrlVariantModel: mockVariants,
+		AnalyticsRpc:    mockAnalytics,
+		ClickCounts:     newClickCounter(t, mockAnalytics),
+	}
+
+	logic := NewGetLinkDetailLogic(context.Background(), svcCtx)
+	resp, err := logic.GetLinkDetail(&types.LinkDetailRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, model.RotationSticky, resp.Rotation)
+	require.Len(t, resp.Variants, 2)
+	assert.Equal(t, types.VariantItem{Label: "a", DestinationUrl: "https://example.com/a", Weight: 3, Clicks: 7}, resp.Variants[0])
+	assert.Equal(t, types.VariantItem{Label: "b", DestinationUrl: "https://example.com/b", Weight: 1, Clicks: 0}, resp.Variants[1])
 }
 
 func TestGetLinkDetailLogic_NotFound(t *testing.T) {
@@ -117,17 +275,56 @@ func TestGetLinkDetailLogic_RPCFailure(t *testing.T) {
 		Config:       config.Config{BaseUrl: "http://localhost:8080"},
 		UrlModel:     mockModel,
 		AnalyticsRpc: mockAnalytics,
+		ClickCounts:  newClickCounter(t, mockAnalytics),
 	}
 
 	logic := NewGetLinkDetailLogic(context.Background(), svcCtx)
 	resp, err := logic.GetLinkDetail(&types.LinkDetailRequest{Code: "abc12345"})
 
-	// Should succeed with graceful degradation - returns 0 clicks
+	// Should succeed with graceful degradation, flagging the missing count
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, "abc12345", resp.ShortCode)
 	assert.Equal(t, "https://example.com", resp.OriginalUrl)
-	assert.Equal(t, int64(0), resp.TotalClicks, "should return 0 clicks on RPC failure")
+	assert.Equal(t, int64(0), resp.TotalClicks)
+	assert.Equal(t, clickcount.StatusUnavailable, resp.ClicksStatus, "0 clicks must not look like real data")
+}
+
+func TestGetLinkDetailLogic_StaleCount(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{ShortCode: "abc12345", OriginalUrl: "https://example.com", CreatedAt: time.Now()}, nil
+		},
+	}
+
+	var rpcErr error
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			return &analyticsclient.GetClickCountResponse{ShortCode: in.ShortCode, TotalClicks: 42}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+		ClickCounts:  newClickCounter(t, mockAnalytics),
+	}
+
+	logic := NewGetLinkDetailLogic(context.Background(), svcCtx)
+	_, err := logic.GetLinkDetail(&types.LinkDetailRequest{Code: "abc12345"})
+	require.NoError(t, err)
+
+	rpcErr = errors.New("analytics service unavailable")
+	resp, err := logic.GetLinkDetail(&types.LinkDetailRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), resp.TotalClicks, "the last known count is served")
+	assert.Equal(t, clickcount.StatusStale, resp.ClicksStatus)
+	assert.NotZero(t, resp.ClicksAsOf)
 }
 
 func TestGetLinkDetailLogic_DBError(t *testing.T) {
@@ -5,15 +5,27 @@ package links
 
 import (
 	"context"
+	"fmt"
 	"math"
 
 	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
+const (
+	// sortClicks orders links by their total clicks.
+	sortClicks = "clicks"
+	// clickWalkChunk is how many links listByClicks reads at a time while
+	// walking to the requested page.
+	clickWalkChunk = 500
+)
+
 type ListLinksLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -35,14 +47,251 @@ func (l *ListLinksLogic) ListLinks(req *types.LinkListRequest) (resp *types.Link
 		logx.Field("per_page", req.PerPage),
 	)
 
+	if req.Cursor != "" {
+		return l.listByCursor(req)
+	}
+	if req.Sort == sortClicks {
+		return l.listByClicks(req)
+	}
+
 	urls, totalCount, queryErr := l.svcCtx.UrlModel.ListWithPagination(
-		l.ctx, req.Page, req.PerPage, req.Search, req.Sort, req.Order,
+		l.ctx, req.Page, req.PerPage, req.Search, req.Sort, req.Order, req.Health,
 	)
 	if queryErr != nil {
 		logx.WithContext(l.ctx).Errorw("failed to list URLs", logx.Field("error", queryErr.Error()))
 		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to list links")
 	}
 
+	resp = newLinkListResponse(req, urls, l.clickCounts(urls), totalCount)
+	// Cursors let clients switch to keyset pagination from any page.
+	if len(urls) > 0 {
+		if req.Page > 1 {
+			resp.PrevCursor = newListCursor(req.Sort, req.Order, urls[0], true).encode()
+		}
+		if int64(req.Page*req.PerPage) < totalCount {
+			resp.NextCursor = newListCursor(req.Sort, req.Order, urls[len(urls)-1], false).encode()
+		}
+	}
+	return resp, nil
+}
+
+// listByCursor lists the page after, or before, the link a cursor points at,
+// or the first page when the cursor is cursorStart. It skips counting the
+// matching links, so the response carries no page number or totals.
+func (l *ListLinksLogic) listByCursor(req *types.LinkListRequest) (*types.LinkListResponse, error) {
+	var key *model.ListKey
+	var backward bool
+	start := req.Cursor == cursorStart
+	if start {
+		if req.Sort == sortClicks {
+			return nil, problemdetails.New(400, problemdetails.TypeValidationError, "Bad Request",
+				"cursor pagination does not support sort=clicks")
+		}
+	} else {
+		cursor, err := decodeListCursor(req.Cursor)
+		if err != nil {
+			return nil, problemdetails.New(400, problemdetails.TypeValidationError, "Bad Request", "cursor is invalid")
+		}
+		if cursor.Sort != req.Sort || cursor.Order != req.Order {
+			return nil, problemdetails.New(400, problemdetails.TypeValidationError, "Bad Request",
+				fmt.Sprintf("cursor was issued for sort=%s and order=%s", cursor.Sort, cursor.Order))
+		}
+		key, backward = cursor.key(), cursor.Backward
+	}
+
+	// One extra row tells whether another page follows in walking direction.
+	urls, err := l.svcCtx.UrlModel.ListWithKeyset(l.ctx, key, backward, req.PerPage+1,
+		req.Search, req.Sort, req.Order, req.Health)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to list URLs", logx.Field("error", err.Error()))
+		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to list links")
+	}
+	more := len(urls) > req.PerPage
+	if more {
+		if backward {
+			urls = urls[1:]
+		} else {
+			urls = urls[:req.PerPage]
+		}
+	}
+
+	resp := newLinkListResponse(req, urls, l.clickCounts(urls), 0)
+	resp.Page = 0
+	if len(urls) > 0 {
+		// The cursor link lies on the other side of the page; the first page
+		// has nothing before it.
+		if more || backward {
+			resp.NextCursor = newListCursor(req.Sort, req.Order, urls[len(urls)-1], false).encode()
+		}
+		if !start && (more || !backward) {
+			resp.PrevCursor = newListCursor(req.Sort, req.Order, urls[0], true).encode()
+		}
+	}
+	return resp, nil
+}
+
+// clickCounts reads the click counts of urls. While analytics-rpc is
+// unavailable and the counts are unknown, they are reported unavailable
+// and the links show 0 clicks.
+func (l *ListLinksLogic) clickCounts(urls []*model.Urls) clickcount.Counts {
+	codes := make([]string, 0, len(urls))
+	for _, u := range urls {
+		codes = append(codes, u.ShortCode)
+	}
+	counts, err := l.svcCtx.ClickCounts.Counts(l.ctx, codes)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get click counts from analytics rpc, degrading gracefully",
+			logx.Field("error", err.Error()),
+		)
+		counts.Status = clickcount.StatusUnavailable
+	}
+	return counts
+}
+
+// linkWalk yields the next chunk of a part of the links list in order, with
+// the click count of each link, and reports whether the part is exhausted.
+type linkWalk func() (urls []*model.Urls, clicks []int64, done bool, err error)
+
+// listByClicks orders the matching links by their click counts, which live in
+// analytics-rpc. Links with clicks follow the click ranking; links without
+// any come first in ascending order and last in descending order, newest
+// first. Both parts are walked by keyset only as far as the requested page.
+// Without the counts the order is unknown, so it fails rather than degrading.
+func (l *ListLinksLogic) listByClicks(req *types.LinkListRequest) (*types.LinkListResponse, error) {
+	totalCount, err := l.svcCtx.UrlModel.CountListed(l.ctx, req.Search, req.Health)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to count URLs", logx.Field("error", err.Error()))
+		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to list links")
+	}
+
+	walks := []linkWalk{l.rankedLinks(req), l.unclickedLinks(req)}
+	if req.Order == "asc" {
+		walks[0], walks[1] = walks[1], walks[0]
+	}
+
+	skip := (req.Page - 1) * req.PerPage
+	urls := make([]*model.Urls, 0, req.PerPage)
+	counts := clickcount.Counts{Clicks: make(map[string]int64, req.PerPage), Status: clickcount.StatusLive}
+	for _, walk := range walks {
+		for done := false; !done && len(urls) < req.PerPage; {
+			var chunk []*model.Urls
+			var clicks []int64
+			chunk, clicks, done, err = walk()
+			if err != nil {
+				return nil, err
+			}
+			for i, u := range chunk {
+				switch {
+				case skip > 0:
+					skip--
+				case len(urls) < req.PerPage:
+					urls = append(urls, u)
+					counts.Clicks[u.ShortCode] = clicks[i]
+				}
+			}
+		}
+	}
+
+	return newLinkListResponse(req, urls, counts, totalCount), nil
+}
+
+// rankedLinks walks the matching links with clicks in click order. Clicks
+// outlive deleted links, and links filtered out take no place.
+func (l *ListLinksLogic) rankedLinks(req *types.LinkListRequest) linkWalk {
+	var after *analyticsclient.LinkClicks
+	return func() ([]*model.Urls, []int64, bool, error) {
+		resp, err := l.svcCtx.AnalyticsRpc.GetClickRanking(l.ctx, &analyticsclient.GetClickRankingRequest{
+			Limit:     clickWalkChunk,
+			Ascending: req.Order == "asc",
+			After:     after,
+		})
+		if err != nil {
+			logx.WithContext(l.ctx).Errorw("failed to get click ranking from analytics rpc",
+				logx.Field("error", err.Error()),
+			)
+			return nil, nil, false, clicksUnavailable()
+		}
+		if len(resp.Links) > 0 {
+			after = resp.Links[len(resp.Links)-1]
+		}
+
+		codes := make([]string, 0, len(resp.Links))
+		for _, link := range resp.Links {
+			codes = append(codes, link.ShortCode)
+		}
+		found, err := l.svcCtx.UrlModel.FindListedByShortCodes(l.ctx, codes, req.Search, req.Health)
+		if err != nil {
+			logx.WithContext(l.ctx).Errorw("failed to find URLs", logx.Field("error", err.Error()))
+			return nil, nil, false, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to list links")
+		}
+		byCode := make(map[string]*model.Urls, len(found))
+		for _, u := range found {
+			byCode[u.ShortCode] = u
+		}
+
+		urls := make([]*model.Urls, 0, len(found))
+		clicks := make([]int64, 0, len(found))
+		for _, link := range resp.Links {
+			if u, ok := byCode[link.ShortCode]; ok {
+				urls = append(urls, u)
+				clicks = append(clicks, link.Clicks)
+			}
+		}
+		return urls, clicks, len(resp.Links) < clickWalkChunk, nil
+	}
+}
+
+// unclickedLinks walks the matching links without clicks, newest first.
+func (l *ListLinksLogic) unclickedLinks(req *types.LinkListRequest) linkWalk {
+	var after *model.ListKey
+	return func() ([]*model.Urls, []int64, bool, error) {
+		batch, err := l.svcCtx.UrlModel.ListWithKeyset(l.ctx, after, false, clickWalkChunk,
+			req.Search, sortCreatedAt, "desc", req.Health)
+		if err != nil {
+			logx.WithContext(l.ctx).Errorw("failed to list URLs", logx.Field("error", err.Error()))
+			return nil, nil, false, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to list links")
+		}
+		done := len(batch) < clickWalkChunk
+		if len(batch) == 0 {
+			return nil, nil, done, nil
+		}
+		last := batch[len(batch)-1]
+		after = &model.ListKey{CreatedAt: last.CreatedAt, Id: last.Id}
+
+		codes := make([]string, 0, len(batch))
+		for _, u := range batch {
+			codes = append(codes, u.ShortCode)
+		}
+		resp, err := l.svcCtx.AnalyticsRpc.GetClickCounts(l.ctx, &analyticsclient.GetClickCountsRequest{
+			ShortCodes: codes,
+		})
+		if err != nil {
+			logx.WithContext(l.ctx).Errorw("failed to get click counts from analytics rpc",
+				logx.Field("error", err.Error()),
+			)
+			return nil, nil, false, clicksUnavailable()
+		}
+		clicked := make(map[string]bool, len(resp.Links))
+		for _, link := range resp.Links {
+			clicked[link.ShortCode] = link.Clicks > 0
+		}
+
+		urls := make([]*model.Urls, 0, len(batch))
+		for _, u := range batch {
+			if !clicked[u.ShortCode] {
+				urls = append(urls, u)
+			}
+		}
+		return urls, make([]int64, len(urls)), done, nil
+	}
+}
+
+func clicksUnavailable() error {
+	return problemdetails.New(503, problemdetails.TypeServiceUnavailable, "Service Unavailable",
+		"click counts are temporarily unavailable, cannot sort by clicks")
+}
+
+func newLinkListResponse(req *types.LinkListRequest, urls []*model.Urls, counts clickcount.Counts, totalCount int64) *types.LinkListResponse {
 	// Map model results to response types
 	linkItems := make([]types.LinkItem, 0, len(urls))
 	for _, u := range urls {
@@ -50,16 +299,20 @@ func (l *ListLinksLogic) ListLinks(req *types.LinkListRequest) (resp *types.Link
 			ShortCode:   u.ShortCode,
 			OriginalUrl: u.OriginalUrl,
 			CreatedAt:   u.CreatedAt.Unix(),
+			PageTitle:   u.PageTitle,
+			FaviconUrl:  u.FaviconUrl,
+			TotalClicks: counts.Clicks[u.ShortCode],
 		})
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(req.PerPage)))
 
 	return &types.LinkListResponse{
-		Links:      linkItems,
-		Page:       req.Page,
-		PerPage:    req.PerPage,
-		TotalPages: totalPages,
-		TotalCount: totalCount,
-	}, nil
+		Links:        linkItems,
+		Page:         req.Page,
+		PerPage:      req.PerPage,
+		TotalPages:   totalPages,
+		TotalCount:   totalCount,
+		ClicksStatus: counts.Status,
+	}
 }
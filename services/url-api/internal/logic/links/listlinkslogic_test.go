@@ -3,9 +3,14 @@ package links
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
 	"go-shortener/services/url-api/internal/config"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
@@ -13,14 +18,29 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
+// clickCountsOf returns a Counter reading the counts in clicks, 0 for other
+// short codes.
+func clickCountsOf(t *testing.T, clicks map[string]int64) *clickcount.Counter {
+	return newClickCounter(t, &MockAnalyticsClient{
+		GetClickCountsFunc: func(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+			links := make([]*analyticsclient.LinkClicks, 0, len(in.ShortCodes))
+			for _, code := range in.ShortCodes {
+				links = append(links, &analyticsclient.LinkClicks{ShortCode: code, Clicks: clicks[code]})
+			}
+			return &analyticsclient.GetClickCountsResponse{Links: links}, nil
+		},
+	})
+}
+
 func TestListLinksLogic_Success(t *testing.T) {
 	createdAt1 := time.Now().Add(-48 * time.Hour)
 	createdAt2 := time.Now().Add(-24 * time.Hour)
 
 	mockModel := &model.MockUrlsModel{
-		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*model.Urls, int64, error) {
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
 			assert.Equal(t, 1, page)
 			assert.Equal(t, 10, pageSize)
 			assert.Equal(t, "", search)
@@ -47,8 +67,9 @@ func TestListLinksLogic_Success(t *testing.T) {
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, map[string]int64{"abc12345": 42}),
 	}
 
 	logic := NewListLinksLogic(context.Background(), svcCtx)
@@ -63,6 +84,9 @@ func TestListLinksLogic_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	assert.Equal(t, 2, len(resp.Links))
+	assert.Equal(t, int64(42), resp.Links[0].TotalClicks)
+	assert.Equal(t, int64(0), resp.Links[1].TotalClicks)
+	assert.Equal(t, clickcount.StatusLive, resp.ClicksStatus)
 	assert.Equal(t, "abc12345", resp.Links[0].ShortCode)
 	assert.Equal(t, "https://example.com", resp.Links[0].OriginalUrl)
 	assert.Equal(t, createdAt1.Unix(), resp.Links[0].CreatedAt)
@@ -75,14 +99,15 @@ func TestListLinksLogic_Success(t *testing.T) {
 
 func TestListLinksLogic_Empty(t *testing.T) {
 	mockModel := &model.MockUrlsModel{
-		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*model.Urls, int64, error) {
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
 			return []*model.Urls{}, 0, nil
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
 	}
 
 	logic := NewListLinksLogic(context.Background(), svcCtx)
@@ -105,7 +130,7 @@ func TestListLinksLogic_WithSearch(t *testing.T) {
 	createdAt := time.Now()
 
 	mockModel := &model.MockUrlsModel{
-		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*model.Urls, int64, error) {
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
 			assert.Equal(t, "example", search)
 			return []*model.Urls{
 				{
@@ -120,8 +145,9 @@ func TestListLinksLogic_WithSearch(t *testing.T) {
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
 	}
 
 	logic := NewListLinksLogic(context.Background(), svcCtx)
@@ -139,16 +165,45 @@ func TestListLinksLogic_WithSearch(t *testing.T) {
 	assert.Equal(t, int64(1), resp.TotalCount)
 }
 
+func TestListLinksLogic_BrokenFilter(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
+			assert.Equal(t, model.HealthBroken, health)
+			return []*model.Urls{}, 0, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 10,
+		Sort:    "created_at",
+		Order:   "desc",
+		Health:  "broken",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Empty(t, resp.Links)
+}
+
 func TestListLinksLogic_DBError(t *testing.T) {
 	mockModel := &model.MockUrlsModel{
-		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*model.Urls, int64, error) {
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
 			return nil, 0, errors.New("database connection error")
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
 	}
 
 	logic := NewListLinksLogic(context.Background(), svcCtx)
@@ -167,15 +222,16 @@ func TestListLinksLogic_DBError(t *testing.T) {
 
 func TestListLinksLogic_Pagination(t *testing.T) {
 	mockModel := &model.MockUrlsModel{
-		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*model.Urls, int64, error) {
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
 			// Simulate 25 total items with page size 10
 			return []*model.Urls{}, 25, nil
 		},
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
 	}
 
 	logic := NewListLinksLogic(context.Background(), svcCtx)
@@ -193,3 +249,479 @@ func TestListLinksLogic_Pagination(t *testing.T) {
 	assert.Equal(t, 3, resp.TotalPages, "25 items / 10 per page = 3 pages")
 	assert.Equal(t, 2, resp.Page)
 }
+
+func TestListLinksLogic_ClickCountsUnavailable(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
+			return []*model.Urls{{Id: "id-1", ShortCode: "abc12345", OriginalUrl: "https://example.com"}}, 1, nil
+		},
+	}
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickCountsFunc: func(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: newClickCounter(t, mockAnalytics),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 10,
+		Sort:    "created_at",
+		Order:   "desc",
+	})
+
+	require.NoError(t, err, "the list degrades without click counts")
+	require.Len(t, resp.Links, 1)
+	assert.Equal(t, int64(0), resp.Links[0].TotalClicks)
+	assert.Equal(t, clickcount.StatusUnavailable, resp.ClicksStatus)
+}
+
+// clickSortFixture serves the links list sorted by clicks from links, whose
+// counts in clicks rank them; links missing from clicks have none.
+func clickSortFixture(t *testing.T, links []*model.Urls, clicks map[string]int64) *svc.ServiceContext {
+	t.Helper()
+	byCode := make(map[string]*model.Urls, len(links))
+	for _, u := range links {
+		byCode[u.ShortCode] = u
+	}
+
+	mockModel := &model.MockUrlsModel{
+		CountListedFunc: func(ctx context.Context, search, health string) (int64, error) {
+			return int64(len(links)), nil
+		},
+		FindListedByShortCodesFunc: func(ctx context.Context, shortCodes []string, search, health string) ([]*model.Urls, error) {
+			var found []*model.Urls
+			for _, code := range shortCodes {
+				if u, ok := byCode[code]; ok {
+					found = append(found, u)
+				}
+			}
+			return found, nil
+		},
+		ListWithKeysetFunc: func(ctx context.Context, key *model.ListKey, backward bool, limit int, search, sort, order, health string) ([]*model.Urls, error) {
+			assert.Nil(t, key, "all links fit one chunk")
+			assert.Equal(t, "created_at", sort)
+			assert.Equal(t, "desc", order)
+			// links are newest first
+			return links, nil
+		},
+	}
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickRankingFunc: func(ctx context.Context, in *analyticsclient.GetClickRankingRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error) {
+			assert.Nil(t, in.After, "all ranked links fit one chunk")
+			ranked := make([]*analyticsclient.LinkClicks, 0, len(clicks))
+			for code, n := range clicks {
+				ranked = append(ranked, &analyticsclient.LinkClicks{ShortCode: code, Clicks: n})
+			}
+			sort.Slice(ranked, func(i, j int) bool {
+				if in.Ascending {
+					return ranked[i].Clicks < ranked[j].Clicks
+				}
+				return ranked[i].Clicks > ranked[j].Clicks
+			})
+			return &analyticsclient.GetClickRankingResponse{Links: ranked}, nil
+		},
+		GetClickCountsFunc: func(ctx context.Context, in *analyticsclient.GetClickCountsRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountsResponse, error) {
+			resp := &analyticsclient.GetClickCountsResponse{}
+			for _, code := range in.ShortCodes {
+				resp.Links = append(resp.Links, &analyticsclient.LinkClicks{ShortCode: code, Clicks: clicks[code]})
+			}
+			return resp, nil
+		},
+	}
+
+	return &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+	}
+}
+
+func listedCodes(resp *types.LinkListResponse) []string {
+	codes := make([]string, 0, len(resp.Links))
+	for _, link := range resp.Links {
+		codes = append(codes, link.ShortCode)
+	}
+	return codes
+}
+
+func TestListLinksLogic_SortByClicks(t *testing.T) {
+	now := time.Now()
+	links := []*model.Urls{
+		{Id: "id-1", ShortCode: "new", CreatedAt: now},
+		{Id: "id-2", ShortCode: "top", CreatedAt: now.Add(-time.Hour)},
+		{Id: "id-3", ShortCode: "mid", CreatedAt: now.Add(-2 * time.Hour)},
+		{Id: "id-4", ShortCode: "old", CreatedAt: now.Add(-3 * time.Hour)},
+		{Id: "id-5", ShortCode: "low", CreatedAt: now.Add(-4 * time.Hour)},
+	}
+	// "gone" was deleted, so it takes no place
+	svcCtx := clickSortFixture(t, links, map[string]int64{"top": 9, "gone": 7, "mid": 5, "low": 1})
+
+	tests := []struct {
+		name  string
+		order string
+		page  int
+		want  []string
+	}{
+		{name: "desc", order: "desc", page: 1, want: []string{"top", "mid", "low"}},
+		{name: "desc into unclicked", order: "desc", page: 2, want: []string{"new", "old"}},
+		{name: "asc unclicked first", order: "asc", page: 1, want: []string{"new", "old", "low"}},
+		{name: "asc into ranked", order: "asc", page: 2, want: []string{"mid", "top"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewListLinksLogic(context.Background(), svcCtx).ListLinks(&types.LinkListRequest{
+				Page:    tt.page,
+				PerPage: 3,
+				Sort:    "clicks",
+				Order:   tt.order,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, listedCodes(resp))
+			assert.Equal(t, int64(5), resp.TotalCount)
+			assert.Equal(t, 2, resp.TotalPages)
+			assert.Equal(t, clickcount.StatusLive, resp.ClicksStatus)
+		})
+	}
+}
+
+func TestListLinksLogic_SortByClicksCounts(t *testing.T) {
+	now := time.Now()
+	links := []*model.Urls{
+		{Id: "id-1", ShortCode: "new", CreatedAt: now},
+		{Id: "id-2", ShortCode: "top", CreatedAt: now.Add(-time.Hour)},
+	}
+	svcCtx := clickSortFixture(t, links, map[string]int64{"top": 9})
+
+	resp, err := NewListLinksLogic(context.Background(), svcCtx).ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 10,
+		Sort:    "clicks",
+		Order:   "asc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, int64(0), resp.Links[0].TotalClicks)
+	assert.Equal(t, int64(9), resp.Links[1].TotalClicks)
+}
+
+func TestListLinksLogic_SortByClicksWalksRankingByKeyset(t *testing.T) {
+	var afters []*analyticsclient.LinkClicks
+	mockModel := &model.MockUrlsModel{
+		CountListedFunc: func(ctx context.Context, search, health string) (int64, error) {
+			return clickWalkChunk + 1, nil
+		},
+		FindListedByShortCodesFunc: func(ctx context.Context, shortCodes []string, search, health string) ([]*model.Urls, error) {
+			assert.Equal(t, "https://", search)
+			found := make([]*model.Urls, 0, len(shortCodes))
+			for _, code := range shortCodes {
+				found = append(found, &model.Urls{ShortCode: code})
+			}
+			return found, nil
+		},
+	}
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickRankingFunc: func(ctx context.Context, in *analyticsclient.GetClickRankingRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error) {
+			afters = append(afters, in.After)
+			start := 0
+			if in.After != nil {
+				start = clickWalkChunk
+			}
+			links := make([]*analyticsclient.LinkClicks, 0, clickWalkChunk)
+			for i := start; i < start+clickWalkChunk; i++ {
+				links = append(links, &analyticsclient.LinkClicks{ShortCode: fmt.Sprintf("c%04d", i), Clicks: int64(10000 - i)})
+			}
+			return &analyticsclient.GetClickRankingResponse{Links: links}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	resp, err := NewListLinksLogic(context.Background(), svcCtx).ListLinks(&types.LinkListRequest{
+		Page:    51,
+		PerPage: 10,
+		Search:  "https://",
+		Sort:    "clicks",
+		Order:   "desc",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, afters, 2, "the walk stops once the page is full")
+	assert.Nil(t, afters[0])
+	assert.Equal(t, "c0499", afters[1].ShortCode, "the next chunk continues after the last ranked link")
+	require.Len(t, resp.Links, 10)
+	assert.Equal(t, "c0500", resp.Links[0].ShortCode)
+	assert.Equal(t, int64(9500), resp.Links[0].TotalClicks)
+}
+
+func TestListLinksLogic_SortByClicksUnavailable(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		CountListedFunc: func(ctx context.Context, search, health string) (int64, error) {
+			return 1, nil
+		},
+	}
+	mockAnalytics := &MockAnalyticsClient{
+		GetClickRankingFunc: func(ctx context.Context, in *analyticsclient.GetClickRankingRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickRankingResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:       config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:     mockModel,
+		AnalyticsRpc: mockAnalytics,
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 10,
+		Sort:    "clicks",
+		Order:   "desc",
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	var pd *problemdetails.ProblemDetail
+	require.ErrorAs(t, err, &pd)
+	assert.Equal(t, 503, pd.Status)
+}
+
+// keysetUrls returns n links created a minute apart, newest first.
+func keysetUrls(n int) []*model.Urls {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	urls := make([]*model.Urls, 0, n)
+	for i := 0; i < n; i++ {
+		urls = append(urls, &model.Urls{
+			Id:          fmt.Sprintf("0190a5c8-0000-7000-8000-%012d", i),
+			ShortCode:   fmt.Sprintf("code%04d", i),
+			OriginalUrl: fmt.Sprintf("https://example.com/%d", i),
+			CreatedAt:   now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	return urls
+}
+
+func TestListLinksLogic_PageCursors(t *testing.T) {
+	urls := keysetUrls(3)
+	mockModel := &model.MockUrlsModel{
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
+			return urls[1:2], 3, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    2,
+		PerPage: 1,
+		Sort:    "created_at",
+		Order:   "desc",
+	})
+
+	require.NoError(t, err)
+	next, err := decodeListCursor(resp.NextCursor)
+	require.NoError(t, err)
+	assert.False(t, next.Backward)
+	assert.Equal(t, urls[1].Id, next.Id)
+	prev, err := decodeListCursor(resp.PrevCursor)
+	require.NoError(t, err)
+	assert.True(t, prev.Backward)
+	assert.Equal(t, urls[1].Id, prev.Id)
+}
+
+func TestListLinksLogic_NoCursorsAtEnds(t *testing.T) {
+	urls := keysetUrls(2)
+	mockModel := &model.MockUrlsModel{
+		ListWithPaginationFunc: func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*model.Urls, int64, error) {
+			return urls, 2, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 10,
+		Sort:    "created_at",
+		Order:   "desc",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.NextCursor)
+	assert.Empty(t, resp.PrevCursor)
+}
+
+func TestListLinksLogic_NextCursor(t *testing.T) {
+	urls := keysetUrls(5)
+	cursor := newListCursor("original_url", "asc", urls[0], false).encode()
+
+	mockModel := &model.MockUrlsModel{
+		ListWithKeysetFunc: func(ctx context.Context, key *model.ListKey, backward bool, limit int, search, sort, order, health string) ([]*model.Urls, error) {
+			assert.Equal(t, &model.ListKey{OriginalUrl: urls[0].OriginalUrl, Id: urls[0].Id}, key)
+			assert.False(t, backward)
+			assert.Equal(t, 3, limit, "one more than a page")
+			assert.Equal(t, "original_url", sort)
+			assert.Equal(t, "asc", order)
+			assert.Equal(t, model.HealthBroken, health)
+			return urls[1:4], nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, map[string]int64{"code0001": 4}),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 2,
+		Sort:    "original_url",
+		Order:   "asc",
+		Health:  "broken",
+		Cursor:  cursor,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, "code0001", resp.Links[0].ShortCode)
+	assert.Equal(t, int64(4), resp.Links[0].TotalClicks)
+	assert.Equal(t, "code0002", resp.Links[1].ShortCode)
+	assert.Equal(t, 0, resp.Page, "cursor pages have no number")
+	assert.Equal(t, int64(0), resp.TotalCount, "cursor pages skip counting")
+
+	next, err := decodeListCursor(resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, urls[2].Id, next.Id)
+	prev, err := decodeListCursor(resp.PrevCursor)
+	require.NoError(t, err)
+	assert.Equal(t, urls[1].Id, prev.Id)
+	assert.True(t, prev.Backward)
+}
+
+func TestListLinksLogic_StartCursor(t *testing.T) {
+	urls := keysetUrls(3)
+
+	mockModel := &model.MockUrlsModel{
+		ListWithKeysetFunc: func(ctx context.Context, key *model.ListKey, backward bool, limit int, search, sort, order, health string) ([]*model.Urls, error) {
+			assert.Nil(t, key, "the first page starts at the beginning")
+			assert.False(t, backward)
+			assert.Equal(t, 3, limit)
+			assert.Equal(t, "created_at", sort)
+			assert.Equal(t, "desc", order)
+			return urls, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 2,
+		Sort:    "created_at",
+		Order:   "desc",
+		Cursor:  "start",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, 0, resp.Page, "cursor pages have no number")
+	assert.Equal(t, int64(0), resp.TotalCount, "cursor pages skip counting")
+	assert.Empty(t, resp.PrevCursor, "the first page has nothing before it")
+	next, err := decodeListCursor(resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, urls[1].Id, next.Id)
+	assert.False(t, next.Backward)
+}
+
+func TestListLinksLogic_PrevCursorToFirstPage(t *testing.T) {
+	urls := keysetUrls(3)
+	cursor := newListCursor("created_at", "desc", urls[2], true).encode()
+
+	mockModel := &model.MockUrlsModel{
+		ListWithKeysetFunc: func(ctx context.Context, key *model.ListKey, backward bool, limit int, search, sort, order, health string) ([]*model.Urls, error) {
+			assert.Equal(t, urls[2].CreatedAt, key.CreatedAt)
+			assert.True(t, backward)
+			return urls[:2], nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: clickCountsOf(t, nil),
+	}
+
+	logic := NewListLinksLogic(context.Background(), svcCtx)
+	resp, err := logic.ListLinks(&types.LinkListRequest{
+		Page:    1,
+		PerPage: 2,
+		Sort:    "created_at",
+		Order:   "desc",
+		Cursor:  cursor,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, "code0000", resp.Links[0].ShortCode)
+	assert.Empty(t, resp.PrevCursor, "the first page has nothing before it")
+	next, err := decodeListCursor(resp.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, urls[1].Id, next.Id)
+}
+
+func TestListLinksLogic_CursorRejected(t *testing.T) {
+	cursor := newListCursor("created_at", "desc", keysetUrls(1)[0], false).encode()
+	tests := map[string]*types.LinkListRequest{
+		"invalid":       {Page: 1, PerPage: 10, Sort: "created_at", Order: "desc", Cursor: "garbage"},
+		"other sort":    {Page: 1, PerPage: 10, Sort: "original_url", Order: "desc", Cursor: cursor},
+		"other order":   {Page: 1, PerPage: 10, Sort: "created_at", Order: "asc", Cursor: cursor},
+		"sorted clicks": {Page: 1, PerPage: 10, Sort: "clicks", Order: "desc", Cursor: cursor},
+		"start clicks":  {Page: 1, PerPage: 10, Sort: "clicks", Order: "desc", Cursor: "start"},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			svcCtx := &svc.ServiceContext{
+				Config:      config.Config{BaseUrl: "http://localhost:8080"},
+				UrlModel:    &model.MockUrlsModel{},
+				ClickCounts: clickCountsOf(t, nil),
+			}
+
+			resp, err := NewListLinksLogic(context.Background(), svcCtx).ListLinks(req)
+
+			assert.Nil(t, resp)
+			var pd *problemdetails.ProblemDetail
+			require.ErrorAs(t, err, &pd)
+			assert.Equal(t, 400, pd.Status)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/url-api/internal/clickcount"
+	"go-shortener/services/url-api/internal/pages"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PreviewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// Render a preview page for a short link
+func NewPreviewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PreviewLogic {
+	return &PreviewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Preview looks up the short code and returns the data for the preview page
+// without redirecting. Previews are not clicks, so no ClickEvent is published.
+func (l *PreviewLogic) Preview(req *types.RedirectRequest) (*pages.Preview, error) {
+	logx.WithContext(l.ctx).Infow("preview", logx.Field("code", req.Code))
+
+	url, err := l.svcCtx.UrlModel.FindOneByShortCode(l.ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, problemdetails.New(http.StatusNotFound, problemdetails.TypeNotFound, "Not Found",
+				"short code '"+req.Code+"' not found")
+		}
+		logx.WithContext(l.ctx).Errorw("failed to find URL", logx.Field("error", err.Error()))
+		return nil, problemdetails.New(http.StatusInternalServerError, problemdetails.TypeInternalError, "Internal Error",
+			"failed to look up short code")
+	}
+
+	count, err := l.svcCtx.ClickCounts.Count(l.ctx, url.ShortCode)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to get click count from analytics rpc, degrading gracefully",
+			logx.Field("code", url.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+	}
+
+	return newPreview(url, url.OriginalUrl, 0, count, err == nil), nil
+}
+
+// newPreview builds the preview page for a link. Without a known click count,
+// the count is shown as unavailable rather than 0.
+func newPreview(url *model.Urls, destination string, countdown int, count clickcount.Count, counted bool) *pages.Preview {
+	// The scraped page title describes original_url only, not A/B variants
+	title := url.Title
+	if title == "" && destination == url.OriginalUrl {
+		title = url.PageTitle
+	}
+
+	return &pages.Preview{
+		ShortCode:         url.ShortCode,
+		Destination:       destination,
+		Title:             title,
+		TotalClicks:       count.Clicks,
+		ClicksUnavailable: !counted,
+		Countdown:         countdown,
+	}
+}
@@ -0,0 +1,147 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/internal/types"
+	"go-shortener/services/url-api/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockAnalyticsClient is a test mock for the Analytics RPC client.
+type mockAnalyticsClient struct {
+	analyticsclient.Analytics
+	getClickCountFunc func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error)
+}
+
+func (m *mockAnalyticsClient) GetClickCount(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+	return m.getClickCountFunc(ctx, in, opts...)
+}
+
+func clickCount(total int64) *mockAnalyticsClient {
+	return &mockAnalyticsClient{
+		getClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+			return &analyticsclient.GetClickCountResponse{ShortCode: in.ShortCode, TotalClicks: total}, nil
+		},
+	}
+}
+
+func newClickCounter(t *testing.T, analytics analyticsclient.Analytics) *clickcount.Counter {
+	counter, err := clickcount.NewCounter(config.ClickCountConf{CacheLimit: 100, CacheExpiry: 60}, analytics)
+	require.NoError(t, err)
+	return counter
+}
+
+func TestPreviewLogic_Success(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			assert.Equal(t, "abc12345", shortCode)
+			return &model.Urls{
+				Id:          "test-id",
+				ShortCode:   "abc12345",
+				OriginalUrl: "https://example.com",
+				Title:       "Example",
+				CreatedAt:   time.Now(),
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080", InterstitialCountdown: 5},
+		UrlModel:    mockModel,
+		ClickCounts: newClickCounter(t, clickCount(42)),
+	}
+
+	logic := NewPreviewLogic(context.Background(), svcCtx)
+	page, err := logic.Preview(&types.RedirectRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	require.NotNil(t, page)
+	assert.Equal(t, "abc12345", page.ShortCode)
+	assert.Equal(t, "https://example.com", page.Destination)
+	assert.Equal(t, "Example", page.Title)
+	assert.Equal(t, int64(42), page.TotalClicks)
+	assert.False(t, page.ClicksUnavailable)
+	assert.Equal(t, 0, page.Countdown, "explicit preview should not auto-redirect")
+}
+
+func TestPreviewLogic_PageTitleFallback(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:          "test-id",
+				ShortCode:   "abc12345",
+				OriginalUrl: "https://example.com",
+				PageTitle:   "Example Domain",
+				CreatedAt:   time.Now(),
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickCounts: newClickCounter(t, clickCount(0)),
+	}
+
+	logic := NewPreviewLogic(context.Background(), svcCtx)
+	page, err := logic.Preview(&types.RedirectRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Example Domain", page.Title)
+}
+
+func TestPreviewLogic_NotFound(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return nil, model.ErrNotFound
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewPreviewLogic(context.Background(), svcCtx)
+	page, err := logic.Preview(&types.RedirectRequest{Code: "notfound"})
+
+	require.Error(t, err)
+	assert.Nil(t, page)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestPreviewLogic_RPCFailure(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{ShortCode: "abc12345", OriginalUrl: "https://example.com"}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+		ClickCounts: newClickCounter(t, &mockAnalyticsClient{
+			getClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+				return nil, errors.New("analytics service unavailable")
+			},
+		}),
+	}
+
+	logic := NewPreviewLogic(context.Background(), svcCtx)
+	page, err := logic.Preview(&types.RedirectRequest{Code: "abc12345"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), page.TotalClicks)
+	assert.True(t, page.ClicksUnavailable, "an unknown count must not be shown as 0 clicks")
+}
@@ -5,8 +5,9 @@ package redirect
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"hash/fnv"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"strings"
@@ -14,14 +15,54 @@ import (
 
 	"go-shortener/common/events"
 	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/url-api/internal/pages"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
 	"go-shortener/services/url-api/model"
 
+	"github.com/google/uuid"
+	"github.com/mssola/useragent"
 	"github.com/zeromicro/go-zero/core/logx"
-	"github.com/zeromicro/go-zero/core/threading"
+	"github.com/zeromicro/go-zero/core/metric"
 )
 
+const (
+	redirectHit   = "hit"
+	redirectMiss  = "miss"
+	redirectError = "error"
+)
+
+// redirects counts redirect requests by outcome.
+var redirects = metric.NewCounterVec(&metric.CounterVecOpts{
+	Namespace: "url_api",
+	Name:      "redirects_total",
+	Help:      "Redirect requests by status: hit, miss for unknown short codes, or error.",
+	Labels:    []string{"status"},
+})
+
+// variantCookieMaxAge is how long a sticky variant assignment is remembered (30 days).
+const variantCookieMaxAge = 30 * 24 * 60 * 60
+
+// RedirectTarget is the destination resolved for a redirect request.
+type RedirectTarget struct {
+	// Url is the destination the visitor is sent to.
+	Url string
+	// Variant is the label of the chosen weighted destination, empty for
+	// single-destination links.
+	Variant string
+	// Cookie pins a sticky variant to the visitor; nil when nothing needs to be set.
+	Cookie *http.Cookie
+	// Interstitial is the countdown page to show instead of redirecting, set for
+	// links flagged "always show interstitial".
+	Interstitial *pages.Preview
+	// Unfurl is the Open Graph page served to link-unfurling crawlers, set when
+	// the link carries custom social preview metadata.
+	Unfurl *pages.OpenGraph
+}
+
+// crawlerTokens identify link-unfurling clients that useragent.Bot() does not flag.
+var crawlerTokens = []string{"whatsapp", "skypeuripreview", "embedly", "vkshare"}
+
 type RedirectLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -37,47 +78,192 @@ func NewRedirectLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Redirect
 	}
 }
 
-// Redirect looks up the original URL by short code and returns it for HTTP redirect.
-// Publishes a ClickEvent to Kafka asynchronously (fire-and-forget).
-func (l *RedirectLogic) Redirect(req *types.RedirectRequest, r *http.Request) (string, error) {
+// Redirect looks up the short code and resolves the destination for HTTP redirect,
+// choosing a weighted variant for A/B split links.
+// Queues a ClickEvent for asynchronous publishing to Kafka (fire-and-forget).
+func (l *RedirectLogic) Redirect(req *types.RedirectRequest, r *http.Request) (*RedirectTarget, error) {
 	logx.WithContext(l.ctx).Infow("redirect", logx.Field("code", req.Code))
 
 	url, err := l.svcCtx.UrlModel.FindOneByShortCode(l.ctx, req.Code)
 	if err != nil {
 		if errors.Is(err, model.ErrNotFound) {
-			return "", problemdetails.New(404, problemdetails.TypeNotFound, "Not Found",
+			redirects.Inc(redirectMiss)
+			return nil, problemdetails.New(404, problemdetails.TypeNotFound, "Not Found",
 				"short code '"+req.Code+"' not found")
 		}
 		logx.WithContext(l.ctx).Errorw("failed to find URL", logx.Field("error", err.Error()))
-		return "", problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error",
+		redirects.Inc(redirectError)
+		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error",
 			"failed to look up short code")
 	}
 
-	// Publish click event to Kafka asynchronously (fire-and-forget)
-	threading.GoSafe(func() {
-		clickEvent := events.ClickEvent{
-			ShortCode: req.Code,
-			Timestamp: time.Now().Unix(),
-			IP:        extractClientIP(r),
-			UserAgent: r.UserAgent(),
-			Referer:   r.Referer(),
-		}
+	target := l.resolveTarget(url, r)
+	switch {
+	case hasOpenGraph(url) && isCrawler(r.UserAgent()):
+		target.Unfurl = l.openGraph(url, target.Url)
+	case url.Interstitial:
+		// Redirects never wait on analytics-rpc; the interstitial shows the
+		// last known count, if any.
+		count, counted := l.svcCtx.ClickCounts.Cached(url.ShortCode)
+		target.Interstitial = newPreview(url, target.Url, l.svcCtx.Config.InterstitialCountdown, count, counted)
+	}
+
+	redirects.Inc(redirectHit)
+	l.publishClick(req.Code, target.Variant, r)
+
+	return target, nil
+}
+
+// publishClick queues the click event for the background publisher; the
+// outbox spools it if Kafka is down. Dropped events are counted by the
+// publisher, so a full queue never fails the redirect.
+func (l *RedirectLogic) publishClick(code, variant string, r *http.Request) {
+	eventID, err := uuid.NewV7()
+	if err != nil {
+		logx.WithContext(l.ctx).Errorf("failed to generate click event id: %v", err)
+		return
+	}
+
+	clickEvent := events.ClickEvent{
+		EventID:   eventID.String(),
+		ShortCode: code,
+		Timestamp: time.Now().Unix(),
+		IP:        extractClientIP(r),
+		UserAgent: r.UserAgent(),
+		Referer:   r.Referer(),
+		Variant:   variant,
+	}
+
+	contentType := l.svcCtx.Config.KqPusherConf.ContentType()
+	payload, err := events.EncodeClick(&clickEvent, contentType)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorf("failed to marshal click event: %v", err)
+		return
+	}
+
+	l.svcCtx.ClickEvents.Enqueue(l.ctx, contentType, payload)
+}
+
+// resolveTarget picks the destination for a link. Single-destination links go to
+// original_url; A/B split links pick a variant by weight, either at random or
+// sticky per visitor (existing cookie first, then a hash of the client IP).
+// If the variants cannot be loaded, the visitor falls back to original_url.
+func (l *RedirectLogic) resolveTarget(url *model.Urls, r *http.Request) *RedirectTarget {
+	fallback := &RedirectTarget{Url: url.OriginalUrl}
+	if url.Rotation == "" {
+		return fallback
+	}
 
-		payload, marshalErr := json.Marshal(clickEvent)
-		if marshalErr != nil {
-			logx.Errorf("failed to marshal click event: %v", marshalErr)
-			return
+	variants, err := l.svcCtx.UrlVariantModel.FindAllByShortCode(l.ctx, url.ShortCode)
+	if err != nil {
+		logx.WithContext(l.ctx).Errorw("failed to load variants, falling back to original URL",
+			logx.Field("code", url.ShortCode),
+			logx.Field("error", err.Error()),
+		)
+		return fallback
+	}
+
+	var totalWeight int64
+	for _, v := range variants {
+		totalWeight += v.Weight
+	}
+	if totalWeight <= 0 {
+		return fallback
+	}
+
+	if url.Rotation != model.RotationSticky {
+		v := pickVariant(variants, rand.Int64N(totalWeight))
+		return &RedirectTarget{Url: v.DestinationUrl, Variant: v.Label}
+	}
+
+	cookieName := variantCookieName(url.ShortCode)
+	if c, cookieErr := r.Cookie(cookieName); cookieErr == nil {
+		for _, v := range variants {
+			if v.Label == c.Value {
+				return &RedirectTarget{Url: v.DestinationUrl, Variant: v.Label}
+			}
 		}
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(extractClientIP(r)))
+	v := pickVariant(variants, int64(h.Sum64()%uint64(totalWeight)))
+
+	return &RedirectTarget{
+		Url:     v.DestinationUrl,
+		Variant: v.Label,
+		Cookie: &http.Cookie{
+			Name:     cookieName,
+			Value:    v.Label,
+			Path:     "/" + url.ShortCode,
+			MaxAge:   variantCookieMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+}
 
-		if pushErr := l.svcCtx.KqPusher.Push(l.ctx, string(payload)); pushErr != nil {
-			logx.Errorw("failed to push click event to Kafka",
-				logx.Field("code", req.Code),
-				logx.Field("error", pushErr.Error()),
-			)
+// openGraph builds the social preview for a link. The title falls back to the
+// link title, then to the short URL, since og:title is required by most unfurlers.
+func (l *RedirectLogic) openGraph(url *model.Urls, destination string) *pages.OpenGraph {
+	shortUrl := l.svcCtx.Config.BaseUrl + "/" + url.ShortCode
+
+	title := url.OgTitle
+	if title == "" {
+		title = url.Title
+	}
+	if title == "" {
+		title = shortUrl
+	}
+
+	return &pages.OpenGraph{
+		ShortUrl:    shortUrl,
+		Destination: destination,
+		Title:       title,
+		Description: url.OgDescription,
+		Image:       url.OgImage,
+	}
+}
+
+// hasOpenGraph reports whether the link carries custom social preview metadata.
+func hasOpenGraph(url *model.Urls) bool {
+	return url.OgTitle != "" || url.OgDescription != "" || url.OgImage != ""
+}
+
+// isCrawler reports whether the User-Agent belongs to a bot, which includes the
+// link unfurlers of Slack, Twitter, Facebook, LinkedIn and Discord.
+func isCrawler(userAgent string) bool {
+	if userAgent == "" {
+		return false
+	}
+	if useragent.New(userAgent).Bot() {
+		return true
+	}
+
+	uaLower := strings.ToLower(userAgent)
+	for _, token := range crawlerTokens {
+		if strings.Contains(uaLower, token) {
+			return true
 		}
-	})
+	}
+	return false
+}
+
+// variantCookieName returns the cookie that pins a sticky variant for a short code.
+func variantCookieName(shortCode string) string {
+	return "sv_" + shortCode
+}
 
-	return url.OriginalUrl, nil
+// pickVariant returns the variant whose cumulative weight range contains n,
+// where n is in [0, total weight).
+func pickVariant(variants []*model.UrlVariants, n int64) *model.UrlVariants {
+	for _, v := range variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return variants[len(variants)-1]
 }
 
 // extractClientIP returns the client IP from X-Forwarded-For, X-Real-IP, or RemoteAddr.
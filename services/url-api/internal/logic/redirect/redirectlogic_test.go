@@ -3,10 +3,13 @@ package redirect
 import (
 	"context"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"go-shortener/common/events"
+	"go-shortener/services/analytics-rpc/analyticsclient"
 	"go-shortener/services/url-api/internal/config"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
@@ -14,8 +17,25 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
+// fakeClickEvents records queued click events.
+type fakeClickEvents struct {
+	contentTypes []string
+	queued       [][]byte
+}
+
+func (f *fakeClickEvents) Enqueue(ctx context.Context, contentType string, value []byte) bool {
+	f.contentTypes = append(f.contentTypes, contentType)
+	f.queued = append(f.queued, value)
+	return true
+}
+
+func (f *fakeClickEvents) Start() {}
+
+func (f *fakeClickEvents) Stop() {}
+
 func TestRedirectLogic_Success(t *testing.T) {
 	mockModel := &model.MockUrlsModel{
 		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
@@ -30,10 +50,11 @@ func TestRedirectLogic_Success(t *testing.T) {
 		},
 	}
 
+	clickEvents := &fakeClickEvents{}
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
-		KqPusher: nil, // nil KqPusher is fine - GoSafe handles nil gracefully
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickEvents: clickEvents,
 	}
 
 	req := httptest.NewRequest("GET", "/abc12345", nil)
@@ -41,10 +62,94 @@ func TestRedirectLogic_Success(t *testing.T) {
 	req.Header.Set("X-Forwarded-For", "1.2.3.4")
 
 	logic := NewRedirectLogic(context.Background(), svcCtx)
-	originalUrl, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, target)
+	assert.Equal(t, "https://example.com", target.Url)
+	assert.Empty(t, target.Variant)
+	assert.Nil(t, target.Cookie)
+	assert.Nil(t, target.Interstitial)
+
+	require.Len(t, clickEvents.queued, 1)
+	event, err := events.DecodeClick(clickEvents.contentTypes[0], clickEvents.queued[0])
+	require.NoError(t, err)
+	assert.Equal(t, "abc12345", event.ShortCode)
+	assert.Equal(t, "1.2.3.4", event.IP)
+	assert.NotEmpty(t, event.EventID)
+}
+
+func TestRedirectLogic_Interstitial(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:           "test-id",
+				ShortCode:    "abc12345",
+				OriginalUrl:  "https://example.com",
+				Title:        "Example",
+				Interstitial: true,
+				CreatedAt:    time.Now(),
+			}, nil
+		},
+	}
+
+	// A preview or link detail view counted the link before
+	analytics := clickCount(7)
+	counter := newClickCounter(t, analytics)
+	_, err := counter.Count(context.Background(), "abc12345")
+	require.NoError(t, err)
+	analytics.getClickCountFunc = func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+		t.Fatal("redirects must not call analytics-rpc")
+		return nil, nil
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080", InterstitialCountdown: 5},
+		UrlModel:    mockModel,
+		ClickCounts: counter,
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	logic := NewRedirectLogic(context.Background(), svcCtx)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, target.Interstitial)
+	assert.Equal(t, "https://example.com", target.Interstitial.Destination)
+	assert.Equal(t, "Example", target.Interstitial.Title)
+	assert.Equal(t, int64(7), target.Interstitial.TotalClicks)
+	assert.False(t, target.Interstitial.ClicksUnavailable)
+	assert.Equal(t, 5, target.Interstitial.Countdown)
+}
+
+func TestRedirectLogic_InterstitialWithoutCachedCount(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{ShortCode: "abc12345", OriginalUrl: "https://example.com", Interstitial: true}, nil
+		},
+	}
+	analytics := &mockAnalyticsClient{
+		getClickCountFunc: func(ctx context.Context, in *analyticsclient.GetClickCountRequest, opts ...grpc.CallOption) (*analyticsclient.GetClickCountResponse, error) {
+			t.Fatal("redirects must not call analytics-rpc")
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080", InterstitialCountdown: 5},
+		UrlModel:    mockModel,
+		ClickCounts: newClickCounter(t, analytics),
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	logic := NewRedirectLogic(context.Background(), svcCtx)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
 
 	require.NoError(t, err)
-	assert.Equal(t, "https://example.com", originalUrl)
+	require.NotNil(t, target.Interstitial)
+	assert.True(t, target.Interstitial.ClicksUnavailable)
 }
 
 func TestRedirectLogic_NotFound(t *testing.T) {
@@ -55,17 +160,17 @@ func TestRedirectLogic_NotFound(t *testing.T) {
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
-		KqPusher: nil,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickEvents: &fakeClickEvents{},
 	}
 
 	req := httptest.NewRequest("GET", "/notfound", nil)
 	logic := NewRedirectLogic(context.Background(), svcCtx)
-	originalUrl, err := logic.Redirect(&types.RedirectRequest{Code: "notfound"}, req)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "notfound"}, req)
 
 	require.Error(t, err)
-	assert.Empty(t, originalUrl)
+	assert.Nil(t, target)
 	assert.Contains(t, err.Error(), "not found")
 }
 
@@ -77,20 +182,238 @@ func TestRedirectLogic_DBError(t *testing.T) {
 	}
 
 	svcCtx := &svc.ServiceContext{
-		Config:   config.Config{BaseUrl: "http://localhost:8080"},
-		UrlModel: mockModel,
-		KqPusher: nil,
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickEvents: &fakeClickEvents{},
 	}
 
 	req := httptest.NewRequest("GET", "/abc12345", nil)
 	logic := NewRedirectLogic(context.Background(), svcCtx)
-	originalUrl, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
 
 	require.Error(t, err)
-	assert.Empty(t, originalUrl)
+	assert.Nil(t, target)
 	assert.Contains(t, err.Error(), "Internal Error")
 }
 
+func ogLink() *model.MockUrlsModel {
+	return &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:            "test-id",
+				ShortCode:     "abc12345",
+				OriginalUrl:   "https://example.com",
+				CreatedAt:     time.Now(),
+				OgTitle:       "Spring sale",
+				OgDescription: "Everything 20% off",
+				OgImage:       "https://example.com/og.png",
+			}, nil
+		},
+	}
+}
+
+func TestRedirectLogic_OpenGraph_Crawler(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    ogLink(),
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	req.Header.Set("User-Agent", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)")
+	target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, target.Unfurl)
+	assert.Equal(t, "http://localhost:8080/abc12345", target.Unfurl.ShortUrl)
+	assert.Equal(t, "https://example.com", target.Unfurl.Destination)
+	assert.Equal(t, "Spring sale", target.Unfurl.Title)
+	assert.Equal(t, "Everything 20% off", target.Unfurl.Description)
+	assert.Equal(t, "https://example.com/og.png", target.Unfurl.Image)
+}
+
+func TestRedirectLogic_OpenGraph_Human(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    ogLink(),
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	assert.Nil(t, target.Unfurl, "humans should get the normal redirect")
+	assert.Equal(t, "https://example.com", target.Url)
+}
+
+func TestRedirectLogic_OpenGraph_CrawlerWithoutMetadata(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{ShortCode: "abc12345", OriginalUrl: "https://example.com"}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:      config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:    mockModel,
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	req.Header.Set("User-Agent", "Twitterbot/1.0")
+	target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	assert.Nil(t, target.Unfurl, "crawlers follow the redirect when the link has no custom metadata")
+}
+
+func TestIsCrawler(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  bool
+	}{
+		{"", false},
+		{"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		{"Twitterbot/1.0", true},
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", true},
+		{"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", true},
+		{"WhatsApp/2.23.20.0", true},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36", false},
+		{"curl/7.64.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isCrawler(tt.userAgent))
+		})
+	}
+}
+
+func splitLink(rotation string) *model.MockUrlsModel {
+	return &model.MockUrlsModel{
+		FindOneByShortCodeFunc: func(ctx context.Context, shortCode string) (*model.Urls, error) {
+			return &model.Urls{
+				Id:          "test-id",
+				ShortCode:   "abc12345",
+				OriginalUrl: "https://example.com",
+				CreatedAt:   time.Now(),
+				Rotation:    rotation,
+			}, nil
+		},
+	}
+}
+
+func splitVariants() *model.MockUrlVariantsModel {
+	return &model.MockUrlVariantsModel{
+		FindAllByShortCodeFunc: func(ctx context.Context, shortCode string) ([]*model.UrlVariants, error) {
+			return []*model.UrlVariants{
+				{ShortCode: "abc12345", Label: "a", DestinationUrl: "https://example.com/a", Weight: 1},
+				{ShortCode: "abc12345", Label: "b", DestinationUrl: "https://example.com/b", Weight: 1},
+			}, nil
+		},
+	}
+}
+
+func TestRedirectLogic_RandomVariant(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        splitLink(model.RotationRandom),
+		UrlVariantModel: splitVariants(),
+		ClickEvents:     &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	logic := NewRedirectLogic(context.Background(), svcCtx)
+	target, err := logic.Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err)
+	require.NotNil(t, target)
+	assert.Contains(t, []string{"a", "b"}, target.Variant)
+	assert.Equal(t, "https://example.com/"+target.Variant, target.Url)
+	assert.Nil(t, target.Cookie, "random rotation should not pin the visitor")
+}
+
+func TestRedirectLogic_StickyVariant_IPHash(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        splitLink(model.RotationSticky),
+		UrlVariantModel: splitVariants(),
+		ClickEvents:     &fakeClickEvents{},
+	}
+
+	var first *RedirectTarget
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest("GET", "/abc12345", nil)
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+		require.NoError(t, err)
+
+		if first == nil {
+			first = target
+			continue
+		}
+		assert.Equal(t, first.Variant, target.Variant, "same IP should always get the same variant")
+	}
+
+	require.NotNil(t, first.Cookie)
+	assert.Equal(t, "sv_abc12345", first.Cookie.Name)
+	assert.Equal(t, first.Variant, first.Cookie.Value)
+	assert.Equal(t, "/abc12345", first.Cookie.Path)
+}
+
+func TestRedirectLogic_StickyVariant_Cookie(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        splitLink(model.RotationSticky),
+		UrlVariantModel: splitVariants(),
+		ClickEvents:     &fakeClickEvents{},
+	}
+
+	for _, label := range []string{"a", "b"} {
+		req := httptest.NewRequest("GET", "/abc12345", nil)
+		req.AddCookie(&http.Cookie{Name: "sv_abc12345", Value: label})
+		target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, label, target.Variant)
+		assert.Equal(t, "https://example.com/"+label, target.Url)
+		assert.Nil(t, target.Cookie, "existing assignment should not be re-issued")
+	}
+}
+
+func TestRedirectLogic_VariantLookupError(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: splitLink(model.RotationRandom),
+		UrlVariantModel: &model.MockUrlVariantsModel{
+			FindAllByShortCodeFunc: func(ctx context.Context, shortCode string) ([]*model.UrlVariants, error) {
+				return nil, errors.New("database connection timeout")
+			},
+		},
+		ClickEvents: &fakeClickEvents{},
+	}
+
+	req := httptest.NewRequest("GET", "/abc12345", nil)
+	target, err := NewRedirectLogic(context.Background(), svcCtx).Redirect(&types.RedirectRequest{Code: "abc12345"}, req)
+
+	require.NoError(t, err, "variant lookup failure should fall back, not fail the redirect")
+	assert.Equal(t, "https://example.com", target.Url)
+	assert.Empty(t, target.Variant)
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := []*model.UrlVariants{
+		{Label: "a", Weight: 3},
+		{Label: "b", Weight: 1},
+	}
+
+	assert.Equal(t, "a", pickVariant(variants, 0).Label)
+	assert.Equal(t, "a", pickVariant(variants, 2).Label)
+	assert.Equal(t, "b", pickVariant(variants, 3).Label)
+}
+
 func TestExtractClientIP_XForwardedFor(t *testing.T) {
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("X-Forwarded-For", "1.2.3.4, 5.6.7.8")
@@ -5,6 +5,8 @@ package shorten
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"strings"
 
 	"go-shortener/pkg/problemdetails"
@@ -15,12 +17,35 @@ import (
 	"github.com/google/uuid"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/metric"
 )
 
 const (
 	shortCodeLength = 8
 	maxRetries      = 5
 	alphabet        = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	minDestinations      = 2
+	maxDestinations      = 10
+	maxLabelLength       = 32
+	maxTitleLength       = 255
+	maxDescriptionLength = 1000
+)
+
+var (
+	shortenCollisions = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "url_api",
+		Subsystem: "shorten",
+		Name:      "collisions_total",
+		Help:      "Generated short codes that collided with an existing one and were retried.",
+	})
+	shortenAttempts = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "url_api",
+		Subsystem: "shorten",
+		Name:      "attempts",
+		Help:      "Short codes generated per shorten request, up to the retry limit.",
+		Buckets:   []float64{1, 2, 3, 4, 5},
+	})
 )
 
 type ShortenLogic struct {
@@ -41,6 +66,15 @@ func NewShortenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ShortenLo
 func (l *ShortenLogic) Shorten(req *types.ShortenRequest) (resp *types.ShortenResponse, err error) {
 	logx.WithContext(l.ctx).Infow("shorten URL", logx.Field("original_url", req.OriginalUrl))
 
+	if metadataErr := validateMetadata(req); metadataErr != nil {
+		return nil, metadataErr
+	}
+
+	rotation, validationErr := validateDestinations(req)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+
 	// Generate UUIDv7 for primary key
 	id, err := uuid.NewV7()
 	if err != nil {
@@ -57,16 +91,24 @@ func (l *ShortenLogic) Shorten(req *types.ShortenRequest) (resp *types.ShortenRe
 			return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to generate short code")
 		}
 
-		_, insertErr := l.svcCtx.UrlModel.Insert(l.ctx, &model.Urls{
-			Id:          id.String(),
-			ShortCode:   code,
-			OriginalUrl: req.OriginalUrl,
-			ClickCount:  0,
-		})
+		link := &model.Urls{
+			Id:            id.String(),
+			ShortCode:     code,
+			OriginalUrl:   req.OriginalUrl,
+			ClickCount:    0,
+			Rotation:      rotation,
+			Title:         req.Title,
+			Interstitial:  req.Interstitial,
+			OgTitle:       req.OgTitle,
+			OgDescription: req.OgDescription,
+			OgImage:       req.OgImage,
+		}
+		insertErr := l.insert(link, req.Destinations)
 
 		if insertErr != nil {
 			// Check for unique constraint violation (short_code collision)
 			if isUniqueViolation(insertErr) {
+				shortenCollisions.Inc()
 				logx.WithContext(l.ctx).Infow("short code collision, retrying",
 					logx.Field("attempt", attempt+1),
 					logx.Field("code", code),
@@ -80,10 +122,13 @@ func (l *ShortenLogic) Shorten(req *types.ShortenRequest) (resp *types.ShortenRe
 		}
 
 		shortCode = code
+		shortenAttempts.Observe(int64(attempt + 1))
+		l.fetchMetadata(link)
 		break
 	}
 
 	if shortCode == "" {
+		shortenAttempts.Observe(maxRetries)
 		return nil, problemdetails.New(500, problemdetails.TypeInternalError, "Internal Error", "failed to generate unique short code after maximum retries")
 	}
 
@@ -94,6 +139,145 @@ func (l *ShortenLogic) Shorten(req *types.ShortenRequest) (resp *types.ShortenRe
 	}, nil
 }
 
+// insert stores the link, together with its weighted destinations when the
+// request defines an A/B split.
+func (l *ShortenLogic) insert(link *model.Urls, destinations []types.DestinationInput) error {
+	if len(destinations) == 0 {
+		_, err := l.svcCtx.UrlModel.Insert(l.ctx, link)
+		return err
+	}
+
+	variants := make([]*model.UrlVariants, 0, len(destinations))
+	for _, d := range destinations {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		variants = append(variants, &model.UrlVariants{
+			Id:             id.String(),
+			ShortCode:      link.ShortCode,
+			Label:          d.Label,
+			DestinationUrl: d.Url,
+			Weight:         d.Weight,
+		})
+	}
+
+	return l.svcCtx.UrlModel.InsertWithVariants(l.ctx, link, variants)
+}
+
+// fetchMetadata queues a background scrape of the destination's title,
+// description and favicon and stores them on the link. Failures, including a
+// full queue, are logged only; the link works without metadata.
+func (l *ShortenLogic) fetchMetadata(link *model.Urls) {
+	fetcher, fetches := l.svcCtx.MetadataFetcher, l.svcCtx.MetadataQueue
+	if fetcher == nil || fetches == nil {
+		return
+	}
+
+	// The queue detaches the fetch from the request context so it outlives
+	// the response, while keeping trace and log fields.
+	queued := fetches.Submit(l.ctx, func(ctx context.Context) {
+		md, err := fetcher.Fetch(ctx, link.OriginalUrl)
+		if err != nil {
+			logx.WithContext(ctx).Infow("failed to fetch destination metadata",
+				logx.Field("short_code", link.ShortCode),
+				logx.Field("error", err.Error()),
+			)
+			return
+		}
+
+		if err := l.svcCtx.UrlModel.UpdatePageMetadata(ctx, link.Id, md.Title, md.Description, md.Favicon); err != nil {
+			logx.WithContext(ctx).Errorw("failed to store destination metadata",
+				logx.Field("short_code", link.ShortCode),
+				logx.Field("error", err.Error()),
+			)
+		}
+	})
+	if !queued {
+		logx.WithContext(l.ctx).Infow("metadata fetch queue is full or stopped, skipping destination metadata",
+			logx.Field("short_code", link.ShortCode),
+		)
+	}
+}
+
+// validateMetadata checks the optional link title and Open Graph fields.
+func validateMetadata(req *types.ShortenRequest) error {
+	var fieldErrors []problemdetails.FieldError
+	maxLength := func(field, value string, limit int) {
+		if len(value) > limit {
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{
+				Field:   field,
+				Message: fmt.Sprintf("must be at most %d characters", limit),
+			})
+		}
+	}
+
+	maxLength("title", req.Title, maxTitleLength)
+	maxLength("og_title", req.OgTitle, maxTitleLength)
+	maxLength("og_description", req.OgDescription, maxDescriptionLength)
+
+	if req.OgImage != "" {
+		u, err := url.Parse(req.OgImage)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{
+				Field:   "og_image",
+				Message: "must be an absolute http or https URL",
+			})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return problemdetails.NewValidation(fieldErrors)
+	}
+	return nil
+}
+
+// validateDestinations checks the weighted destinations of an A/B split link and
+// returns the rotation strategy to store. Links without destinations have no rotation.
+func validateDestinations(req *types.ShortenRequest) (string, error) {
+	if len(req.Destinations) == 0 {
+		return "", nil
+	}
+
+	var fieldErrors []problemdetails.FieldError
+	if len(req.Destinations) < minDestinations || len(req.Destinations) > maxDestinations {
+		fieldErrors = append(fieldErrors, problemdetails.FieldError{
+			Field:   "destinations",
+			Message: fmt.Sprintf("must contain between %d and %d destinations", minDestinations, maxDestinations),
+		})
+	}
+
+	seen := make(map[string]bool, len(req.Destinations))
+	for i, d := range req.Destinations {
+		field := fmt.Sprintf("destinations[%d]", i)
+		switch {
+		case d.Label == "":
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: field + ".label", Message: "is required"})
+		case len(d.Label) > maxLabelLength:
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{
+				Field:   field + ".label",
+				Message: fmt.Sprintf("must be at most %d characters", maxLabelLength),
+			})
+		case seen[d.Label]:
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: field + ".label", Message: "must be unique"})
+		}
+		seen[d.Label] = true
+
+		if d.Url == "" {
+			fieldErrors = append(fieldErrors, problemdetails.FieldError{Field: field + ".url", Message: "is required"})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return "", problemdetails.NewValidation(fieldErrors)
+	}
+
+	if req.Rotation == "" {
+		return model.RotationRandom, nil
+	}
+	return req.Rotation, nil
+}
+
 // isUniqueViolation checks if the error is a PostgreSQL unique constraint violation.
 func isUniqueViolation(err error) bool {
 	return strings.Contains(err.Error(), "duplicate key value violates unique constraint")
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"go-shortener/pkg/problemdetails"
 	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/metadata"
 	"go-shortener/services/url-api/internal/svc"
 	"go-shortener/services/url-api/internal/types"
 	"go-shortener/services/url-api/model"
@@ -45,6 +49,187 @@ func TestShortenLogic_Success(t *testing.T) {
 	assert.Len(t, resp.ShortCode, 8)
 }
 
+type mockMetadataFetcher struct {
+	FetchFunc func(ctx context.Context, rawURL string) (*metadata.Metadata, error)
+}
+
+func (m *mockMetadataFetcher) Fetch(ctx context.Context, rawURL string) (*metadata.Metadata, error) {
+	return m.FetchFunc(ctx, rawURL)
+}
+
+func newMetadataQueue(t *testing.T) *metadata.Queue {
+	queue := metadata.NewQueue(1, 10)
+	t.Cleanup(queue.Stop)
+	return queue
+}
+
+func TestShortenLogic_FetchesMetadata(t *testing.T) {
+	stored := make(chan [4]string, 1)
+	var insertedID string
+	mockModel := &model.MockUrlsModel{
+		InsertFunc: func(ctx context.Context, data *model.Urls) (sql.Result, error) {
+			insertedID = data.Id
+			return nil, nil
+		},
+		UpdatePageMetadataFunc: func(ctx context.Context, id, title, description, faviconUrl string) error {
+			stored <- [4]string{id, title, description, faviconUrl}
+			return nil
+		},
+	}
+	fetcher := &mockMetadataFetcher{
+		FetchFunc: func(ctx context.Context, rawURL string) (*metadata.Metadata, error) {
+			assert.Equal(t, "https://example.com", rawURL)
+			return &metadata.Metadata{
+				Title:       "Example Domain",
+				Description: "An example page",
+				Favicon:     "https://example.com/favicon.ico",
+			}, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        mockModel,
+		MetadataFetcher: fetcher,
+		MetadataQueue:   newMetadataQueue(t),
+	}
+
+	// A cancelled request context must not abort the background fetch
+	ctx, cancel := context.WithCancel(context.Background())
+	logic := NewShortenLogic(ctx, svcCtx)
+	_, err := logic.Shorten(&types.ShortenRequest{OriginalUrl: "https://example.com"})
+	cancel()
+	require.NoError(t, err)
+
+	select {
+	case got := <-stored:
+		assert.Equal(t, [4]string{insertedID, "Example Domain", "An example page", "https://example.com/favicon.ico"}, got)
+	case <-time.After(time.Second):
+		t.Fatal("metadata was not stored")
+	}
+}
+
+func TestShortenLogic_MetadataFetchFailure(t *testing.T) {
+	fetched := make(chan struct{})
+	mockModel := &model.MockUrlsModel{
+		InsertFunc: func(ctx context.Context, data *model.Urls) (sql.Result, error) {
+			return nil, nil
+		},
+	}
+	fetcher := &mockMetadataFetcher{
+		FetchFunc: func(ctx context.Context, rawURL string) (*metadata.Metadata, error) {
+			defer close(fetched)
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:          config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel:        mockModel,
+		MetadataFetcher: fetcher,
+		MetadataQueue:   newMetadataQueue(t),
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	resp, err := logic.Shorten(&types.ShortenRequest{OriginalUrl: "https://example.com"})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	select {
+	case <-fetched:
+	case <-time.After(time.Second):
+		t.Fatal("metadata fetch was not attempted")
+	}
+}
+
+func TestShortenLogic_TitleAndInterstitial(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		InsertFunc: func(ctx context.Context, data *model.Urls) (sql.Result, error) {
+			assert.Equal(t, "Spring sale", data.Title)
+			assert.True(t, data.Interstitial)
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	_, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl:  "https://example.com",
+		Title:        "Spring sale",
+		Interstitial: true,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestShortenLogic_TitleTooLong(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: &model.MockUrlsModel{},
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	resp, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl: "https://example.com",
+		Title:       strings.Repeat("x", 256),
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "Validation Failed")
+}
+
+func TestShortenLogic_OpenGraph(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		InsertFunc: func(ctx context.Context, data *model.Urls) (sql.Result, error) {
+			assert.Equal(t, "Spring sale", data.OgTitle)
+			assert.Equal(t, "Everything 20% off", data.OgDescription)
+			assert.Equal(t, "https://example.com/og.png", data.OgImage)
+			return nil, nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	_, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl:   "https://example.com",
+		OgTitle:       "Spring sale",
+		OgDescription: "Everything 20% off",
+		OgImage:       "https://example.com/og.png",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestShortenLogic_InvalidOgImage(t *testing.T) {
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: &model.MockUrlsModel{},
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	resp, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl: "https://example.com",
+		OgImage:     "javascript:alert(1)",
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+
+	var pd *problemdetails.ProblemDetail
+	require.ErrorAs(t, err, &pd)
+	require.Len(t, pd.Errors, 1)
+	assert.Equal(t, "og_image", pd.Errors[0].Field)
+}
+
 func TestShortenLogic_InsertError(t *testing.T) {
 	mockModel := &model.MockUrlsModel{
 		InsertFunc: func(ctx context.Context, data *model.Urls) (sql.Result, error) {
@@ -119,3 +304,120 @@ func TestShortenLogic_MaxRetriesExceeded(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "maximum retries")
 }
+
+func TestShortenLogic_WithDestinations(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		InsertWithVariantsFunc: func(ctx context.Context, data *model.Urls, variants []*model.UrlVariants) error {
+			assert.Equal(t, "https://example.com", data.OriginalUrl)
+			assert.Equal(t, model.RotationSticky, data.Rotation)
+			require.Len(t, variants, 2)
+			for _, v := range variants {
+				assert.NotEmpty(t, v.Id)
+				assert.Equal(t, data.ShortCode, v.ShortCode)
+			}
+			assert.Equal(t, "a", variants[0].Label)
+			assert.Equal(t, "https://example.com/a", variants[0].DestinationUrl)
+			assert.Equal(t, int64(3), variants[0].Weight)
+			assert.Equal(t, "b", variants[1].Label)
+			return nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	resp, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl: "https://example.com",
+		Destinations: []types.DestinationInput{
+			{Label: "a", Url: "https://example.com/a", Weight: 3},
+			{Label: "b", Url: "https://example.com/b", Weight: 1},
+		},
+		Rotation: model.RotationSticky,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Len(t, resp.ShortCode, 8)
+}
+
+func TestShortenLogic_DefaultRotation(t *testing.T) {
+	mockModel := &model.MockUrlsModel{
+		InsertWithVariantsFunc: func(ctx context.Context, data *model.Urls, variants []*model.UrlVariants) error {
+			assert.Equal(t, model.RotationRandom, data.Rotation)
+			return nil
+		},
+	}
+
+	svcCtx := &svc.ServiceContext{
+		Config:   config.Config{BaseUrl: "http://localhost:8080"},
+		UrlModel: mockModel,
+	}
+
+	logic := NewShortenLogic(context.Background(), svcCtx)
+	_, err := logic.Shorten(&types.ShortenRequest{
+		OriginalUrl: "https://example.com",
+		Destinations: []types.DestinationInput{
+			{Label: "a", Url: "https://example.com/a", Weight: 1},
+			{Label: "b", Url: "https://example.com/b", Weight: 1},
+		},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestShortenLogic_InvalidDestinations(t *testing.T) {
+	tests := []struct {
+		name         string
+		destinations []types.DestinationInput
+		field        string
+	}{
+		{
+			name:         "single destination",
+			destinations: []types.DestinationInput{{Label: "a", Url: "https://example.com/a", Weight: 1}},
+			field:        "destinations",
+		},
+		{
+			name: "duplicate label",
+			destinations: []types.DestinationInput{
+				{Label: "a", Url: "https://example.com/a", Weight: 1},
+				{Label: "a", Url: "https://example.com/b", Weight: 1},
+			},
+			field: "destinations[1].label",
+		},
+		{
+			name: "missing url",
+			destinations: []types.DestinationInput{
+				{Label: "a", Url: "https://example.com/a", Weight: 1},
+				{Label: "b", Weight: 1},
+			},
+			field: "destinations[1].url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcCtx := &svc.ServiceContext{
+				Config:   config.Config{BaseUrl: "http://localhost:8080"},
+				UrlModel: &model.MockUrlsModel{},
+			}
+
+			logic := NewShortenLogic(context.Background(), svcCtx)
+			resp, err := logic.Shorten(&types.ShortenRequest{
+				OriginalUrl:  "https://example.com",
+				Destinations: tt.destinations,
+			})
+
+			require.Error(t, err)
+			assert.Nil(t, resp)
+
+			var pd *problemdetails.ProblemDetail
+			require.ErrorAs(t, err, &pd)
+			assert.Equal(t, 400, pd.Status)
+			require.NotEmpty(t, pd.Errors)
+			assert.Equal(t, tt.field, pd.Errors[0].Field)
+		})
+	}
+}
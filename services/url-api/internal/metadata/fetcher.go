@@ -0,0 +1,227 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-shortener/services/url-api/internal/safehttp"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	userAgent = "go-shortener-metadata/1.0"
+
+	maxTitleLength       = 255
+	maxDescriptionLength = 1000
+)
+
+// Metadata is the page information scraped from a link's destination.
+type Metadata struct {
+	Title       string
+	Description string
+	Favicon     string
+}
+
+// Options tunes the fetcher limits.
+type Options struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	// AllowPrivateNetworks disables the SSRF guard. Only meant for tests and
+	// local development where destinations live on localhost.
+	AllowPrivateNetworks bool
+}
+
+// Fetcher downloads destination pages and extracts their metadata.
+type Fetcher struct {
+	client       *http.Client
+	maxBodyBytes int64
+}
+
+// NewFetcher returns a Fetcher backed by an SSRF-guarded HTTP client.
+func NewFetcher(opts Options) *Fetcher {
+	return &Fetcher{
+		client: safehttp.NewClient(safehttp.Options{
+			Timeout:              opts.Timeout,
+			AllowPrivateNetworks: opts.AllowPrivateNetworks,
+		}),
+		maxBodyBytes: opts.MaxBodyBytes,
+	}
+}
+
+// Fetch downloads rawURL and extracts its title, description and favicon.
+// Only the first MaxBodyBytes of the body are read.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBodyBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	// resp.Request is the last request made, so relative favicons resolve
+	// against the final URL after redirects.
+	return parse(body, resp.Request.URL)
+}
+
+// parse walks the document head. Open Graph tags are used as fallbacks when
+// <title> or the description meta tag are missing.
+func parse(r io.Reader, base *url.URL) (*Metadata, error) {
+	var (
+		md            Metadata
+		ogTitle       string
+		ogDescription string
+		inTitle       bool
+	)
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			break loop
+		case html.TextToken:
+			if inTitle && md.Title == "" {
+				md.Title = strings.TrimSpace(string(z.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = true
+			case "body":
+				break loop
+			case "meta", "link":
+				if !hasAttr {
+					continue
+				}
+				attrs := attributes(z)
+				if string(name) == "link" {
+					if isIcon(attrs["rel"]) && md.Favicon == "" {
+						md.Favicon = resolve(base, attrs["href"])
+					}
+					continue
+				}
+				key := attrs["name"]
+				if key == "" {
+					key = attrs["property"]
+				}
+				switch strings.ToLower(key) {
+				case "description":
+					md.Description = strings.TrimSpace(attrs["content"])
+				case "og:title":
+					ogTitle = strings.TrimSpace(attrs["content"])
+				case "og:description":
+					ogDescription = strings.TrimSpace(attrs["content"])
+				}
+			}
+		}
+	}
+
+	if md.Title == "" {
+		md.Title = ogTitle
+	}
+	if md.Description == "" {
+		md.Description = ogDescription
+	}
+	if md.Favicon == "" {
+		md.Favicon = resolve(base, "/favicon.ico")
+	}
+	md.Title = truncate(md.Title, maxTitleLength)
+	md.Description = truncate(md.Description, maxDescriptionLength)
+
+	return &md, nil
+}
+
+func attributes(z *html.Tokenizer) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		key, val, more := z.TagAttr()
+		attrs[strings.ToLower(string(key))] = string(val)
+		if !more {
+			return attrs
+		}
+	}
+}
+
+func isIcon(rel string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		if token == "icon" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve makes href absolute against base. Only http(s) results are kept so
+// data: and javascript: URLs never reach the UI.
+func resolve(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" {
+		return ""
+	}
+	abs := base.ResolveReference(ref)
+	if abs.Scheme != "http" && abs.Scheme != "https" {
+		return ""
+	}
+	return abs.String()
+}
+
+// truncate cuts s to at most limit bytes without splitting a UTF-8 rune.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !isRuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
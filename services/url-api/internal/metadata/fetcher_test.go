@@ -0,0 +1,144 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-shortener/services/url-api/internal/safehttp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFetcher() *Fetcher {
+	return NewFetcher(Options{
+		Timeout:              2 * time.Second,
+		MaxBodyBytes:         1 << 20,
+		AllowPrivateNetworks: true,
+	})
+}
+
+func TestFetch_ExtractsMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<!doctype html>
+<html><head>
+  <title> Example Domain </title>
+  <meta name="description" content="An example page">
+  <link rel="shortcut icon" href="/static/icon.png">
+</head><body><title>ignored</title></body></html>`))
+	}))
+	defer srv.Close()
+
+	md, err := newTestFetcher().Fetch(context.Background(), srv.URL+"/page")
+	require.NoError(t, err)
+	assert.Equal(t, "Example Domain", md.Title)
+	assert.Equal(t, "An example page", md.Description)
+	assert.Equal(t, srv.URL+"/static/icon.png", md.Favicon)
+}
+
+func TestFetch_OpenGraphFallbackAndDefaultFavicon(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head>
+  <meta property="og:title" content="OG Title">
+  <meta property="og:description" content="OG Description">
+</head></html>`))
+	}))
+	defer srv.Close()
+
+	md, err := newTestFetcher().Fetch(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "OG Title", md.Title)
+	assert.Equal(t, "OG Description", md.Description)
+	assert.Equal(t, srv.URL+"/favicon.ico", md.Favicon)
+}
+
+func TestFetch_FollowsRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<title>Moved</title><link rel="icon" href="fav.ico">`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	md, err := newTestFetcher().Fetch(context.Background(), srv.URL+"/old")
+	require.NoError(t, err)
+	assert.Equal(t, "Moved", md.Title)
+	assert.Equal(t, srv.URL+"/new/fav.ico", md.Favicon)
+}
+
+func TestFetch_BodySizeLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><head>" + strings.Repeat("<!-- padding -->", 100) + "<title>Too far</title></head></html>"))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(Options{Timeout: 2 * time.Second, MaxBodyBytes: 64, AllowPrivateNetworks: true})
+	md, err := f.Fetch(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Empty(t, md.Title)
+}
+
+func TestFetch_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(Options{Timeout: 50 * time.Millisecond, MaxBodyBytes: 1024, AllowPrivateNetworks: true})
+	_, err := f.Fetch(context.Background(), srv.URL)
+	require.Error(t, err)
+}
+
+func TestFetch_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "non-2xx status", url: srv.URL + "/missing"},
+		{name: "non-html content", url: srv.URL + "/json"},
+		{name: "unsupported scheme", url: "ftp://example.com/file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestFetcher().Fetch(context.Background(), tt.url)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFetch_BlocksPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach a loopback destination")
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(Options{Timeout: 2 * time.Second, MaxBodyBytes: 1024})
+	_, err := f.Fetch(context.Background(), srv.URL)
+	require.ErrorIs(t, err, safehttp.ErrBlockedAddress)
+}
@@ -0,0 +1,109 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// Queue runs metadata fetches in the background on a fixed pool of workers
+// fed by a bounded queue, so a burst of new links cannot start an unbounded
+// number of fetches. Queue implements service.Service so shutdown drains it
+// with the server's service group.
+type Queue struct {
+	jobs chan job
+
+	mu      sync.RWMutex
+	closed  bool
+	workers sync.WaitGroup
+
+	// ctx is cancelled by Stop to cut in-flight fetches short.
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+type job struct {
+	ctx context.Context
+	run func(ctx context.Context)
+}
+
+// NewQueue returns a Queue holding up to size jobs and starts its workers.
+func NewQueue(workers, size int) *Queue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		jobs:   make(chan job, size),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for i := 0; i < max(workers, 1); i++ {
+		q.workers.Add(1)
+		threading.GoSafe(func() {
+			defer q.workers.Done()
+			q.work()
+		})
+	}
+
+	return q
+}
+
+// Submit queues run without blocking and reports false when the queue is full
+// or Stop was called; the link then simply has no metadata. run gets the
+// values of ctx, outlives its cancellation and is cancelled by Stop instead.
+func (q *Queue) Submit(ctx context.Context, run func(ctx context.Context)) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.jobs <- job{ctx: context.WithoutCancel(ctx), run: run}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start does nothing; the workers run from NewQueue until Stop.
+func (q *Queue) Start() {}
+
+// Stop stops accepting jobs, cancels in-flight fetches, skips queued ones and
+// waits for the workers to return.
+func (q *Queue) Stop() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		close(q.jobs)
+		q.mu.Unlock()
+
+		if pending := len(q.jobs); pending > 0 {
+			logx.Infof("Skipping %d queued metadata fetches", pending)
+		}
+		q.cancel()
+		q.workers.Wait()
+	})
+}
+
+func (q *Queue) work() {
+	for j := range q.jobs {
+		if q.ctx.Err() != nil {
+			continue
+		}
+		q.run(j)
+	}
+}
+
+// run calls j.run with a context that Stop cancels.
+func (q *Queue) run(j job) {
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	stop := context.AfterFunc(q.ctx, cancel)
+	defer stop()
+
+	j.run(ctx)
+}
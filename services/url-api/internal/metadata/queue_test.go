@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestQueue_RunsDetachedFromCaller(t *testing.T) {
+	queue := NewQueue(1, 1)
+	defer queue.Stop()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "trace"))
+	cancel()
+	done := make(chan any, 1)
+	require.True(t, queue.Submit(ctx, func(ctx context.Context) {
+		assert.NoError(t, ctx.Err())
+		done <- ctx.Value(ctxKey{})
+	}))
+
+	select {
+	case value := <-done:
+		assert.Equal(t, "trace", value)
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+}
+
+func TestQueue_RejectsWhenFull(t *testing.T) {
+	queue := NewQueue(1, 1)
+	defer queue.Stop()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	require.True(t, queue.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	// One job waits in the queue; the worker is busy with the first
+	assert.True(t, queue.Submit(context.Background(), func(ctx context.Context) {}))
+	assert.False(t, queue.Submit(context.Background(), func(ctx context.Context) {}))
+	close(release)
+}
+
+func TestQueue_StopCancelsAndDrains(t *testing.T) {
+	queue := NewQueue(1, 10)
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	require.True(t, queue.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	}))
+	ran := false
+	require.True(t, queue.Submit(context.Background(), func(ctx context.Context) {
+		ran = true
+	}))
+	<-started
+
+	queue.Stop()
+
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("Stop returned before the in-flight job finished")
+	}
+	assert.False(t, ran, "queued jobs are skipped once stopped")
+	assert.False(t, queue.Submit(context.Background(), func(ctx context.Context) {}))
+}
@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"go-shortener/common/eventbus"
+	"go-shortener/common/events"
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+var (
+	backlogEvents = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "backlog_events",
+		Help:      "Click events spooled on disk waiting for Kafka.",
+	})
+	backlogBytes = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "backlog_bytes",
+		Help:      "Disk used by the click event spool.",
+	})
+	spooledEvents = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "spooled_total",
+		Help:      "Click events spooled because Kafka was unavailable or a backlog existed.",
+	})
+	relayedEvents = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "relayed_total",
+		Help:      "Spooled click events delivered to Kafka.",
+	})
+	droppedEvents = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "dropped_total",
+		Help:      "Click events lost because the spool was full or failed.",
+	})
+	pushDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "push_duration_ms",
+		Help:      "Duration of click event pushes to the event bus, successful or not.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500},
+	})
+	pushFailures = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "url_api",
+		Subsystem: "click_outbox",
+		Name:      "push_failures_total",
+		Help:      "Click event pushes to the event bus that failed, including relay attempts.",
+	})
+)
+
+// Pusher publishes encoded events and reports failures synchronously.
+// eventbus.EventBus naturally satisfies this interface.
+type Pusher interface {
+	Push(ctx context.Context, msgs ...eventbus.Message) error
+	Close() error
+}
+
+// Outbox publishes click events to the event bus, Kafka by default, spooling
+// them on local disk while Kafka is unavailable. While a backlog exists new
+// events are spooled behind it, and a relay drains the spool in order once
+// Kafka is back. Delivery is at least once: events carry ids the consumer
+// deduplicates on. Outbox implements service.Service so the relay runs in the
+// server's service group.
+type Outbox struct {
+	conf   config.OutboxConf
+	pusher Pusher
+	spool  *spool
+
+	// relayMu keeps Stop from closing the spool under a running Drain.
+	relayMu sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	once    sync.Once
+}
+
+// New opens the spool in c.Dir and returns an Outbox pushing with pusher.
+func New(c config.OutboxConf, pusher Pusher) (*Outbox, error) {
+	s, err := openSpool(c.Dir, c.MaxBytes, c.SegmentBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Outbox{
+		conf:   c,
+		pusher: pusher,
+		spool:  s,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	o.observe()
+	return o, nil
+}
+
+// Publish pushes an encoded event to Kafka, or spools it if Kafka fails or
+// older events are still spooled. It only fails when the event is lost. A
+// push cancelled through ctx is spooled as well, so callers must detach ctx
+// from requests that may finish first.
+func (o *Outbox) Publish(ctx context.Context, contentType string, value []byte) error {
+	if pending, _ := o.spool.Backlog(); pending == 0 {
+		err := o.push(ctx, Record{ContentType: contentType, Value: value})
+		if err == nil {
+			return nil
+		}
+		logx.WithContext(ctx).Errorw("failed to push click event to Kafka, spooling",
+			logx.Field("error", err.Error()),
+		)
+	}
+
+	return o.Spool(contentType, value)
+}
+
+// Spool appends an encoded event to the spool without trying Kafka, for the
+// relay to deliver on this or the next run. It only fails when the event is
+// lost.
+func (o *Outbox) Spool(contentType string, value []byte) error {
+	if err := o.spool.Append(Record{ContentType: contentType, Value: value}); err != nil {
+		droppedEvents.Inc()
+		return err
+	}
+	spooledEvents.Inc()
+	o.observe()
+	return nil
+}
+
+// Start drains the spool every RelayInterval until Stop is called.
+func (o *Outbox) Start() {
+	if pending, size := o.spool.Backlog(); pending > 0 {
+		logx.Infof("Click event outbox has a backlog of %d events (%d bytes)", pending, size)
+	}
+
+	ticker := time.NewTicker(time.Duration(o.conf.RelayInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		o.Drain(o.ctx)
+
+		select {
+		case <-o.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends the relay, closes the spool and then the pusher. Events
+// published afterwards are dropped.
+func (o *Outbox) Stop() {
+	o.once.Do(func() {
+		o.cancel()
+
+		o.relayMu.Lock()
+		defer o.relayMu.Unlock()
+		if err := o.spool.Close(); err != nil {
+			logx.Errorf("failed to close click event outbox: %v", err)
+		}
+		if err := o.pusher.Close(); err != nil {
+			logx.Errorf("failed to close click event pusher: %v", err)
+		}
+	})
+}
+
+// Drain relays spooled events in order, RelayBatchSize per push, until the
+// spool is empty or a push fails, and returns how many were relayed.
+func (o *Outbox) Drain(ctx context.Context) int {
+	o.relayMu.Lock()
+	defer o.relayMu.Unlock()
+
+	relayed := 0
+	for ctx.Err() == nil {
+		recs, err := o.spool.Peek(max(o.conf.RelayBatchSize, 1))
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logx.WithContext(ctx).Errorw("failed to read click event outbox", logx.Field("error", err.Error()))
+			break
+		}
+
+		if err := o.push(ctx, recs...); err != nil {
+			logx.WithContext(ctx).Infow("Kafka still unavailable, keeping click event backlog",
+				logx.Field("error", err.Error()),
+			)
+			break
+		}
+		if err := o.spool.Commit(); err != nil {
+			logx.WithContext(ctx).Errorw("failed to commit click event outbox", logx.Field("error", err.Error()))
+			break
+		}
+		relayedEvents.Add(float64(len(recs)))
+		relayed += len(recs)
+	}
+
+	if relayed > 0 {
+		logx.WithContext(ctx).Infow("relayed spooled click events", logx.Field("count", relayed))
+		o.observe()
+	}
+	return relayed
+}
+
+// push publishes events within PushTimeout, naming their content type in the
+// message headers.
+func (o *Outbox) push(ctx context.Context, recs ...Record) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(o.conf.PushTimeout)*time.Millisecond)
+	defer cancel()
+
+	msgs := make([]eventbus.Message, len(recs))
+	for i, rec := range recs {
+		msgs[i] = eventbus.Message{
+			Value:   string(rec.Value),
+			Headers: map[string]string{events.HeaderContentType: rec.ContentType},
+		}
+	}
+
+	start := time.Now()
+	err := o.pusher.Push(ctx, msgs...)
+	pushDuration.Observe(time.Since(start).Milliseconds())
+	if err != nil {
+		pushFailures.Inc()
+	}
+	return err
+}
+
+func (o *Outbox) observe() {
+	pending, size := o.spool.Backlog()
+	backlogEvents.Set(float64(pending))
+	backlogBytes.Set(float64(size))
+}
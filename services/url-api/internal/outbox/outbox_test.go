@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shortener/common/eventbus"
+	"go-shortener/common/events"
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePusher records pushed values and fails while down is set.
+type fakePusher struct {
+	mu           sync.Mutex
+	down         bool
+	closed       bool
+	pushes       int
+	pushed       []string
+	contentTypes []string
+}
+
+func (p *fakePusher) Push(ctx context.Context, msgs ...eventbus.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down {
+		return errors.New("kafka: broker unavailable")
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	p.pushes++
+	for _, msg := range msgs {
+		p.pushed = append(p.pushed, msg.Value)
+		p.contentTypes = append(p.contentTypes, msg.Headers[events.HeaderContentType])
+	}
+	return nil
+}
+
+func (p *fakePusher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *fakePusher) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func testConf(t *testing.T) config.OutboxConf {
+	return config.OutboxConf{
+		Dir:            t.TempDir(),
+		MaxBytes:       1 << 20,
+		SegmentBytes:   1 << 10,
+		PushTimeout:    1000,
+		RelayInterval:  10,
+		RelayBatchSize: 2,
+	}
+}
+
+func TestOutbox_PushesDirectly(t *testing.T) {
+	pusher := &fakePusher{}
+	o, err := New(testConf(t), pusher)
+	require.NoError(t, err)
+
+	require.NoError(t, o.Publish(context.Background(), events.ContentTypeProtobuf, []byte("a")))
+
+	assert.Equal(t, []string{"a"}, pusher.pushed)
+	assert.Equal(t, []string{events.ContentTypeProtobuf}, pusher.contentTypes)
+	pending, _ := o.spool.Backlog()
+	assert.Zero(t, pending)
+}
+
+func TestOutbox_SpoolsWhileKafkaIsDownAndRelaysInOrder(t *testing.T) {
+	pusher := &fakePusher{down: true}
+	o, err := New(testConf(t), pusher)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, o.Publish(ctx, events.ContentTypeJSON, []byte("1")))
+	require.NoError(t, o.Publish(ctx, events.ContentTypeJSON, []byte("2")))
+	assert.Zero(t, o.Drain(ctx), "nothing is relayed while Kafka is down")
+
+	pusher.setDown(false)
+	// A backlog exists, so new events queue behind it
+	require.NoError(t, o.Publish(ctx, events.ContentTypeProtobuf, []byte("3")))
+	assert.Empty(t, pusher.pushed)
+
+	assert.Equal(t, 3, o.Drain(ctx))
+	assert.Equal(t, []string{"1", "2", "3"}, pusher.pushed)
+	assert.Equal(t, 2, pusher.pushes, "relayed in batches of RelayBatchSize")
+	assert.Equal(t, []string{events.ContentTypeJSON, events.ContentTypeJSON, events.ContentTypeProtobuf}, pusher.contentTypes)
+	pending, _ := o.spool.Backlog()
+	assert.Zero(t, pending)
+}
+
+func TestOutbox_SpoolsCancelledPushes(t *testing.T) {
+	pusher := &fakePusher{}
+	o, err := New(testConf(t), pusher)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, o.Publish(ctx, events.ContentTypeJSON, []byte("a")))
+
+	assert.Empty(t, pusher.pushed)
+	pending, _ := o.spool.Backlog()
+	assert.Equal(t, 1, pending, "the event is relayed on the next run")
+}
+
+func TestOutbox_SpoolSkipsKafka(t *testing.T) {
+	pusher := &fakePusher{}
+	o, err := New(testConf(t), pusher)
+	require.NoError(t, err)
+
+	require.NoError(t, o.Spool(events.ContentTypeJSON, []byte("a")))
+
+	assert.Empty(t, pusher.pushed)
+	pending, _ := o.spool.Backlog()
+	assert.Equal(t, 1, pending)
+	assert.Equal(t, 1, o.Drain(context.Background()))
+	assert.Equal(t, []string{"a"}, pusher.pushed)
+}
+
+func TestOutbox_StartRelaysUntilStopped(t *testing.T) {
+	conf := testConf(t)
+	pusher := &fakePusher{down: true}
+	o, err := New(conf, pusher)
+	require.NoError(t, err)
+	require.NoError(t, o.Publish(context.Background(), events.ContentTypeJSON, []byte("1")))
+	pusher.setDown(false)
+
+	done := make(chan struct{})
+	go func() {
+		o.Start()
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		pusher.mu.Lock()
+		defer pusher.mu.Unlock()
+		return len(pusher.pushed) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	o.Stop()
+	<-done
+	assert.True(t, pusher.closed)
+	pusher.setDown(true)
+	assert.ErrorIs(t, o.Publish(context.Background(), events.ContentTypeJSON, []byte("2")), ErrClosed,
+		"events cannot be spooled after shutdown")
+}
@@ -0,0 +1,495 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentSuffix = ".log"
+	cursorName    = "cursor"
+
+	// recordHeaderSize is the length and CRC-32 preceding each record body.
+	recordHeaderSize = 8
+	// maxRecordSize bounds a record body, so a corrupt length is not
+	// allocated.
+	maxRecordSize = 1 << 20
+)
+
+var (
+	// ErrFull is returned when appending would exceed the spool size limit.
+	ErrFull = errors.New("outbox spool is full")
+	// ErrClosed is returned once the spool is closed.
+	ErrClosed = errors.New("outbox spool is closed")
+	// ErrBroken is returned by Append once a failed write could not be rolled
+	// back, so records appended after it would be unreadable.
+	ErrBroken = errors.New("outbox spool is broken")
+)
+
+// segmentWriter is the segment file being appended to.
+type segmentWriter interface {
+	io.WriteSeeker
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// Record is a spooled event and the content type it was encoded with.
+type Record struct {
+	ContentType string
+	Value       []byte
+}
+
+// spool is a durable FIFO of records in append-only segment files. Records
+// are read from a cursor that is persisted after each commit, so a crash may
+// replay the last records but never loses one. Fully read segments are
+// deleted.
+type spool struct {
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+
+	mu       sync.Mutex
+	closed   bool
+	segments []uint64 // segment ids, oldest first; the last one is written
+	writer   segmentWriter
+	// writeSize is the size of the segment being written.
+	writeSize int64
+	// broken is set when a failed append left a torn record behind.
+	broken  bool
+	reader  *os.File
+	readSeg uint64
+	readOff int64
+	// peeked is the encoded size of the records returned by the last peek
+	// and peekedCount their number.
+	peeked      int64
+	peekedCount int
+	// size is the total size of all segments; pending counts unread records.
+	size    int64
+	pending int
+}
+
+// openSpool opens or creates the spool in dir, truncating a record torn by a
+// crash at the end of the last segment.
+func openSpool(dir string, maxBytes, segmentBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{dir: dir, maxBytes: maxBytes, segmentBytes: segmentBytes}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentSuffix)
+		if !ok {
+			continue
+		}
+		if id, err := strconv.ParseUint(name, 10, 64); err == nil {
+			s.segments = append(s.segments, id)
+		}
+	}
+	slices.Sort(s.segments)
+
+	s.readSeg, s.readOff = s.loadCursor()
+	// Segments before the cursor were drained before the last shutdown.
+	for len(s.segments) > 0 && s.segments[0] < s.readSeg {
+		if err := os.Remove(s.segmentPath(s.segments[0])); err != nil {
+			return err
+		}
+		s.segments = s.segments[1:]
+	}
+	if len(s.segments) == 0 || s.segments[0] != s.readSeg {
+		s.readOff = 0
+		if len(s.segments) > 0 {
+			s.readSeg = s.segments[0]
+		}
+	}
+	if len(s.segments) == 0 {
+		s.readSeg = 1
+		s.segments = []uint64{1}
+	}
+
+	for i, id := range s.segments {
+		from := int64(0)
+		if id == s.readSeg {
+			from = s.readOff
+		}
+		end, count, err := scanSegment(s.segmentPath(id), from)
+		if err != nil {
+			return err
+		}
+		if i == len(s.segments)-1 {
+			if err := truncate(s.segmentPath(id), end); err != nil {
+				return err
+			}
+			s.writeSize = end
+		}
+		s.pending += count
+		info, err := os.Stat(s.segmentPath(id))
+		if err != nil {
+			return err
+		}
+		s.size += info.Size()
+	}
+
+	s.writer, err = os.OpenFile(s.segmentPath(s.segments[len(s.segments)-1]), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	return err
+}
+
+// Append durably adds rec at the end of the spool.
+func (s *spool) Append(rec Record) error {
+	data, err := encodeRecord(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.broken {
+		return ErrBroken
+	}
+	if s.size+int64(len(data)) > s.maxBytes {
+		return ErrFull
+	}
+	if s.writeSize > 0 && s.writeSize+int64(len(data)) > s.segmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.writer.Write(data); err != nil {
+		return s.rollback(err)
+	}
+	if err := s.writer.Sync(); err != nil {
+		return s.rollback(err)
+	}
+	s.writeSize += int64(len(data))
+	s.size += int64(len(data))
+	s.pending++
+	return nil
+}
+
+// rollback cuts what a failed append wrote off the segment, since records
+// behind a torn one could not be read. If that fails too, the spool refuses
+// further appends. Callers hold s.mu.
+func (s *spool) rollback(err error) error {
+	if terr := s.writer.Truncate(s.writeSize); terr != nil {
+		s.broken = true
+		return fmt.Errorf("%w: %w (rollback: %w)", ErrBroken, err, terr)
+	}
+	if _, serr := s.writer.Seek(s.writeSize, io.SeekStart); serr != nil {
+		s.broken = true
+		return fmt.Errorf("%w: %w (rollback: %w)", ErrBroken, err, serr)
+	}
+	return err
+}
+
+// Peek returns up to n of the oldest unread records, or io.EOF when the
+// spool is empty. The records come from one segment, so a segment is only
+// deleted once all its records are committed. Calling Peek again without
+// Commit returns the same records.
+func (s *spool) Peek(n int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.pending == 0 {
+		return nil, io.EOF
+	}
+
+	var recs []Record
+	off := s.readOff
+	for len(recs) < min(n, s.pending) {
+		if s.reader == nil {
+			f, err := os.Open(s.segmentPath(s.readSeg))
+			if err != nil {
+				return nil, err
+			}
+			s.reader = f
+		}
+
+		rec, size, err := readRecord(s.reader, off)
+		if errors.Is(err, io.EOF) && len(recs) == 0 && s.readSeg != s.segments[len(s.segments)-1] {
+			if err := s.dropReadSegment(); err != nil {
+				return nil, err
+			}
+			off = s.readOff
+			continue
+		}
+		if err != nil {
+			if len(recs) > 0 {
+				break
+			}
+			return nil, err
+		}
+		recs = append(recs, rec)
+		off += size
+	}
+
+	s.peeked, s.peekedCount = off-s.readOff, len(recs)
+	return recs, nil
+}
+
+// Commit marks the records returned by the last Peek as delivered. Once the
+// spool is drained, its segments are replaced by an empty one.
+func (s *spool) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.peekedCount == 0 {
+		return errors.New("outbox spool: commit without peek")
+	}
+	s.readOff += s.peeked
+	s.pending -= s.peekedCount
+	s.peeked, s.peekedCount = 0, 0
+
+	if s.pending == 0 {
+		return s.reset()
+	}
+	return s.saveCursor()
+}
+
+// Backlog returns the number of unread records and the bytes they occupy on
+// disk, including read records in segments not yet deleted.
+func (s *spool) Backlog() (int, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending, s.size
+}
+
+// Close releases the segment files. The spool is reopened from disk.
+func (s *spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.reader != nil {
+		s.reader.Close()
+	}
+	return s.writer.Close()
+}
+
+// rotate starts a new segment for writing.
+func (s *spool) rotate() error {
+	if err := s.writer.Close(); err != nil {
+		return err
+	}
+	id := s.segments[len(s.segments)-1] + 1
+	f, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	s.writer = f
+	s.writeSize = 0
+	s.segments = append(s.segments, id)
+	return nil
+}
+
+// dropReadSegment deletes the fully read oldest segment and moves the cursor
+// to the next one.
+func (s *spool) dropReadSegment() error {
+	s.reader.Close()
+	s.reader = nil
+
+	path := s.segmentPath(s.readSeg)
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	s.size -= info.Size()
+	s.segments = s.segments[1:]
+	s.readSeg, s.readOff = s.segments[0], 0
+	return s.saveCursor()
+}
+
+// reset replaces all segments of a drained spool with a new empty one.
+func (s *spool) reset() error {
+	if s.reader != nil {
+		s.reader.Close()
+		s.reader = nil
+	}
+	if err := s.writer.Close(); err != nil {
+		return err
+	}
+
+	id := s.segments[len(s.segments)-1] + 1
+	f, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	s.writer, s.writeSize = f, 0
+	s.readSeg, s.readOff = id, 0
+	if err := s.saveCursor(); err != nil {
+		return err
+	}
+
+	for _, old := range s.segments {
+		if err := os.Remove(s.segmentPath(old)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	s.segments = []uint64{id}
+	s.size = 0
+	return nil
+}
+
+func (s *spool) segmentPath(id uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", id, segmentSuffix))
+}
+
+func (s *spool) loadCursor() (uint64, int64) {
+	data, err := os.ReadFile(filepath.Join(s.dir, cursorName))
+	if err != nil {
+		return 0, 0
+	}
+	var seg uint64
+	var off int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &seg, &off); err != nil {
+		return 0, 0
+	}
+	return seg, off
+}
+
+// saveCursor persists the read position through a rename, so the cursor file
+// is never torn.
+func (s *spool) saveCursor() error {
+	path := filepath.Join(s.dir, cursorName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%d %d", s.readSeg, s.readOff)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// encodeRecord lays out a record as a header of body length and CRC-32,
+// followed by the content type length, content type and value.
+func encodeRecord(rec Record) ([]byte, error) {
+	if len(rec.ContentType) > 255 {
+		return nil, fmt.Errorf("outbox spool: content type too long: %q", rec.ContentType)
+	}
+	if 1+len(rec.ContentType)+len(rec.Value) > maxRecordSize {
+		return nil, fmt.Errorf("outbox spool: record exceeds %d bytes", maxRecordSize)
+	}
+	body := make([]byte, 0, 1+len(rec.ContentType)+len(rec.Value))
+	body = append(body, byte(len(rec.ContentType)))
+	body = append(body, rec.ContentType...)
+	body = append(body, rec.Value...)
+
+	data := make([]byte, recordHeaderSize, recordHeaderSize+len(body))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(body))
+	return append(data, body...), nil
+}
+
+// readRecord reads the record at off, returning its encoded size. A missing,
+// short or corrupt record reads as io.EOF.
+func readRecord(r io.ReaderAt, off int64) (Record, int64, error) {
+	var header [recordHeaderSize]byte
+	if _, err := r.ReadAt(header[:], off); err != nil {
+		return Record{}, 0, io.EOF
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxRecordSize {
+		return Record{}, 0, io.EOF
+	}
+	body := make([]byte, length)
+	if _, err := r.ReadAt(body, off+recordHeaderSize); err != nil {
+		return Record{}, 0, io.EOF
+	}
+	rec, ok := decodeBody(body, binary.BigEndian.Uint32(header[4:8]))
+	if !ok {
+		return Record{}, 0, io.EOF
+	}
+	return rec, int64(recordHeaderSize + len(body)), nil
+}
+
+func decodeBody(body []byte, checksum uint32) (Record, bool) {
+	if len(body) == 0 || crc32.ChecksumIEEE(body) != checksum {
+		return Record{}, false
+	}
+	n := int(body[0])
+	if 1+n > len(body) {
+		return Record{}, false
+	}
+	return Record{ContentType: string(body[1 : 1+n]), Value: body[1+n:]}, true
+}
+
+// scanSegment counts the intact records of a segment from off and returns
+// the offset just past the last one.
+func scanSegment(path string, off int64) (int64, int, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	r := bufio.NewReader(f)
+	count := 0
+	for {
+		var header [recordHeaderSize]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return off, count, nil
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if length > maxRecordSize {
+			return off, count, nil
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return off, count, nil
+		}
+		if _, ok := decodeBody(body, binary.BigEndian.Uint32(header[4:8])); !ok {
+			return off, count, nil
+		}
+		off += int64(recordHeaderSize + len(body))
+		count++
+	}
+}
+
+func truncate(path string, size int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() == size {
+		return nil
+	}
+	return os.Truncate(path, size)
+}
@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(i int) Record {
+	return Record{ContentType: "application/json", Value: []byte(`{"n":` + strconv.Itoa(i) + `}`)}
+}
+
+func drain(t *testing.T, s *spool) []Record {
+	t.Helper()
+	var out []Record
+	for {
+		recs, err := s.Peek(3)
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		out = append(out, recs...)
+	}
+}
+
+func TestSpool_FIFOAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	// Small segments force a rotation every couple of records
+	s, err := openSpool(dir, 1<<20, 64)
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append(record(i)))
+	}
+	pending, _ := s.Backlog()
+	assert.Equal(t, 10, pending)
+
+	got := drain(t, s)
+	require.Len(t, got, 10)
+	for i, rec := range got {
+		assert.Equal(t, record(i), rec)
+	}
+
+	pending, size := s.Backlog()
+	assert.Zero(t, pending)
+	assert.Zero(t, size)
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	assert.Len(t, segments, 1, "drained segments are deleted")
+}
+
+func TestSpool_PeekWithoutCommitRepeats(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 1<<20, 1<<20)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Append(record(1)))
+	require.NoError(t, s.Append(record(2)))
+
+	first, err := s.Peek(1)
+	require.NoError(t, err)
+	again, err := s.Peek(1)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	require.NoError(t, s.Commit())
+
+	next, err := s.Peek(1)
+	require.NoError(t, err)
+	assert.Equal(t, []Record{record(2)}, next)
+}
+
+func TestSpool_PeekBatchStopsAtSegmentEnd(t *testing.T) {
+	rec := record(1)
+	data, err := encodeRecord(rec)
+	require.NoError(t, err)
+
+	s, err := openSpool(t.TempDir(), 1<<20, int64(2*len(data)))
+	require.NoError(t, err)
+	defer s.Close()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append(record(i)))
+	}
+
+	recs, err := s.Peek(10)
+	require.NoError(t, err)
+	assert.Equal(t, []Record{record(0), record(1)}, recs)
+	require.NoError(t, s.Commit())
+
+	recs, err = s.Peek(10)
+	require.NoError(t, err)
+	assert.Equal(t, []Record{record(2)}, recs)
+	require.NoError(t, s.Commit())
+	pending, _ := s.Backlog()
+	assert.Zero(t, pending)
+}
+
+func TestSpool_ResumesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 1<<20, 64)
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		require.NoError(t, s.Append(record(i)))
+	}
+	for i := 0; i < 3; i++ {
+		_, err := s.Peek(1)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+	require.NoError(t, s.Close())
+
+	s, err = openSpool(dir, 1<<20, 64)
+	require.NoError(t, err)
+	defer s.Close()
+
+	pending, _ := s.Backlog()
+	assert.Equal(t, 3, pending)
+	require.NoError(t, s.Append(record(6)))
+	assert.Equal(t, []Record{record(3), record(4), record(5), record(6)}, drain(t, s))
+}
+
+func TestSpool_TruncatesTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 1<<20, 1<<20)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(record(1)))
+	require.NoError(t, s.Append(record(2)))
+	require.NoError(t, s.Close())
+
+	// Simulate a crash halfway through writing the second record
+	path := filepath.Join(dir, "00000000000000000001"+segmentSuffix)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-3))
+
+	s, err = openSpool(dir, 1<<20, 1<<20)
+	require.NoError(t, err)
+	defer s.Close()
+
+	pending, _ := s.Backlog()
+	assert.Equal(t, 1, pending)
+	require.NoError(t, s.Append(record(3)))
+	assert.Equal(t, []Record{record(1), record(3)}, drain(t, s))
+}
+
+// tornWriter writes half of the next write to the segment, then fails, like
+// a full disk. It fails truncating when truncateErr is set.
+type tornWriter struct {
+	segmentWriter
+	tear        bool
+	truncateErr error
+}
+
+func (w *tornWriter) Write(p []byte) (int, error) {
+	if !w.tear {
+		return w.segmentWriter.Write(p)
+	}
+	w.tear = false
+	n, _ := w.segmentWriter.Write(p[:len(p)/2])
+	return n, errors.New("no space left on device")
+}
+
+func (w *tornWriter) Truncate(size int64) error {
+	if w.truncateErr != nil {
+		return w.truncateErr
+	}
+	return w.segmentWriter.Truncate(size)
+}
+
+func TestSpool_RollsBackTornAppend(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 1<<20, 1<<20)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(record(1)))
+	_, sizeBefore := s.Backlog()
+
+	w := &tornWriter{segmentWriter: s.writer, tear: true}
+	s.writer = w
+	require.Error(t, s.Append(record(2)))
+	pending, size := s.Backlog()
+	assert.Equal(t, 1, pending)
+	assert.Equal(t, sizeBefore, size, "the torn bytes are not counted")
+
+	// Records appended after the failure stay readable, also after a restart
+	require.NoError(t, s.Append(record(3)))
+	require.NoError(t, s.Close())
+	s, err = openSpool(dir, 1<<20, 1<<20)
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []Record{record(1), record(3)}, drain(t, s))
+}
+
+func TestSpool_BrokenWhenRollbackFails(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 1<<20, 1<<20)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Append(record(1)))
+
+	s.writer = &tornWriter{segmentWriter: s.writer, tear: true, truncateErr: errors.New("read-only file system")}
+	assert.ErrorIs(t, s.Append(record(2)), ErrBroken)
+	assert.ErrorIs(t, s.Append(record(3)), ErrBroken, "nothing is appended behind the torn record")
+
+	// Records before the tear are still relayed
+	recs, err := s.Peek(10)
+	require.NoError(t, err)
+	assert.Equal(t, []Record{record(1)}, recs)
+}
+
+func TestSpool_BoundedSize(t *testing.T) {
+	rec := record(1)
+	data, err := encodeRecord(rec)
+	require.NoError(t, err)
+
+	s, err := openSpool(t.TempDir(), int64(2*len(data)), 1<<20)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Append(rec))
+	require.NoError(t, s.Append(rec))
+	assert.ErrorIs(t, s.Append(rec), ErrFull)
+
+	// Draining frees the space
+	drain(t, s)
+	assert.NoError(t, s.Append(rec))
+}
+
+func TestSpool_Closed(t *testing.T) {
+	s, err := openSpool(t.TempDir(), 1<<20, 1<<20)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	assert.ErrorIs(t, s.Append(record(1)), ErrClosed)
+	_, err = s.Peek(1)
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,77 @@
+// Package pages renders the HTML pages url-api serves in place of a redirect.
+package pages
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"net/url"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Preview is the data shown on the link preview / interstitial page.
+type Preview struct {
+	ShortCode   string
+	Destination string
+	Title       string
+	TotalClicks int64
+	// ClicksUnavailable hides TotalClicks when no click count is known.
+	ClicksUnavailable bool
+	// Countdown is the number of seconds before the page forwards the visitor
+	// to Destination. 0 disables the automatic redirect (explicit preview).
+	Countdown int
+}
+
+// AutoRedirect reports whether the page should forward the visitor on its own.
+// Only http(s) destinations are followed automatically, since meta refresh
+// targets are not sanitized by html/template.
+func (p *Preview) AutoRedirect() bool {
+	return p.Countdown > 0 && isWebURL(p.Destination)
+}
+
+// WritePreview renders the preview page. The page is never cached so that
+// click counts and destination changes are always current.
+func WritePreview(w http.ResponseWriter, p *Preview) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	return templates.ExecuteTemplate(w, "preview.html", p)
+}
+
+// OpenGraph is the social preview served to link-unfurling crawlers.
+type OpenGraph struct {
+	// ShortUrl is the canonical og:url of the link.
+	ShortUrl    string
+	Destination string
+	Title       string
+	Description string
+	Image       string
+}
+
+// AutoRedirect reports whether the page forwards to Destination with a meta refresh.
+func (o *OpenGraph) AutoRedirect() bool {
+	return isWebURL(o.Destination)
+}
+
+// WriteOpenGraph renders an HTML page carrying Open Graph and Twitter Card tags,
+// which crawlers read instead of following the redirect.
+func WriteOpenGraph(w http.ResponseWriter, o *OpenGraph) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	return templates.ExecuteTemplate(w, "opengraph.html", o)
+}
+
+// isWebURL reports whether raw is an absolute http or https URL.
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
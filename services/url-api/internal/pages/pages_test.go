@@ -0,0 +1,112 @@
+package pages
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePreview_Preview(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WritePreview(rec, &Preview{
+		ShortCode:   "abc12345",
+		Destination: "https://example.com/landing",
+		Title:       "Spring sale",
+		TotalClicks: 42,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "https://example.com/landing")
+	assert.Contains(t, body, "Spring sale")
+	assert.Contains(t, body, "42 clicks")
+	assert.NotContains(t, body, "http-equiv=\"refresh\"", "explicit preview must not auto-redirect")
+}
+
+func TestWritePreview_ClicksUnavailable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WritePreview(rec, &Preview{
+		ShortCode:         "abc12345",
+		Destination:       "https://example.com/landing",
+		ClicksUnavailable: true,
+	})
+
+	require.NoError(t, err)
+	body := rec.Body.String()
+	assert.Contains(t, body, "click count unavailable")
+	assert.NotContains(t, body, "0 clicks")
+}
+
+func TestWritePreview_Countdown(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WritePreview(rec, &Preview{
+		ShortCode:   "abc12345",
+		Destination: "https://example.com/landing",
+		Countdown:   5,
+	})
+
+	require.NoError(t, err)
+	body := rec.Body.String()
+	assert.Contains(t, body, `content="5;url=https://example.com/landing"`)
+	assert.Contains(t, body, `id="countdown">5<`)
+}
+
+func TestWritePreview_EscapesContent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WritePreview(rec, &Preview{
+		ShortCode:   "abc12345",
+		Destination: "javascript:alert(1)",
+		Title:       "<script>alert(1)</script>",
+		Countdown:   5,
+	})
+
+	require.NoError(t, err)
+	body := rec.Body.String()
+	assert.NotContains(t, body, "<script>alert(1)</script>")
+	assert.NotContains(t, body, `href="javascript:`)
+	assert.NotContains(t, body, "http-equiv=\"refresh\"", "non-web destinations must not auto-redirect")
+}
+
+func TestWriteOpenGraph(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WriteOpenGraph(rec, &OpenGraph{
+		ShortUrl:    "http://localhost:8080/abc12345",
+		Destination: "https://example.com/landing",
+		Title:       "Spring sale",
+		Description: "Everything 20% off",
+		Image:       "https://example.com/og.png",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, rec.Code)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `<meta property="og:url" content="http://localhost:8080/abc12345">`)
+	assert.Contains(t, body, `<meta property="og:title" content="Spring sale">`)
+	assert.Contains(t, body, `<meta property="og:description" content="Everything 20% off">`)
+	assert.Contains(t, body, `<meta property="og:image" content="https://example.com/og.png">`)
+	assert.Contains(t, body, `<meta name="twitter:card" content="summary_large_image">`)
+	assert.Contains(t, body, `content="0;url=https://example.com/landing"`)
+}
+
+func TestWriteOpenGraph_WithoutImage(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WriteOpenGraph(rec, &OpenGraph{
+		ShortUrl:    "http://localhost:8080/abc12345",
+		Destination: "https://example.com/landing",
+		Title:       `"quoted" <title>`,
+	})
+
+	require.NoError(t, err)
+	body := rec.Body.String()
+	assert.Contains(t, body, `<meta name="twitter:card" content="summary">`)
+	assert.NotContains(t, body, "og:image")
+	assert.NotContains(t, body, `content=""quoted"`)
+	assert.NotContains(t, body, "<title>\"quoted\" <title>")
+}
@@ -0,0 +1,198 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/metric"
+	"github.com/zeromicro/go-zero/core/threading"
+	"github.com/zeromicro/go-zero/core/trace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// publishSpanName names the span covering the publishing of one click event.
+const publishSpanName = "click-event publish"
+
+const (
+	reasonQueueFull = "queue_full"
+	reasonShutdown  = "shutdown"
+)
+
+var droppedEvents = metric.NewCounterVec(&metric.CounterVecOpts{
+	Namespace: "url_api",
+	Subsystem: "click_publisher",
+	Name:      "dropped_total",
+	Help:      "Click events dropped before reaching the outbox, by reason.",
+	Labels:    []string{"reason"},
+})
+
+// Sink delivers encoded click events and runs their background delivery.
+// Spool keeps an event for later delivery without trying Kafka.
+// *outbox.Outbox naturally satisfies this interface.
+type Sink interface {
+	Publish(ctx context.Context, contentType string, value []byte) error
+	Spool(contentType string, value []byte) error
+	Start()
+	Stop()
+}
+
+type event struct {
+	ctx         context.Context
+	contentType string
+	value       []byte
+}
+
+// Publisher hands click events from request handlers to a pool of workers
+// through a bounded queue, so redirects never wait on Kafka. On Stop it
+// flushes queued events within ShutdownTimeout before stopping the sink.
+// Publisher implements service.Service so shutdown runs with the server's
+// service group.
+type Publisher struct {
+	conf  config.PublisherConf
+	sink  Sink
+	queue chan event
+
+	mu      sync.RWMutex
+	closed  bool
+	workers sync.WaitGroup
+
+	// ctx is cancelled when the shutdown deadline passes.
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// New returns a Publisher delivering to sink and starts its workers.
+func New(c config.PublisherConf, sink Sink) *Publisher {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Publisher{
+		conf:   c,
+		sink:   sink,
+		queue:  make(chan event, c.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for i := 0; i < c.Workers; i++ {
+		p.workers.Add(1)
+		threading.GoSafe(func() {
+			defer p.workers.Done()
+			p.work()
+		})
+	}
+
+	return p
+}
+
+// Enqueue queues an encoded event without blocking. It reports false, and
+// counts the event as dropped, when the queue is full or Stop was called.
+// The event is published with the values of ctx but outlives its cancellation.
+func (p *Publisher) Enqueue(ctx context.Context, contentType string, value []byte) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		droppedEvents.Inc(reasonShutdown)
+		return false
+	}
+
+	select {
+	case p.queue <- event{ctx: context.WithoutCancel(ctx), contentType: contentType, value: value}:
+		return true
+	default:
+		droppedEvents.Inc(reasonQueueFull)
+		return false
+	}
+}
+
+// Start runs the sink until Stop is called.
+func (p *Publisher) Start() {
+	p.sink.Start()
+}
+
+// Stop stops accepting events and waits up to ShutdownTimeout for queued
+// events to be published. Past the deadline, in-flight pushes are cancelled,
+// which the outbox spools for the next run, and the remaining events are
+// spooled without trying Kafka. The sink is stopped last.
+func (p *Publisher) Stop() {
+	p.once.Do(p.shutdown)
+}
+
+func (p *Publisher) shutdown() {
+	p.mu.Lock()
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	pending := len(p.queue)
+	if pending > 0 {
+		logx.Infof("Flushing %d queued click events", pending)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(time.Duration(p.conf.ShutdownTimeout) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		logx.Errorf("Click events not flushed within %dms, spooling %d queued events",
+			p.conf.ShutdownTimeout, len(p.queue))
+		p.cancel()
+		<-done
+	}
+
+	p.cancel()
+	p.sink.Stop()
+}
+
+func (p *Publisher) work() {
+	for e := range p.queue {
+		if p.ctx.Err() != nil {
+			p.spool(e)
+			continue
+		}
+
+		p.publish(e)
+	}
+}
+
+// publish hands e to the sink in a producer span of the request's trace. The
+// sink injects the span into the message headers, so the consumer continues
+// the trace.
+func (p *Publisher) publish(e event) {
+	ctx, span := trace.TracerFromContext(e.ctx).Start(e.ctx, publishSpanName,
+		oteltrace.WithSpanKind(oteltrace.SpanKindProducer),
+		oteltrace.WithAttributes(attribute.String("messaging.message.content_type", e.contentType)),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(p.ctx, cancel)
+	defer stop()
+
+	if err := p.sink.Publish(ctx, e.contentType, e.value); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		logx.WithContext(ctx).Errorw("failed to publish click event", logx.Field("error", err.Error()))
+	}
+}
+
+// spool hands e to the sink's spool once the shutdown deadline has passed.
+func (p *Publisher) spool(e event) {
+	if err := p.sink.Spool(e.contentType, e.value); err != nil {
+		droppedEvents.Inc(reasonShutdown)
+		logx.WithContext(e.ctx).Errorw("failed to spool click event", logx.Field("error", err.Error()))
+	}
+}
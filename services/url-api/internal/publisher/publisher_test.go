@@ -0,0 +1,160 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shortener/common/events"
+	"go-shortener/services/url-api/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// fakeSink records published and spooled events. While block is set, Publish
+// waits for release or for its context to be cancelled.
+type fakeSink struct {
+	mu        sync.Mutex
+	published []string
+	cancelled []string
+	spooled   []string
+	traces    []any
+	spans     []oteltrace.SpanContext
+	stopped   bool
+
+	block   bool
+	release chan struct{}
+	started chan struct{}
+}
+
+func newFakeSink(block bool) *fakeSink {
+	return &fakeSink{block: block, release: make(chan struct{}), started: make(chan struct{}, 100)}
+}
+
+func (s *fakeSink) Publish(ctx context.Context, contentType string, value []byte) error {
+	if s.block {
+		s.started <- struct{}{}
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.cancelled = append(s.cancelled, string(value))
+			s.mu.Unlock()
+			return nil
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.published = append(s.published, string(value))
+	s.traces = append(s.traces, ctx.Value(ctxKey{}))
+	s.spans = append(s.spans, oteltrace.SpanContextFromContext(ctx))
+	return nil
+}
+
+func (s *fakeSink) Spool(contentType string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spooled = append(s.spooled, string(value))
+	return nil
+}
+
+func (s *fakeSink) Start() {}
+
+func (s *fakeSink) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+}
+
+func TestPublisher_PublishesDetachedFromRequest(t *testing.T) {
+	sink := newFakeSink(false)
+	p := New(config.PublisherConf{QueueSize: 10, Workers: 2, ShutdownTimeout: 1000}, sink)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "trace"))
+	require.True(t, p.Enqueue(ctx, events.ContentTypeJSON, []byte("a")))
+	cancel()
+	p.Stop()
+
+	assert.Equal(t, []string{"a"}, sink.published)
+	assert.Equal(t, []any{"trace"}, sink.traces, "request values are kept")
+	assert.True(t, sink.stopped)
+}
+
+func TestPublisher_PublishesInProducerSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, request := provider.Tracer("test").Start(context.Background(), "redirect")
+	request.End()
+
+	sink := newFakeSink(false)
+	p := New(config.PublisherConf{QueueSize: 10, Workers: 1, ShutdownTimeout: 1000}, sink)
+	require.True(t, p.Enqueue(ctx, events.ContentTypeJSON, []byte("a")))
+	p.Stop()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	publish := spans[1]
+	assert.Equal(t, publishSpanName, publish.Name())
+	assert.Equal(t, oteltrace.SpanKindProducer, publish.SpanKind())
+	assert.Equal(t, request.SpanContext().SpanID(), publish.Parent().SpanID())
+	require.Len(t, sink.spans, 1)
+	assert.Equal(t, publish.SpanContext(), sink.spans[0], "the sink propagates the publish span")
+}
+
+func TestPublisher_FlushesQueueOnStop(t *testing.T) {
+	sink := newFakeSink(true)
+	p := New(config.PublisherConf{QueueSize: 10, Workers: 1, ShutdownTimeout: 1000}, sink)
+
+	for _, v := range []string{"1", "2", "3"} {
+		require.True(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte(v)))
+	}
+	<-sink.started
+	close(sink.release)
+	p.Stop()
+
+	assert.Equal(t, []string{"1", "2", "3"}, sink.published)
+	assert.True(t, sink.stopped)
+	assert.False(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte("4")),
+		"events are rejected after shutdown")
+}
+
+func TestPublisher_SpoolsQueueAfterDeadline(t *testing.T) {
+	sink := newFakeSink(true)
+	p := New(config.PublisherConf{QueueSize: 10, Workers: 1, ShutdownTimeout: 20}, sink)
+
+	for _, v := range []string{"1", "2", "3"} {
+		require.True(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte(v)))
+	}
+	<-sink.started
+
+	start := time.Now()
+	p.Stop()
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{"1"}, sink.cancelled, "the in-flight push is cancelled")
+	assert.Empty(t, sink.published)
+	assert.Equal(t, []string{"2", "3"}, sink.spooled, "queued events are spooled without trying Kafka")
+	assert.True(t, sink.stopped)
+}
+
+func TestPublisher_RejectsWhenQueueIsFull(t *testing.T) {
+	sink := newFakeSink(true)
+	p := New(config.PublisherConf{QueueSize: 1, Workers: 1, ShutdownTimeout: 1000}, sink)
+
+	require.True(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte("1")))
+	<-sink.started
+	require.True(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte("2")))
+	assert.False(t, p.Enqueue(context.Background(), events.ContentTypeJSON, []byte("3")))
+
+	close(sink.release)
+	p.Stop()
+	assert.Equal(t, []string{"1", "2"}, sink.published)
+}
@@ -0,0 +1,68 @@
+package safehttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_BlocksPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach a loopback destination")
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = NewClient(Options{Timeout: 2 * time.Second}).Do(req)
+	require.ErrorIs(t, err, ErrBlockedAddress)
+}
+
+func TestNewClient_RedirectLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{Timeout: 2 * time.Second, AllowPrivateNetworks: true})
+	_, err := client.Get(srv.URL + "/")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stopped after 5 redirects")
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{ip: "93.184.216.34", public: true},
+		{ip: "2606:4700::1111", public: true},
+		{ip: "127.0.0.1", public: false},
+		{ip: "10.1.2.3", public: false},
+		{ip: "172.16.0.1", public: false},
+		{ip: "192.168.1.1", public: false},
+		{ip: "169.254.169.254", public: false},
+		{ip: "0.0.0.0", public: false},
+		{ip: "0.1.2.3", public: false},
+		{ip: "100.64.0.1", public: false},
+		{ip: "100.127.255.254", public: false},
+		{ip: "::ffff:100.64.0.1", public: false},
+		{ip: "100.128.0.1", public: true},
+		{ip: "::1", public: false},
+		{ip: "fd00::1", public: false},
+		{ip: "fe80::1", public: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.public, isPublic(net.ParseIP(tt.ip)))
+		})
+	}
+}
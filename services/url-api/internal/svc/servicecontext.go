@@ -4,10 +4,17 @@
 package svc
 
 import (
+	"context"
 	"time"
 
+	"go-shortener/common/eventbus"
+	"go-shortener/common/health"
 	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/clickcount"
 	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/metadata"
+	"go-shortener/services/url-api/internal/outbox"
+	"go-shortener/services/url-api/internal/publisher"
 	"go-shortener/services/url-api/model"
 
 	_ "github.com/lib/pq"
@@ -17,14 +24,62 @@ import (
 	"github.com/zeromicro/go-zero/zrpc"
 )
 
+// MetadataFetcher abstracts destination page scraping for testability.
+// *metadata.Fetcher naturally satisfies this interface.
+type MetadataFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*metadata.Metadata, error)
+}
+
+// MetadataQueue runs metadata fetches in the background with bounded
+// concurrency and runs in the service group, which drains it on shutdown.
+// *metadata.Queue naturally satisfies this interface.
+type MetadataQueue interface {
+	Submit(ctx context.Context, run func(ctx context.Context)) bool
+	Start()
+	Stop()
+}
+
+// ClickCounter reads the click counts of links, falling back to the last
+// known counts while analytics-rpc is unavailable. *clickcount.Counter
+// naturally satisfies this interface.
+type ClickCounter interface {
+	Count(ctx context.Context, shortCode string) (clickcount.Count, error)
+	Counts(ctx context.Context, shortCodes []string) (clickcount.Counts, error)
+	Cached(shortCode string) (clickcount.Count, bool)
+}
+
+// ClickEventPublisher queues encoded click events and runs in the service
+// group for background delivery. *publisher.Publisher naturally satisfies
+// this interface.
+type ClickEventPublisher interface {
+	Enqueue(ctx context.Context, contentType string, value []byte) bool
+	Start()
+	Stop()
+}
+
 type ServiceContext struct {
-	Config       config.Config
-	UrlModel     model.UrlsModel
-	KqPusher     *kq.Pusher
-	AnalyticsRpc analyticsclient.Analytics
+	Config          config.Config
+	UrlModel        model.UrlsModel
+	UrlVariantModel model.UrlVariantsModel
+	ClickEvents     ClickEventPublisher
+	AnalyticsRpc    analyticsclient.Analytics
+	ClickCounts     ClickCounter
+	MetadataFetcher MetadataFetcher // nil when metadata fetching is disabled
+	MetadataQueue   MetadataQueue   // nil when metadata fetching is disabled
+	Health          *health.Health
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
+	client := zrpc.MustNewClient(c.AnalyticsRpc)
+	ctx := NewServiceContextWithAnalytics(c, analyticsclient.NewAnalytics(client))
+	// Only the analytics endpoints need analytics-rpc
+	ctx.Health.RegisterOptional("analytics-rpc", health.Grpc(client.Conn()))
+	return ctx
+}
+
+// NewServiceContextWithAnalytics is like NewServiceContext but uses analytics
+// instead of dialing c.AnalyticsRpc.
+func NewServiceContextWithAnalytics(c config.Config, analytics analyticsclient.Analytics) *ServiceContext {
 	conn := sqlx.NewSqlConn("postgres", c.DataSource)
 
 	// Configure connection pool
@@ -36,10 +91,44 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	logx.Infof("Connection pool configured: MaxOpen=%d, MaxIdle=%d, MaxLifetime=%ds",
 		c.Pool.MaxOpenConns, c.Pool.MaxIdleConns, c.Pool.ConnMaxLifetime)
 
+	var fetcher MetadataFetcher
+	var fetches MetadataQueue
+	if c.Metadata.Enabled {
+		fetcher = metadata.NewFetcher(metadata.Options{
+			Timeout:              time.Duration(c.Metadata.Timeout) * time.Millisecond,
+			MaxBodyBytes:         c.Metadata.MaxBodyBytes,
+			AllowPrivateNetworks: c.Metadata.AllowPrivateNetworks,
+		})
+		fetches = metadata.NewQueue(c.Metadata.Workers, c.Metadata.QueueSize)
+	}
+
+	busConf := c.EventBus
+	if busConf.DataSource == "" {
+		busConf.DataSource = c.DataSource
+	}
+	bus := eventbus.MustNew(busConf, kq.KqConf{Brokers: c.KqPusherConf.Brokers, Topic: c.KqPusherConf.Topic})
+	clickOutbox, err := outbox.New(c.Outbox, bus)
+	logx.Must(err)
+
+	clickCounts, err := clickcount.NewCounter(c.ClickCounts, analytics)
+	logx.Must(err)
+
+	checks := health.New(c.Readiness)
+	checks.Register("postgres", health.Ping(conn))
+	if busConf.Driver == eventbus.DriverKafka {
+		// Click events are spooled while Kafka is down
+		checks.RegisterOptional("kafka", health.KafkaBrokers(c.KqPusherConf.Brokers))
+	}
+
 	return &ServiceContext{
-		Config:       c,
-		UrlModel:     model.NewUrlsModel(conn),
-		KqPusher:     kq.NewPusher(c.KqPusherConf.Brokers, c.KqPusherConf.Topic),
-		AnalyticsRpc: analyticsclient.NewAnalytics(zrpc.MustNewClient(c.AnalyticsRpc)),
+		Config:          c,
+		UrlModel:        model.NewUrlsModel(conn),
+		UrlVariantModel: model.NewUrlVariantsModel(conn),
+		ClickEvents:     publisher.New(c.Publisher, clickOutbox),
+		AnalyticsRpc:    analytics,
+		ClickCounts:     clickCounts,
+		MetadataFetcher: fetcher,
+		MetadataQueue:   fetches,
+		Health:          checks,
 	}
 }
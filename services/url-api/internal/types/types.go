@@ -3,49 +3,117 @@
 
 package types
 
+type AnalyticsRequest struct {
+	Code        string `path:"code"`
+	From        int64  `form:"from,optional"`
+	To          int64  `form:"to,optional"`
+	Approximate bool   `form:"approximate,optional"`
+}
+
+type AnalyticsSummaryResponse struct {
+	ShortCode                 string           `json:"short_code"`
+	From                      int64            `json:"from,omitempty"`
+	To                        int64            `json:"to,omitempty"`
+	TotalClicks               int64            `json:"total_clicks"`
+	Countries                 []DimensionCount `json:"countries"`
+	Devices                   []DimensionCount `json:"devices"`
+	TrafficSources            []DimensionCount `json:"traffic_sources"`
+	UniqueVisitors            int64            `json:"unique_visitors"`
+	UniqueVisitorsApproximate bool             `json:"unique_visitors_approximate"`
+	UniqueVisitorsPartial     bool             `json:"unique_visitors_partial"`
+}
+
 type DeleteLinkRequest struct {
 	Code string `path:"code"`
 }
 
+type DestinationInput struct {
+	Label  string `json:"label"`
+	Url    string `json:"url"`
+	Weight int64  `json:"weight,default=1,range=[1:1000]"`
+}
+
+type DimensionCount struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
 type LinkDetailRequest struct {
 	Code string `path:"code"`
 }
 
 type LinkDetailResponse struct {
-	ShortCode   string `json:"short_code"`
-	OriginalUrl string `json:"original_url"`
-	CreatedAt   int64  `json:"created_at"`
-	TotalClicks int64  `json:"total_clicks"`
+	ShortCode       string        `json:"short_code"`
+	OriginalUrl     string        `json:"original_url"`
+	CreatedAt       int64         `json:"created_at"`
+	TotalClicks     int64         `json:"total_clicks"`
+	ClicksStatus    string        `json:"clicks_status"`
+	ClicksAsOf      int64         `json:"clicks_as_of,omitempty"`
+	Rotation        string        `json:"rotation,omitempty"`
+	Variants        []VariantItem `json:"variants,omitempty"`
+	Title           string        `json:"title,omitempty"`
+	Interstitial    bool          `json:"interstitial"`
+	OgTitle         string        `json:"og_title,omitempty"`
+	OgDescription   string        `json:"og_description,omitempty"`
+	OgImage         string        `json:"og_image,omitempty"`
+	PageTitle       string        `json:"page_title,omitempty"`
+	PageDescription string        `json:"page_description,omitempty"`
+	FaviconUrl      string        `json:"favicon_url,omitempty"`
+	Health          LinkHealth    `json:"health"`
+}
+
+type LinkHealth struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+	CheckedAt  int64  `json:"checked_at,omitempty"`
 }
 
 type LinkItem struct {
 	ShortCode   string `json:"short_code"`
 	OriginalUrl string `json:"original_url"`
 	CreatedAt   int64  `json:"created_at"`
+	PageTitle   string `json:"page_title,omitempty"`
+	FaviconUrl  string `json:"favicon_url,omitempty"`
+	TotalClicks int64  `json:"total_clicks"`
 }
 
 type LinkListRequest struct {
 	Page    int    `form:"page,default=1,range=[1:]"`
 	PerPage int    `form:"per_page,default=20,range=[1:100]"`
-	Sort    string `form:"sort,default=created_at,options=created_at|original_url"`
+	Sort    string `form:"sort,default=created_at,options=created_at|original_url|clicks"`
 	Order   string `form:"order,default=desc,options=asc|desc"`
 	Search  string `form:"search,optional"`
+	Health  string `form:"health,optional,options=healthy|broken"`
+	Cursor  string `form:"cursor,optional"`
 }
 
 type LinkListResponse struct {
-	Links      []LinkItem `json:"links"`
-	Page       int        `json:"page"`
-	PerPage    int        `json:"per_page"`
-	TotalPages int        `json:"total_pages"`
-	TotalCount int64      `json:"total_count"`
+	Links        []LinkItem `json:"links"`
+	Page         int        `json:"page"`
+	PerPage      int        `json:"per_page"`
+	TotalPages   int        `json:"total_pages"`
+	TotalCount   int64      `json:"total_count"`
+	ClicksStatus string     `json:"clicks_status"`
+	NextCursor   string     `json:"next_cursor,omitempty"`
+	PrevCursor   string     `json:"prev_cursor,omitempty"`
 }
 
 type RedirectRequest struct {
-	Code string `path:"code"`
+	Code    string `path:"code"`
+	Preview bool   `form:"preview,optional"`
 }
 
 type ShortenRequest struct {
-	OriginalUrl string `json:"original_url"`
+	OriginalUrl   string             `json:"original_url"`
+	Destinations  []DestinationInput `json:"destinations,optional"`
+	Rotation      string             `json:"rotation,optional,options=random|sticky"`
+	Title         string             `json:"title,optional"`
+	Interstitial  bool               `json:"interstitial,optional"`
+	OgTitle       string             `json:"og_title,optional"`
+	OgDescription string             `json:"og_description,optional"`
+	OgImage       string             `json:"og_image,optional"`
 }
 
 type ShortenResponse struct {
@@ -53,3 +121,61 @@ type ShortenResponse struct {
 	ShortUrl    string `json:"short_url"`
 	OriginalUrl string `json:"original_url"`
 }
+
+type TimeBucket struct {
+	Start          int64 `json:"start"`
+	Clicks         int64 `json:"clicks"`
+	UniqueVisitors int64 `json:"unique_visitors"`
+}
+
+type TimeSeriesRequest struct {
+	Code        string `path:"code"`
+	Interval    string `form:"interval,default=hour,options=minute|hour|day|week"`
+	From        int64  `form:"from,optional"`
+	To          int64  `form:"to,optional"`
+	Timezone    string `form:"tz,optional"`
+	Approximate bool   `form:"approximate,optional"`
+}
+
+type TimeSeriesResponse struct {
+	ShortCode                 string       `json:"short_code"`
+	Interval                  string       `json:"interval"`
+	Timezone                  string       `json:"timezone"`
+	From                      int64        `json:"from"`
+	To                        int64        `json:"to"`
+	Buckets                   []TimeBucket `json:"buckets"`
+	UniqueVisitorsApproximate bool         `json:"unique_visitors_approximate"`
+	UniqueVisitorsPartial     bool         `json:"unique_visitors_partial"`
+}
+
+type TopLink struct {
+	ShortCode   string `json:"short_code"`
+	ShortUrl    string `json:"short_url"`
+	OriginalUrl string `json:"original_url"`
+	PageTitle   string `json:"page_title,omitempty"`
+	FaviconUrl  string `json:"favicon_url,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	Clicks      int64  `json:"clicks"`
+}
+
+type TopLinksRequest struct {
+	Period  string `form:"period,default=24h,options=24h|7d|30d"`
+	Limit   int    `form:"limit,default=10,range=[1:100]"`
+	Country string `form:"country,optional"`
+	Device  string `form:"device,optional,options=Mobile|Desktop|Bot|Unknown"`
+	Source  string `form:"source,optional,options=Direct|Search|Social|Referral"`
+}
+
+type TopLinksResponse struct {
+	Period string    `json:"period"`
+	From   int64     `json:"from"`
+	To     int64     `json:"to"`
+	Links  []TopLink `json:"links"`
+}
+
+type VariantItem struct {
+	Label          string `json:"label"`
+	DestinationUrl string `json:"destination_url"`
+	Weight         int64  `json:"weight"`
+	Clicks         int64  `json:"clicks"`
+}
@@ -3,19 +3,28 @@ package model
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // MockUrlsModel is a test mock for UrlsModel interface.
 type MockUrlsModel struct {
-	FindOneFunc            func(ctx context.Context, id string) (*Urls, error)
-	FindOneByShortCodeFunc func(ctx context.Context, shortCode string) (*Urls, error)
-	InsertFunc             func(ctx context.Context, data *Urls) (sql.Result, error)
-	UpdateFunc             func(ctx context.Context, data *Urls) error
-	DeleteFunc             func(ctx context.Context, id string) error
-	ListWithPaginationFunc func(ctx context.Context, page, pageSize int, search, sort, order string) ([]*Urls, int64, error)
-	WithSessionFunc        func(session sqlx.Session) UrlsModel
+	FindOneFunc                func(ctx context.Context, id string) (*Urls, error)
+	FindOneByShortCodeFunc     func(ctx context.Context, shortCode string) (*Urls, error)
+	InsertFunc                 func(ctx context.Context, data *Urls) (sql.Result, error)
+	UpdateFunc                 func(ctx context.Context, data *Urls) error
+	DeleteFunc                 func(ctx context.Context, id string) error
+	ListWithPaginationFunc     func(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*Urls, int64, error)
+	ListWithKeysetFunc         func(ctx context.Context, key *ListKey, backward bool, limit int, search, sort, order, health string) ([]*Urls, error)
+	CountListedFunc            func(ctx context.Context, search, health string) (int64, error)
+	FindListedByShortCodesFunc func(ctx context.Context, shortCodes []string, search, health string) ([]*Urls, error)
+	InsertWithVariantsFunc     func(ctx context.Context, data *Urls, variants []*UrlVariants) error
+	UpdatePageMetadataFunc     func(ctx context.Context, id, title, description, faviconUrl string) error
+	ClaimDueForHealthCheckFunc func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Urls, error)
+	UpdateHealthFunc           func(ctx context.Context, data *Urls) error
+	FindByShortCodesFunc       func(ctx context.Context, shortCodes []string) ([]*Urls, error)
+	WithSessionFunc            func(session sqlx.Session) UrlsModel
 }
 
 // Ensure MockUrlsModel implements UrlsModel interface
@@ -56,13 +65,69 @@ func (m *MockUrlsModel) Delete(ctx context.Context, id string) error {
 	panic("MockUrlsModel.DeleteFunc not set")
 }
 
-func (m *MockUrlsModel) ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order string) ([]*Urls, int64, error) {
+func (m *MockUrlsModel) ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*Urls, int64, error) {
 	if m.ListWithPaginationFunc != nil {
-		return m.ListWithPaginationFunc(ctx, page, pageSize, search, sort, order)
+		return m.ListWithPaginationFunc(ctx, page, pageSize, search, sort, order, health)
 	}
 	panic("MockUrlsModel.ListWithPaginationFunc not set")
 }
 
+func (m *MockUrlsModel) ListWithKeyset(ctx context.Context, key *ListKey, backward bool, limit int, search, sort, order, health string) ([]*Urls, error) {
+	if m.ListWithKeysetFunc != nil {
+		return m.ListWithKeysetFunc(ctx, key, backward, limit, search, sort, order, health)
+	}
+	panic("MockUrlsModel.ListWithKeysetFunc not set")
+}
+
+func (m *MockUrlsModel) CountListed(ctx context.Context, search, health string) (int64, error) {
+	if m.CountListedFunc != nil {
+		return m.CountListedFunc(ctx, search, health)
+	}
+	panic("MockUrlsModel.CountListedFunc not set")
+}
+
+func (m *MockUrlsModel) FindListedByShortCodes(ctx context.Context, shortCodes []string, search, health string) ([]*Urls, error) {
+	if m.FindListedByShortCodesFunc != nil {
+		return m.FindListedByShortCodesFunc(ctx, shortCodes, search, health)
+	}
+	panic("MockUrlsModel.FindListedByShortCodesFunc not set")
+}
+
+func (m *MockUrlsModel) InsertWithVariants(ctx context.Context, data *Urls, variants []*UrlVariants) error {
+	if m.InsertWithVariantsFunc != nil {
+		return m.InsertWithVariantsFunc(ctx, data, variants)
+	}
+	panic("MockUrlsModel.InsertWithVariantsFunc not set")
+}
+
+func (m *MockUrlsModel) UpdatePageMetadata(ctx context.Context, id, title, description, faviconUrl string) error {
+	if m.UpdatePageMetadataFunc != nil {
+		return m.UpdatePageMetadataFunc(ctx, id, title, description, faviconUrl)
+	}
+	panic("MockUrlsModel.UpdatePageMetadataFunc not set")
+}
+
+func (m *MockUrlsModel) ClaimDueForHealthCheck(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Urls, error) {
+	if m.ClaimDueForHealthCheckFunc != nil {
+		return m.ClaimDueForHealthCheckFunc(ctx, now, leaseUntil, limit)
+	}
+	panic("MockUrlsModel.ClaimDueForHealthCheckFunc not set")
+}
+
+func (m *MockUrlsModel) UpdateHealth(ctx context.Context, data *Urls) error {
+	if m.UpdateHealthFunc != nil {
+		return m.UpdateHealthFunc(ctx, data)
+	}
+	panic("MockUrlsModel.UpdateHealthFunc not set")
+}
+
+func (m *MockUrlsModel) FindByShortCodes(ctx context.Context, shortCodes []string) ([]*Urls, error) {
+	if m.FindByShortCodesFunc != nil {
+		return m.FindByShortCodesFunc(ctx, shortCodes)
+	}
+	panic("MockUrlsModel.FindByShortCodesFunc not set")
+}
+
 func (m *MockUrlsModel) withSession(session sqlx.Session) UrlsModel {
 	if m.WithSessionFunc != nil {
 		return m.WithSessionFunc(session)
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// MockUrlVariantsModel is a test mock for UrlVariantsModel interface.
+type MockUrlVariantsModel struct {
+	InsertFunc                  func(ctx context.Context, data *UrlVariants) (sql.Result, error)
+	FindOneFunc                 func(ctx context.Context, id string) (*UrlVariants, error)
+	FindOneByShortCodeLabelFunc func(ctx context.Context, shortCode string, label string) (*UrlVariants, error)
+	UpdateFunc                  func(ctx context.Context, data *UrlVariants) error
+	DeleteFunc                  func(ctx context.Context, id string) error
+	FindAllByShortCodeFunc      func(ctx context.Context, shortCode string) ([]*UrlVariants, error)
+	WithSessionFunc             func(session sqlx.Session) UrlVariantsModel
+}
+
+// Ensure MockUrlVariantsModel implements UrlVariantsModel interface
+var _ UrlVariantsModel = (*MockUrlVariantsModel)(nil)
+
+func (m *MockUrlVariantsModel) Insert(ctx context.Context, data *UrlVariants) (sql.Result, error) {
+	if m.InsertFunc != nil {
+		return m.InsertFunc(ctx, data)
+	}
+	panic("MockUrlVariantsModel.InsertFunc not set")
+}
+
+func (m *MockUrlVariantsModel) FindOne(ctx context.Context, id string) (*UrlVariants, error) {
+	if m.FindOneFunc != nil {
+		return m.FindOneFunc(ctx, id)
+	}
+	panic("MockUrlVariantsModel.FindOneFunc not set")
+}
+
+func (m *MockUrlVariantsModel) FindOneByShortCodeLabel(ctx context.Context, shortCode string, label string) (*UrlVariants, error) {
+	if m.FindOneByShortCodeLabelFunc != nil {
+		return m.FindOneByShortCodeLabelFunc(ctx, shortCode, label)
+	}
+	panic("MockUrlVariantsModel.FindOneByShortCodeLabelFunc not set")
+}
+
+func (m *MockUrlVariantsModel) Update(ctx context.Context, data *UrlVariants) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, data)
+	}
+	panic("MockUrlVariantsModel.UpdateFunc not set")
+}
+
+func (m *MockUrlVariantsModel) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	panic("MockUrlVariantsModel.DeleteFunc not set")
+}
+
+func (m *MockUrlVariantsModel) FindAllByShortCode(ctx context.Context, shortCode string) ([]*UrlVariants, error) {
+	if m.FindAllByShortCodeFunc != nil {
+		return m.FindAllByShortCodeFunc(ctx, shortCode)
+	}
+	panic("MockUrlVariantsModel.FindAllByShortCodeFunc not set")
+}
+
+func (m *MockUrlVariantsModel) withSession(session sqlx.Session) UrlVariantsModel {
+	if m.WithSessionFunc != nil {
+		return m.WithSessionFunc(session)
+	}
+	panic("MockUrlVariantsModel.WithSessionFunc not set")
+}
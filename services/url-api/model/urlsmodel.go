@@ -3,25 +3,51 @@ package model
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 var _ UrlsModel = (*customUrlsModel)(nil)
 
+// Health classification of the last check. A status code of 0 means the
+// request itself failed (DNS, connection refused, timeout, ...).
+const (
+	healthBrokenCondition  = "health_checked_at IS NOT NULL AND (health_status_code = 0 OR health_status_code >= 400)"
+	healthHealthyCondition = "health_checked_at IS NOT NULL AND health_status_code BETWEEN 200 AND 399"
+)
+
 type (
 	// UrlsModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customUrlsModel.
 	UrlsModel interface {
 		urlsModel
 		withSession(session sqlx.Session) UrlsModel
-		ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order string) ([]*Urls, int64, error)
+		ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*Urls, int64, error)
+		ListWithKeyset(ctx context.Context, key *ListKey, backward bool, limit int, search, sort, order, health string) ([]*Urls, error)
+		CountListed(ctx context.Context, search, health string) (int64, error)
+		FindListedByShortCodes(ctx context.Context, shortCodes []string, search, health string) ([]*Urls, error)
+		InsertWithVariants(ctx context.Context, data *Urls, variants []*UrlVariants) error
+		UpdatePageMetadata(ctx context.Context, id, title, description, faviconUrl string) error
+		ClaimDueForHealthCheck(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Urls, error)
+		UpdateHealth(ctx context.Context, data *Urls) error
+		FindByShortCodes(ctx context.Context, shortCodes []string) ([]*Urls, error)
 	}
 
 	customUrlsModel struct {
 		*defaultUrlsModel
 	}
+
+	// ListKey is the position of a URL in the links list: its sort value
+	// and id, which breaks ties.
+	ListKey struct {
+		CreatedAt   time.Time
+		OriginalUrl string
+		Id          string
+	}
 )
 
 // NewUrlsModel returns a model for the database table.
@@ -35,24 +61,11 @@ func (m *customUrlsModel) withSession(session sqlx.Session) UrlsModel {
 	return NewUrlsModel(sqlx.NewSqlConnFromSession(session))
 }
 
-// ListWithPagination returns a paginated list of URLs with optional search filtering.
-// Uses OFFSET/LIMIT pagination matching the current API contract (page, per_page, sort, order, search).
-func (m *customUrlsModel) ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order string) ([]*Urls, int64, error) {
-	// Build WHERE clause for search
-	conditions := make([]string, 0)
-	args := make([]interface{}, 0)
-	argIdx := 1
-
-	if search != "" {
-		conditions = append(conditions, fmt.Sprintf("original_url ILIKE $%d", argIdx))
-		args = append(args, search+"%")
-		argIdx++
-	}
-
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(conditions, " AND ")
-	}
+// ListWithPagination returns a paginated list of URLs with optional search and health filtering.
+// Uses OFFSET/LIMIT pagination matching the current API contract (page, per_page, sort, order, search, health).
+func (m *customUrlsModel) ListWithPagination(ctx context.Context, page, pageSize int, search, sort, order, health string) ([]*Urls, int64, error) {
+	whereClause, args := listWhere(search, health)
+	argIdx := len(args) + 1
 
 	// Count total matching records
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", m.table, whereClause)
@@ -62,28 +75,15 @@ func (m *customUrlsModel) ListWithPagination(ctx context.Context, page, pageSize
 		return nil, 0, err
 	}
 
-	// Validate sort column (whitelist to prevent SQL injection)
-	var sortColumn string
-	switch sort {
-	case "original_url":
-		sortColumn = "original_url"
-	default:
-		sortColumn = "created_at"
-	}
-
-	// Validate order direction
-	orderDir := "DESC"
-	if strings.ToUpper(order) == "ASC" {
-		orderDir = "ASC"
-	}
+	sortColumn, orderDir := listOrder(sort, order)
 
 	// Calculate offset
 	offset := (page - 1) * pageSize
 
 	// Build data query
 	dataQuery := fmt.Sprintf(
-		"SELECT %s FROM %s%s ORDER BY %s %s LIMIT $%d OFFSET $%d",
-		urlsRows, m.table, whereClause, sortColumn, orderDir, argIdx, argIdx+1,
+		"SELECT %s FROM %s%s ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d",
+		urlsRows, m.table, whereClause, sortColumn, orderDir, orderDir, argIdx, argIdx+1,
 	)
 	dataArgs := append(args, pageSize, offset)
 
@@ -95,3 +95,208 @@ func (m *customUrlsModel) ListWithPagination(ctx context.Context, page, pageSize
 
 	return resp, totalCount, nil
 }
+
+// ListWithKeyset returns up to limit URLs with the search and health
+// filtering of ListWithPagination, starting after key in list order, or from
+// the start when key is nil. Walking backward, it returns the URLs just
+// before key instead, still in list order. Unlike OFFSET pagination, pages
+// neither repeat nor skip links created while paging.
+func (m *customUrlsModel) ListWithKeyset(ctx context.Context, key *ListKey, backward bool, limit int, search, sort, order, health string) ([]*Urls, error) {
+	whereClause, args := listWhere(search, health)
+	sortColumn, orderDir := listOrder(sort, order)
+
+	// Walking backward scans in reverse and flips the rows afterwards.
+	scanDir := orderDir
+	if backward {
+		scanDir = reverseDir(orderDir)
+	}
+
+	if key != nil {
+		var value any = key.CreatedAt
+		if sortColumn == "original_url" {
+			value = key.OriginalUrl
+		}
+		cmp := ">"
+		if scanDir == "DESC" {
+			cmp = "<"
+		}
+		args = append(args, value, key.Id)
+		// The id breaks ties between links sharing a sort value.
+		whereClause = andWhere(whereClause,
+			fmt.Sprintf("(%s, id) %s ($%d, $%d::uuid)", sortColumn, cmp, len(args)-1, len(args)))
+	}
+
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s %s, id %s LIMIT $%d",
+		urlsRows, m.table, whereClause, sortColumn, scanDir, scanDir, len(args))
+	var resp []*Urls
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if backward {
+		slices.Reverse(resp)
+	}
+	return resp, nil
+}
+
+// CountListed returns how many URLs match the search and health filters of
+// ListWithPagination.
+func (m *customUrlsModel) CountListed(ctx context.Context, search, health string) (int64, error) {
+	whereClause, args := listWhere(search, health)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", m.table, whereClause)
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, args...)
+	return count, err
+}
+
+// FindListedByShortCodes returns the URLs among shortCodes that match the
+// search and health filters of ListWithPagination, in no particular order.
+func (m *customUrlsModel) FindListedByShortCodes(ctx context.Context, shortCodes []string, search, health string) ([]*Urls, error) {
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+	whereClause, args := listWhere(search, health)
+	args = append(args, pq.Array(shortCodes))
+	whereClause = andWhere(whereClause, fmt.Sprintf("short_code = ANY($%d)", len(args)))
+	query := fmt.Sprintf("SELECT %s FROM %s%s", urlsRows, m.table, whereClause)
+	var resp []*Urls
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, err
+}
+
+// listWhere builds the WHERE clause, if any, applying the search and health
+// filters of the links list.
+func listWhere(search, health string) (string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	if search != "" {
+		args = append(args, search+"%")
+		conditions = append(conditions, fmt.Sprintf("original_url ILIKE $%d", len(args)))
+	}
+
+	switch health {
+	case HealthBroken:
+		conditions = append(conditions, healthBrokenCondition)
+	case HealthHealthy:
+		conditions = append(conditions, healthHealthyCondition)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// andWhere adds condition to whereClause as built by listWhere.
+func andWhere(whereClause, condition string) string {
+	if whereClause == "" {
+		return " WHERE " + condition
+	}
+	return whereClause + " AND " + condition
+}
+
+// listOrder whitelists the sort column and order direction of the links list
+// to prevent SQL injection, defaulting to newest first.
+func listOrder(sort, order string) (string, string) {
+	sortColumn := "created_at"
+	if sort == "original_url" {
+		sortColumn = "original_url"
+	}
+
+	orderDir := "DESC"
+	if strings.ToUpper(order) == "ASC" {
+		orderDir = "ASC"
+	}
+	return sortColumn, orderDir
+}
+
+func reverseDir(dir string) string {
+	if dir == "ASC" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// InsertWithVariants inserts a URL together with its weighted destinations in a
+// single transaction, so a link is never visible with a partial set of variants.
+func (m *customUrlsModel) InsertWithVariants(ctx context.Context, data *Urls, variants []*UrlVariants) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := m.withSession(session).Insert(ctx, data); err != nil {
+			return err
+		}
+
+		variantModel := NewUrlVariantsModel(sqlx.NewSqlConnFromSession(session))
+		for _, v := range variants {
+			if _, err := variantModel.Insert(ctx, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UpdatePageMetadata stores the title, description and favicon scraped from a
+// link's destination and stamps metadata_fetched_at, leaving all other columns
+// untouched so it cannot race with concurrent edits of the link itself.
+func (m *customUrlsModel) UpdatePageMetadata(ctx context.Context, id, title, description, faviconUrl string) error {
+	query := fmt.Sprintf("UPDATE %s SET page_title = $1, page_description = $2, favicon_url = $3, metadata_fetched_at = NOW() WHERE id = $4", m.table)
+	_, err := m.conn.ExecCtx(ctx, query, title, description, faviconUrl, id)
+	return err
+}
+
+// ClaimDueForHealthCheck claims up to limit links whose next health check is
+// due, never-checked links first, by moving their next check to leaseUntil.
+// Rows locked by a concurrent claim are skipped, so replicas never probe the
+// same link at once; a link whose checker dies is claimed again once the
+// lease runs out.
+func (m *customUrlsModel) ClaimDueForHealthCheck(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Urls, error) {
+	query := fmt.Sprintf(
+		"UPDATE %[1]s SET health_next_check_at = $2 WHERE id IN ("+
+			"SELECT id FROM %[1]s WHERE health_next_check_at IS NULL OR health_next_check_at <= $1 "+
+			"ORDER BY health_next_check_at NULLS FIRST LIMIT $3 FOR UPDATE SKIP LOCKED"+
+			") RETURNING %[2]s",
+		m.table, urlsRows,
+	)
+	var resp []*Urls
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, now, leaseUntil, limit)
+	return resp, err
+}
+
+// UpdateHealth stores the health_* columns of data, leaving the rest of the
+// link untouched.
+func (m *customUrlsModel) UpdateHealth(ctx context.Context, data *Urls) error {
+	query := fmt.Sprintf(
+		"UPDATE %s SET health_status_code = $1, health_latency_ms = $2, health_error = $3, health_failures = $4, health_checked_at = $5, health_next_check_at = $6 WHERE id = $7",
+		m.table,
+	)
+	_, err := m.conn.ExecCtx(ctx, query, data.HealthStatusCode, data.HealthLatencyMs, data.HealthError,
+		data.HealthFailures, data.HealthCheckedAt, data.HealthNextCheckAt, data.Id)
+	return err
+}
+
+// FindByShortCodes returns the links among shortCodes, in no particular order.
+// Unknown short codes are skipped.
+func (m *customUrlsModel) FindByShortCodes(ctx context.Context, shortCodes []string) ([]*Urls, error) {
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE short_code = ANY($1)", urlsRows, m.table)
+	var resp []*Urls
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, pq.Array(shortCodes))
+	return resp, err
+}
+
+// Health reports the destination health from the last check.
+func (u *Urls) Health() string {
+	switch {
+	case !u.HealthCheckedAt.Valid:
+		return HealthUnchecked
+	case u.HealthStatusCode == 0 || u.HealthStatusCode >= 400:
+		return HealthBroken
+	default:
+		return HealthHealthy
+	}
+}
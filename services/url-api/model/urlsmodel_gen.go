@@ -38,11 +38,27 @@ type (
 	}
 
 	Urls struct {
-		Id          string    `db:"id"`
-		ShortCode   string    `db:"short_code"`
-		OriginalUrl string    `db:"original_url"`
-		ClickCount  int64     `db:"click_count"`
-		CreatedAt   time.Time `db:"created_at"`
+		Id                string       `db:"id"`
+		ShortCode         string       `db:"short_code"`
+		OriginalUrl       string       `db:"original_url"`
+		ClickCount        int64        `db:"click_count"`
+		CreatedAt         time.Time    `db:"created_at"`
+		Rotation          string       `db:"rotation"`
+		Title             string       `db:"title"`
+		Interstitial      bool         `db:"interstitial"`
+		OgTitle           string       `db:"og_title"`
+		OgDescription     string       `db:"og_description"`
+		OgImage           string       `db:"og_image"`
+		PageTitle         string       `db:"page_title"`
+		PageDescription   string       `db:"page_description"`
+		FaviconUrl        string       `db:"favicon_url"`
+		MetadataFetchedAt sql.NullTime `db:"metadata_fetched_at"`
+		HealthStatusCode  int64        `db:"health_status_code"`
+		HealthLatencyMs   int64        `db:"health_latency_ms"`
+		HealthError       string       `db:"health_error"`
+		HealthFailures    int64        `db:"health_failures"`
+		HealthCheckedAt   sql.NullTime `db:"health_checked_at"`
+		HealthNextCheckAt sql.NullTime `db:"health_next_check_at"`
 	}
 )
 
@@ -88,14 +104,14 @@ func (m *defaultUrlsModel) FindOneByShortCode(ctx context.Context, shortCode str
 }
 
 func (m *defaultUrlsModel) Insert(ctx context.Context, data *Urls) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values ($1, $2, $3, $4)", m.table, urlsRowsExpectAutoSet)
-	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.OriginalUrl, data.ClickCount)
+	query := fmt.Sprintf("insert into %s (%s) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)", m.table, urlsRowsExpectAutoSet)
+	ret, err := m.conn.ExecCtx(ctx, query, data.Id, data.ShortCode, data.OriginalUrl, data.ClickCount, data.Rotation, data.Title, data.Interstitial, data.OgTitle, data.OgDescription, data.OgImage, data.PageTitle, data.PageDescription, data.FaviconUrl, data.MetadataFetchedAt, data.HealthStatusCode, data.HealthLatencyMs, data.HealthError, data.HealthFailures, data.HealthCheckedAt, data.HealthNextCheckAt)
 	return ret, err
 }
 
 func (m *defaultUrlsModel) Update(ctx context.Context, newData *Urls) error {
 	query := fmt.Sprintf("update %s set %s where id = $1", m.table, urlsRowsWithPlaceHolder)
-	_, err := m.conn.ExecCtx(ctx, query, newData.Id, newData.ShortCode, newData.OriginalUrl, newData.ClickCount)
+	_, err := m.conn.ExecCtx(ctx, query, newData.Id, newData.ShortCode, newData.OriginalUrl, newData.ClickCount, newData.Rotation, newData.Title, newData.Interstitial, newData.OgTitle, newData.OgDescription, newData.OgImage, newData.PageTitle, newData.PageDescription, newData.FaviconUrl, newData.MetadataFetchedAt, newData.HealthStatusCode, newData.HealthLatencyMs, newData.HealthError, newData.HealthFailures, newData.HealthCheckedAt, newData.HealthNextCheckAt)
 	return err
 }
 
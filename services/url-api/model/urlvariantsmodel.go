@@ -0,0 +1,47 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ UrlVariantsModel = (*customUrlVariantsModel)(nil)
+
+type (
+	// UrlVariantsModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customUrlVariantsModel.
+	UrlVariantsModel interface {
+		urlVariantsModel
+		withSession(session sqlx.Session) UrlVariantsModel
+		FindAllByShortCode(ctx context.Context, shortCode string) ([]*UrlVariants, error)
+	}
+
+	customUrlVariantsModel struct {
+		*defaultUrlVariantsModel
+	}
+)
+
+// NewUrlVariantsModel returns a model for the database table.
+func NewUrlVariantsModel(conn sqlx.SqlConn) UrlVariantsModel {
+	return &customUrlVariantsModel{
+		defaultUrlVariantsModel: newUrlVariantsModel(conn),
+	}
+}
+
+func (m *customUrlVariantsModel) withSession(session sqlx.Session) UrlVariantsModel {
+	return NewUrlVariantsModel(sqlx.NewSqlConnFromSession(session))
+}
+
+// FindAllByShortCode returns all weighted destinations of a link, ordered by label
+// so that weight buckets are stable across requests.
+func (m *customUrlVariantsModel) FindAllByShortCode(ctx context.Context, shortCode string) ([]*UrlVariants, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE short_code = $1 ORDER BY label", urlVariantsRows, m.table)
+	var resp []*UrlVariants
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, shortCode)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
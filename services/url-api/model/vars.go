@@ -3,3 +3,17 @@ package model
 import "github.com/zeromicro/go-zero/core/stores/sqlx"
 
 var ErrNotFound = sqlx.ErrNotFound
+
+// Rotation strategies for links with multiple weighted destinations.
+// An empty rotation means the link has a single destination (original_url).
+const (
+	RotationRandom = "random"
+	RotationSticky = "sticky"
+)
+
+// Destination health states derived from the last health check.
+const (
+	HealthUnchecked = "unchecked"
+	HealthHealthy   = "healthy"
+	HealthBroken    = "broken"
+)
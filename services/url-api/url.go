@@ -4,20 +4,13 @@
 package main
 
 import (
-	"context"
-	"errors"
 	"flag"
 	"fmt"
-	"net/http"
+	_ "time/tzdata" // embed the IANA database for analytics timezones
 
-	"go-shortener/pkg/problemdetails"
-	"go-shortener/services/url-api/internal/config"
-	"go-shortener/services/url-api/internal/handler"
-	"go-shortener/services/url-api/internal/svc"
+	"go-shortener/services/url-api/urlapi"
 
 	"github.com/zeromicro/go-zero/core/conf"
-	"github.com/zeromicro/go-zero/rest"
-	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
 var configFile = flag.String("f", "etc/url.yaml", "the config file")
@@ -25,44 +18,12 @@ var configFile = flag.String("f", "etc/url.yaml", "the config file")
 func main() {
 	flag.Parse()
 
-	var c config.Config
+	var c urlapi.Config
 	conf.MustLoad(*configFile, &c)
 
-	server := rest.MustNewServer(c.RestConf)
-	defer server.Stop()
-
-	ctx := svc.NewServiceContext(c)
-	handler.RegisterHandlers(server, ctx)
-
-	// Health check endpoint
-	server.AddRoute(rest.Route{
-		Method: http.MethodGet,
-		Path:   "/healthz",
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("ok"))
-		}),
-	})
-
-	// Custom RFC 7807 error handler
-	// Note: go-zero's doHandleError checks if the returned body implements error,
-	// and if so writes it as plaintext. We return a problemDetailsBody (non-error)
-	// struct to ensure JSON serialization.
-	httpx.SetErrorHandlerCtx(func(ctx context.Context, err error) (int, interface{}) {
-		var pd *problemdetails.ProblemDetail
-		if errors.As(err, &pd) {
-			return pd.Status, pd.Body()
-		}
-
-		problem := problemdetails.New(
-			http.StatusBadRequest,
-			problemdetails.TypeValidationError,
-			"Bad Request",
-			err.Error(),
-		)
-		return problem.Status, problem.Body()
-	})
+	group := urlapi.MustNew(c)
+	defer group.Stop()
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
-	server.Start()
+	group.Start()
 }
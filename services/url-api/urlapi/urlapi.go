@@ -0,0 +1,95 @@
+// Package urlapi wires url-api so it runs on its own or inside the allinone
+// binary.
+package urlapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-shortener/common/health"
+	"go-shortener/pkg/problemdetails"
+	"go-shortener/services/analytics-rpc/analyticsclient"
+	"go-shortener/services/url-api/internal/config"
+	"go-shortener/services/url-api/internal/handler"
+	"go-shortener/services/url-api/internal/healthcheck"
+	"go-shortener/services/url-api/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/service"
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// Config is the url-api configuration.
+type Config = config.Config
+
+type options struct {
+	analytics analyticsclient.Analytics
+}
+
+// Option customizes how MustNew wires url-api.
+type Option func(*options)
+
+// WithAnalytics uses client for analytics instead of dialing analytics-rpc.
+func WithAnalytics(client analyticsclient.Analytics) Option {
+	return func(o *options) {
+		o.analytics = client
+	}
+}
+
+// MustNew returns the HTTP server and the background services of url-api as
+// one group, exiting on error.
+func MustNew(c Config, opts ...Option) *service.ServiceGroup {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	server := rest.MustNewServer(c.RestConf)
+
+	var ctx *svc.ServiceContext
+	if o.analytics != nil {
+		ctx = svc.NewServiceContextWithAnalytics(c, o.analytics)
+	} else {
+		ctx = svc.NewServiceContext(c)
+	}
+	handler.RegisterHandlers(server, ctx)
+
+	// Liveness and readiness probes
+	server.AddRoutes([]rest.Route{
+		{Method: http.MethodGet, Path: health.LegacyPath, Handler: ctx.Health.LivenessHandler()},
+		{Method: http.MethodGet, Path: health.LivePath, Handler: ctx.Health.LivenessHandler()},
+		{Method: http.MethodGet, Path: health.ReadyPath, Handler: ctx.Health.ReadinessHandler()},
+	})
+
+	// Custom RFC 7807 error handler
+	// Note: go-zero's doHandleError checks if the returned body implements error,
+	// and if so writes it as plaintext. We return a problemDetailsBody (non-error)
+	// struct to ensure JSON serialization.
+	httpx.SetErrorHandlerCtx(func(ctx context.Context, err error) (int, interface{}) {
+		var pd *problemdetails.ProblemDetail
+		if errors.As(err, &pd) {
+			return pd.Status, pd.Body()
+		}
+
+		problem := problemdetails.New(
+			http.StatusBadRequest,
+			problemdetails.TypeValidationError,
+			"Bad Request",
+			err.Error(),
+		)
+		return problem.Status, problem.Body()
+	})
+
+	group := service.NewServiceGroup()
+	group.Add(server)
+	group.Add(ctx.ClickEvents)
+	if ctx.MetadataQueue != nil {
+		group.Add(ctx.MetadataQueue)
+	}
+	if c.HealthCheck.Enabled {
+		group.Add(healthcheck.NewChecker(c.HealthCheck, ctx.UrlModel, ctx.UrlVariantModel))
+	}
+
+	return group
+}
@@ -0,0 +1,241 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-shortener/common/eventbus"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-queue/kq"
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+// countingHandler counts how often each value is consumed.
+type countingHandler struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (h *countingHandler) Consume(ctx context.Context, msg eventbus.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[msg.Value]++
+	return nil
+}
+
+func (h *countingHandler) total() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.counts)
+}
+
+func TestPostgresEventBusIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	conf := eventbus.Conf{Driver: eventbus.DriverPostgres, PollInterval: 10, BatchSize: 5, Lease: 30000}
+	kc := kq.KqConf{Topic: "click-events", Processors: 4}
+	bus := eventbus.NewPostgresBus(conn, conf, kc)
+	other := eventbus.NewPostgresBus(conn, conf, kq.KqConf{Topic: "other-events", Processors: 1})
+	ctx := context.Background()
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		require.NoError(t, bus.Push(ctx, eventbus.Message{Value: fmt.Sprintf("event-%d", i)}))
+	}
+	require.NoError(t, other.Push(ctx, eventbus.Message{Value: "other"}))
+
+	// Two consumers share the topic without handling an event twice
+	handler := &countingHandler{counts: make(map[string]int)}
+	var wg sync.WaitGroup
+	var consumers []service.Service
+	for i := 0; i < 2; i++ {
+		consumer, err := bus.Subscribe(handler)
+		require.NoError(t, err)
+		consumers = append(consumers, consumer)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consumer.Start()
+		}()
+	}
+
+	require.Eventually(t, func() bool { return handler.total() == n }, 10*time.Second, 10*time.Millisecond)
+	for _, consumer := range consumers {
+		consumer.Stop()
+	}
+	wg.Wait()
+
+	for value, count := range handler.counts {
+		assert.Equal(t, 1, count, value)
+	}
+	assert.NotContains(t, handler.counts, "other")
+
+	var remaining []string
+	require.NoError(t, conn.QueryRowsCtx(ctx, &remaining, `SELECT topic FROM event_queue`))
+	assert.Equal(t, []string{"other-events"}, remaining)
+}
+
+// failingHandler fails the first delivery of each value.
+type failingHandler struct {
+	countingHandler
+}
+
+func (h *failingHandler) Consume(ctx context.Context, msg eventbus.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[msg.Value]++
+	if h.counts[msg.Value] == 1 {
+		return errors.New("store unavailable")
+	}
+	return nil
+}
+
+func (h *failingHandler) deliveries(value string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.counts[value]
+}
+
+func TestPostgresEventBusRetriesFailedEventsIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	conf := eventbus.Conf{Driver: eventbus.DriverPostgres, PollInterval: 10, BatchSize: 5, RetryBackoff: 500, MaxBackoff: 1000,
+		Lease: 30000}
+	bus := eventbus.NewPostgresBus(conn, conf, kq.KqConf{Topic: "retry-events", Processors: 1})
+	ctx := context.Background()
+	require.NoError(t, bus.Push(ctx, eventbus.Message{Value: "a"}))
+
+	handler := &failingHandler{countingHandler{counts: make(map[string]int)}}
+	consumer, err := bus.Subscribe(handler)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		consumer.Start()
+		close(done)
+	}()
+	defer func() {
+		consumer.Stop()
+		<-done
+	}()
+
+	// The failed event stays queued and is hidden until its retry is due
+	require.Eventually(t, func() bool {
+		var attempts []int
+		require.NoError(t, conn.QueryRowsCtx(ctx, &attempts,
+			`SELECT attempts FROM event_queue WHERE topic = 'retry-events' AND visible_at > NOW()`))
+		return len(attempts) == 1 && attempts[0] == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, handler.deliveries("a"))
+
+	require.Eventually(t, func() bool {
+		var remaining []int64
+		require.NoError(t, conn.QueryRowsCtx(ctx, &remaining, `SELECT id FROM event_queue WHERE topic = 'retry-events'`))
+		return len(remaining) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, handler.deliveries("a"))
+}
+
+// blockingHandler fails every event, after waiting for release if set.
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) Consume(ctx context.Context, msg eventbus.Message) error {
+	if h.release != nil {
+		h.started <- struct{}{}
+		<-h.release
+	}
+	return errors.New("store unavailable")
+}
+
+func TestPostgresEventBusLeasesClaimedEventsIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	conf := eventbus.Conf{Driver: eventbus.DriverPostgres, PollInterval: 10, BatchSize: 5, RetryBackoff: 60000, MaxBackoff: 60000,
+		Lease: 30000}
+	bus := eventbus.NewPostgresBus(conn, conf, kq.KqConf{Topic: "lease-events", Processors: 1})
+	ctx := context.Background()
+	require.NoError(t, bus.Push(ctx, eventbus.Message{Value: "a"}))
+
+	handler := &blockingHandler{started: make(chan struct{}), release: make(chan struct{})}
+	consumer, err := bus.Subscribe(handler)
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		consumer.Start()
+		close(done)
+	}()
+	defer func() {
+		consumer.Stop()
+		<-done
+	}()
+	<-handler.started
+
+	// The claim is committed while the handler runs, hiding the event from
+	// other consumers for the lease
+	var leased []bool
+	require.NoError(t, conn.QueryRowsCtx(ctx, &leased,
+		`SELECT visible_at > NOW() + INTERVAL '20 seconds' FROM event_queue WHERE topic = 'lease-events' AND attempts = 1`))
+	assert.Equal(t, []bool{true}, leased)
+
+	other, err := bus.Subscribe(&countingHandler{counts: make(map[string]int)})
+	require.NoError(t, err)
+	runner, ok := other.(interface{ RunOnce(context.Context) int })
+	require.True(t, ok)
+	assert.Equal(t, 0, runner.RunOnce(ctx))
+
+	close(handler.release)
+	require.Eventually(t, func() bool {
+		var retries []bool
+		require.NoError(t, conn.QueryRowsCtx(ctx, &retries,
+			`SELECT visible_at BETWEEN NOW() + INTERVAL '50 seconds' AND NOW() + INTERVAL '60 seconds'
+			FROM event_queue WHERE topic = 'lease-events'`))
+		return len(retries) == 1 && retries[0]
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPostgresEventBusDeadLettersIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	conf := eventbus.Conf{Driver: eventbus.DriverPostgres, PollInterval: 10, BatchSize: 5, RetryBackoff: 10, MaxBackoff: 10,
+		Lease: 30000, MaxAttempts: 3}
+	bus := eventbus.NewPostgresBus(conn, conf, kq.KqConf{Topic: "dead-events", Processors: 1})
+	ctx := context.Background()
+	require.NoError(t, bus.Push(ctx, eventbus.Message{Value: "a"}))
+
+	consumer, err := bus.Subscribe(&blockingHandler{})
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		consumer.Start()
+		close(done)
+	}()
+	defer func() {
+		consumer.Stop()
+		<-done
+	}()
+
+	require.Eventually(t, func() bool {
+		var attempts []int
+		require.NoError(t, conn.QueryRowsCtx(ctx, &attempts,
+			`SELECT attempts FROM event_dead_letters WHERE topic = 'dead-events' AND value = 'a'`))
+		return len(attempts) == 1 && attempts[0] == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var remaining []int64
+	require.NoError(t, conn.QueryRowsCtx(ctx, &remaining, `SELECT id FROM event_queue WHERE topic = 'dead-events'`))
+	assert.Empty(t, remaining)
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-shortener/services/url-api/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListLinksKeysetIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	urls := model.NewUrlsModel(conn)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := urls.Insert(ctx, &model.Urls{
+			Id:          uuid.Must(uuid.NewV7()).String(),
+			ShortCode:   fmt.Sprintf("keyset%02d", i),
+			OriginalUrl: fmt.Sprintf("https://example.com/%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	for _, sort := range []string{"created_at", "original_url"} {
+		t.Run(sort, func(t *testing.T) {
+			all, total, err := urls.ListWithPagination(ctx, 1, 10, "", sort, "desc", "")
+			require.NoError(t, err)
+			require.Equal(t, int64(5), total)
+
+			key := func(u *model.Urls) *model.ListKey {
+				return &model.ListKey{CreatedAt: u.CreatedAt, OriginalUrl: u.OriginalUrl, Id: u.Id}
+			}
+
+			// Walking forward in pages of two visits every link once, in order
+			var walked []*model.Urls
+			var after *model.ListKey
+			for {
+				page, err := urls.ListWithKeyset(ctx, after, false, 2, "", sort, "desc", "")
+				require.NoError(t, err)
+				if len(page) == 0 {
+					break
+				}
+				walked = append(walked, page...)
+				after = key(page[len(page)-1])
+			}
+			assert.Equal(t, all, walked)
+
+			// Walking backward returns the links just before the key, in list order
+			before, err := urls.ListWithKeyset(ctx, key(all[3]), true, 2, "", sort, "desc", "")
+			require.NoError(t, err)
+			assert.Equal(t, all[1:3], before)
+		})
+	}
+}
+
+func TestClaimDueForHealthCheckIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	urls := model.NewUrlsModel(conn)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := urls.Insert(ctx, &model.Urls{
+			Id:          uuid.Must(uuid.NewV7()).String(),
+			ShortCode:   fmt.Sprintf("health%02d", i),
+			OriginalUrl: fmt.Sprintf("https://example.com/%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	lease := now.Add(10 * time.Minute)
+
+	// A second replica claiming right after the first gets the other links
+	first, err := urls.ClaimDueForHealthCheck(ctx, now, lease, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	second, err := urls.ClaimDueForHealthCheck(ctx, now, lease, 2)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.NotContains(t, []string{first[0].Id, first[1].Id}, second[0].Id)
+
+	none, err := urls.ClaimDueForHealthCheck(ctx, now, lease, 2)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	// Once the lease runs out, unchecked links are due again
+	again, err := urls.ClaimDueForHealthCheck(ctx, lease, lease.Add(10*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, again, 3)
+}
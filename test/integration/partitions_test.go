@@ -0,0 +1,117 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clicksModel "go-shortener/services/analytics-rpc/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickPartitionsIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	partitions := clicksModel.NewClickPartitionsModel(conn)
+	clicks := clicksModel.NewClicksModel(conn)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	old := current.AddDate(-2, 0, 0)
+
+	// The migration creates the current month through three months ahead
+	names, err := partitions.List(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, names, "clicks_default")
+	assert.Contains(t, names, clicksModel.ClickPartitionName(current))
+	assert.Contains(t, names, clicksModel.ClickPartitionName(current.AddDate(0, 3, 0)))
+
+	// Creating is idempotent
+	require.NoError(t, partitions.Create(ctx, old))
+	require.NoError(t, partitions.Create(ctx, old.Add(48*time.Hour)))
+
+	err = clicks.InsertWithRollups(ctx, &clicksModel.Clicks{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		ShortCode: "partitn1",
+		ClickedAt: old.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	count, err := clicks.CountInRange(ctx, "partitn1", old, old.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Dropping a partition removes its raw clicks; rollups keep the totals
+	require.NoError(t, partitions.Drop(ctx, clicksModel.ClickPartitionName(old)))
+	count, err = clicks.CountByShortCode(ctx, "partitn1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	var raw int64
+	require.NoError(t, conn.QueryRowCtx(ctx, &raw, "SELECT COUNT(*) FROM clicks WHERE short_code = 'partitn1'"))
+	assert.Equal(t, int64(0), raw)
+
+	// Clicks of a month without a partition land in the default partition
+	// and move to the partition once it is created
+	orphan := old.AddDate(0, -1, 0)
+	err = clicks.InsertWithRollups(ctx, &clicksModel.Clicks{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		ShortCode: "partitn2",
+		ClickedAt: orphan.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, partitions.Create(ctx, orphan))
+	var moved, stray int64
+	require.NoError(t, conn.QueryRowCtx(ctx, &moved,
+		`SELECT COUNT(*) FROM "`+clicksModel.ClickPartitionName(orphan)+`" WHERE short_code = 'partitn2'`))
+	assert.Equal(t, int64(1), moved)
+	require.NoError(t, conn.QueryRowCtx(ctx, &stray, "SELECT COUNT(*) FROM clicks_default"))
+	assert.Zero(t, stray)
+	names, err = partitions.List(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, names, "clicks_default", "the default partition is attached again")
+
+	// Expired clicks stranded in the default partition are archived or deleted
+	stranded := old.AddDate(-1, 0, 0)
+	err = clicks.InsertWithRollups(ctx, &clicksModel.Clicks{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		ShortCode: "partitn3",
+		ClickedAt: stranded.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	removed, err := partitions.ExpireDefault(ctx, stranded.AddDate(0, 1, 0), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	var archived int64
+	require.NoError(t, conn.QueryRowCtx(ctx, &archived,
+		"SELECT COUNT(*) FROM clicks_default_archive WHERE short_code = 'partitn3'"))
+	assert.Equal(t, int64(1), archived)
+
+	err = clicks.InsertWithRollups(ctx, &clicksModel.Clicks{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		ShortCode: "partitn4",
+		ClickedAt: stranded.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	removed, err = partitions.ExpireDefault(ctx, stranded.AddDate(0, 1, 0), false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	require.NoError(t, conn.QueryRowCtx(ctx, &stray, "SELECT COUNT(*) FROM clicks_default"))
+	assert.Zero(t, stray)
+	count, err = clicks.CountByShortCode(ctx, "partitn3")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "rollups keep the totals")
+
+	// Detaching keeps the table outside clicks
+	next := clicksModel.ClickPartitionName(current.AddDate(0, 3, 0))
+	require.NoError(t, partitions.Detach(ctx, next))
+	names, err = partitions.List(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, names, next)
+	assert.NotContains(t, names, clicksModel.ClickPartitionName(old))
+}
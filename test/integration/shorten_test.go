@@ -30,6 +30,20 @@ func setupPostgres(t *testing.T) (sqlx.SqlConn, func()) {
 			"../../services/migrations/000001_create_urls.up.sql",
 			"../../services/migrations/000002_create_clicks.up.sql",
 			"../../services/migrations/000003_add_clicks_enrichment.up.sql",
+			"../../services/migrations/000004_add_url_variants.up.sql",
+			"../../services/migrations/000005_add_urls_interstitial.up.sql",
+			"../../services/migrations/000006_add_urls_open_graph.up.sql",
+			"../../services/migrations/000007_add_urls_page_metadata.up.sql",
+			"../../services/migrations/000008_add_urls_health.up.sql",
+			"../../services/migrations/000009_add_clicks_visitor_hash.up.sql",
+			"../../services/migrations/000010_add_clicks_clicked_at_index.up.sql",
+			"../../services/migrations/000011_create_click_rollups.up.sql",
+			"../../services/migrations/000012_partition_clicks.up.sql",
+			"../../services/migrations/000013_create_event_queue.up.sql",
+			"../../services/migrations/000014_add_urls_created_at_index.up.sql",
+			"../../services/migrations/000015_add_event_queue_retries.up.sql",
+			"../../services/migrations/000016_create_click_totals.up.sql",
+			"../../services/migrations/000017_create_event_dead_letters.up.sql",
 		),
 		postgres.BasicWaitStrategies(),
 		postgres.WithSQLDriver("pgx"),
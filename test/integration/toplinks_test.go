@@ -0,0 +1,137 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clicksModel "go-shortener/services/analytics-rpc/model"
+	"go-shortener/services/url-api/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopLinksIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	clicks := clicksModel.NewClicksModel(conn)
+	urls := model.NewUrlsModel(conn)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	insert := func(shortCode, country string, clickedAt time.Time) {
+		err := clicks.InsertWithRollups(ctx, &clicksModel.Clicks{
+			Id:          uuid.Must(uuid.NewV7()).String(),
+			ShortCode:   shortCode,
+			ClickedAt:   clickedAt,
+			CountryCode: country,
+		})
+		require.NoError(t, err)
+	}
+	for i := 0; i < 3; i++ {
+		insert("aaaaaaaa", "DE", now.Add(-time.Hour))
+	}
+	insert("bbbbbbbb", "US", now.Add(-time.Hour))
+	insert("bbbbbbbb", "DE", now.Add(-time.Hour))
+	// Outside the range
+	for i := 0; i < 5; i++ {
+		insert("cccccccc", "DE", now.Add(-48*time.Hour))
+	}
+
+	top, err := clicks.TopShortCodes(ctx, now.Add(-24*time.Hour), now, nil, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "aaaaaaaa", top[0].ShortCode)
+	assert.Equal(t, int64(3), top[0].Clicks)
+	assert.Equal(t, int64(2), top[1].Clicks)
+
+	top, err = clicks.TopShortCodes(ctx, now.Add(-24*time.Hour), now, nil, 10, 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "bbbbbbbb", top[0].ShortCode)
+
+	top, err = clicks.TopShortCodes(ctx, now.Add(-24*time.Hour), now,
+		[]clicksModel.DimensionFilter{{Dimension: clicksModel.DimensionCountry, Value: "US"}}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "bbbbbbbb", top[0].ShortCode)
+
+	top, err = clicks.TopShortCodes(ctx, time.Time{}, time.Time{}, nil, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "cccccccc", top[0].ShortCode)
+
+	// Counts read from rollups match the raw clicks
+	total, err := clicks.CountInRange(ctx, "cccccccc", now.Add(-72*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	dimensions, err := clicks.CountByDimension(ctx, "bbbbbbbb", clicksModel.DimensionCountry, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, dimensions, 2)
+	buckets, err := clicks.CountByBucket(ctx, "cccccccc", clicksModel.IntervalDay, "Europe/Berlin", now.Add(-72*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	assert.Equal(t, int64(5), buckets[0].Clicks)
+
+	// A redelivered click is rejected without being counted twice
+	id := uuid.Must(uuid.NewV7()).String()
+	require.NoError(t, clicks.InsertWithRollups(ctx, &clicksModel.Clicks{Id: id, ShortCode: "dddddddd", ClickedAt: now.Add(-48 * time.Hour)}))
+	require.Error(t, clicks.InsertWithRollups(ctx, &clicksModel.Clicks{Id: id, ShortCode: "dddddddd", ClickedAt: now.Add(-48 * time.Hour)}))
+	total, err = clicks.CountByShortCode(ctx, "dddddddd")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// Batches skip already stored clicks and count the rest once
+	batch := []*clicksModel.Clicks{
+		{Id: id, ShortCode: "dddddddd", ClickedAt: now.Add(-48 * time.Hour)},
+		{Id: uuid.Must(uuid.NewV7()).String(), ShortCode: "dddddddd", ClickedAt: now.Add(-48 * time.Hour)},
+		{Id: uuid.Must(uuid.NewV7()).String(), ShortCode: "dddddddd", ClickedAt: now.Add(-47 * time.Hour)},
+	}
+	inserted, err := clicks.InsertBatchWithRollups(ctx, batch)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{batch[1].Id, batch[2].Id}, inserted)
+	total, err = clicks.CountInRange(ctx, "dddddddd", now.Add(-72*time.Hour), now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	// Batch counts cover every requested short code with clicks
+	counts, err := clicks.CountByShortCodes(ctx, []string{"aaaaaaaa", "cccccccc", "zzzzzzzz"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*clicksModel.LinkCount{
+		{ShortCode: "aaaaaaaa", Clicks: 3},
+		{ShortCode: "cccccccc", Clicks: 5},
+	}, counts)
+
+	_, err = urls.Insert(ctx, &model.Urls{Id: uuid.Must(uuid.NewV7()).String(), ShortCode: "aaaaaaaa", OriginalUrl: "https://example.com/a"})
+	require.NoError(t, err)
+	found, err := urls.FindByShortCodes(ctx, []string{"aaaaaaaa", "bbbbbbbb"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "https://example.com/a", found[0].OriginalUrl)
+	listed, err := urls.FindListedByShortCodes(ctx, []string{"aaaaaaaa", "bbbbbbbb"}, "https://other.example.com/", "")
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+	count, err := urls.CountListed(ctx, "https://example.com/", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// The ranking walks the click totals by keyset, most clicked first
+	ranking, err := clicks.RankShortCodes(ctx, false, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, ranking, 2)
+	assert.Equal(t, "cccccccc", ranking[0].ShortCode)
+	assert.Equal(t, int64(5), ranking[0].Clicks)
+	ranking, err = clicks.RankShortCodes(ctx, false, ranking[1], 2)
+	require.NoError(t, err)
+	require.Len(t, ranking, 1)
+	assert.Equal(t, "bbbbbbbb", ranking[0].ShortCode)
+	ranking, err = clicks.RankShortCodes(ctx, true, &clicksModel.LinkCount{ShortCode: "bbbbbbbb", Clicks: 2}, 10)
+	require.NoError(t, err)
+	require.Len(t, ranking, 2)
+	assert.Equal(t, "aaaaaaaa", ranking[0].ShortCode)
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-shortener/pkg/hll"
+	clicksModel "go-shortener/services/analytics-rpc/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueVisitorsIntegration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	model := clicksModel.NewClicksModel(conn)
+	ctx := context.Background()
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// 300 visitors, each clicking twice, spread over two days; plus clicks
+	// recorded before fingerprinting existed
+	local, err := hll.New(10)
+	require.NoError(t, err)
+	for i := 0; i < 300; i++ {
+		sum := sha256.Sum256([]byte(strconv.Itoa(i)))
+		hash := hex.EncodeToString(sum[:])[:32]
+		require.NoError(t, local.AddHash(hash))
+		for j := 0; j < 2; j++ {
+			err := model.InsertWithRollups(ctx, &clicksModel.Clicks{
+				Id:          uuid.Must(uuid.NewV7()).String(),
+				ShortCode:   "uniqcode",
+				ClickedAt:   day.Add(time.Duration(i%2)*24*time.Hour + time.Duration(j)*time.Minute),
+				VisitorHash: hash,
+			})
+			require.NoError(t, err)
+		}
+	}
+	err = model.InsertWithRollups(ctx, &clicksModel.Clicks{Id: uuid.Must(uuid.NewV7()).String(), ShortCode: "uniqcode", ClickedAt: day})
+	require.NoError(t, err)
+
+	unique, err := model.CountUniqueInRange(ctx, "uniqcode", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), unique)
+
+	buckets, err := model.CountUniqueByBucket(ctx, "uniqcode", clicksModel.IntervalDay, "UTC", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, int64(150), buckets[0].Visitors)
+	assert.Equal(t, int64(150), buckets[1].Visitors)
+
+	// Registers built in SQL must match the ones built in Go
+	registers, err := model.VisitorRegisters(ctx, "uniqcode", 10, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	remote, err := hll.New(10)
+	require.NoError(t, err)
+	for _, r := range registers {
+		remote.Set(int(r.Index), uint8(r.Rank))
+	}
+	assert.Equal(t, local.Estimate(), remote.Estimate())
+
+	byBucket, err := model.VisitorRegistersByBucket(ctx, "uniqcode", clicksModel.IntervalDay, "UTC", 10, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	days := map[int64]bool{}
+	for _, r := range byBucket {
+		days[r.Bucket.Unix()] = true
+	}
+	assert.Len(t, days, 2)
+}